@@ -6,19 +6,28 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/me/go-gin-auth/internal/config"
 	"github.com/me/go-gin-auth/internal/delivery/http/handler"
+	"github.com/me/go-gin-auth/internal/delivery/http/middleware"
 	"github.com/me/go-gin-auth/internal/delivery/http/router"
 	"github.com/me/go-gin-auth/internal/repository"
 	"github.com/me/go-gin-auth/internal/storage"
 	"github.com/me/go-gin-auth/internal/usecase"
 	"github.com/me/go-gin-auth/pkg/jwt"
 	"github.com/me/go-gin-auth/pkg/logger"
+	"github.com/me/go-gin-auth/pkg/mailer"
+	"github.com/me/go-gin-auth/pkg/oauth"
 	"github.com/me/go-gin-auth/pkg/password"
+	"github.com/me/go-gin-auth/pkg/redisclient"
+	"github.com/me/go-gin-auth/pkg/revocation"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+	"github.com/me/go-gin-auth/pkg/totp"
 	"github.com/me/go-gin-auth/pkg/validator"
+	"github.com/me/go-gin-auth/pkg/webhook"
 	"go.uber.org/zap"
 	gormLogger "gorm.io/gorm/logger"
 )
@@ -58,46 +67,297 @@ func main() {
 
 	appLogger.Info("Database connected successfully")
 
+	// Redis (tùy chọn): store dùng chung khi chạy nhiều instance
+	var redisClient *redisclient.Client
+	if cfg.Redis.URL != "" {
+		redisClient, err = redisclient.New(cfg.Redis.URL)
+		if err != nil {
+			appLogger.Fatal("Invalid REDIS_URL", zap.Error(err))
+		}
+		pingCtx, cancelPing := context.WithTimeout(context.Background(), 5*time.Second)
+		err = redisClient.Ping(pingCtx)
+		cancelPing()
+		if err != nil {
+			appLogger.Fatal("Failed to connect to Redis", zap.Error(err))
+		}
+		defer redisClient.Close()
+		appLogger.Info("Redis connected successfully")
+	} else if cfg.App.Env == "production" {
+		appLogger.Warn("REDIS_URL is not set: access token revocation and rate limits only apply to this instance")
+	}
+
 	// 5. Initialize services
+	keyring, err := jwt.LoadKeyring(jwt.KeyringConfig{
+		Algorithm:        cfg.JWT.Algorithm,
+		Secret:           cfg.JWT.AccessSecret,
+		PrivateKeyPath:   cfg.JWT.PrivateKeyPath,
+		KeyID:            cfg.JWT.KeyID,
+		PreviousSecrets:  cfg.JWT.PreviousAccessSecrets,
+		PreviousKeyPaths: cfg.JWT.PreviousPrivateKeyPaths,
+		KeysDir:          cfg.JWT.KeysDir,
+	})
+	if err != nil {
+		appLogger.Fatal("Failed to load JWT keyring", zap.Error(err))
+	}
+	appLogger.Info("JWT keyring loaded", zap.String("active_kid", keyring.Active().ID))
 	jwtService := jwt.NewJWTService(
-		cfg.JWT.AccessSecret,
+		keyring,
 		cfg.JWT.RefreshSecret,
 		cfg.JWT.AccessTTL,
 		cfg.JWT.RefreshTTL,
 	)
-	passwordService := password.NewPasswordService(cfg.Security.BcryptCost)
+	passwordService := password.NewPasswordService(password.Params{
+		Algorithm:         cfg.Security.PasswordHashAlgorithm,
+		BcryptCost:        cfg.Security.BcryptCost,
+		Argon2Memory:      cfg.Security.Argon2Memory,
+		Argon2Iterations:  cfg.Security.Argon2Iterations,
+		Argon2Parallelism: cfg.Security.Argon2Parallelism,
+	})
+	var breachChecker password.BreachChecker
+	if cfg.Security.BreachedPasswordsFile != "" {
+		breachChecker, err = password.NewFileBreachChecker(cfg.Security.BreachedPasswordsFile)
+		if err != nil {
+			appLogger.Fatal("Failed to open breached password file", zap.Error(err))
+		}
+	}
+	passwordPolicy := password.NewPolicy(password.PolicyConfig{
+		MinLength:      cfg.Security.PasswordMinLength,
+		MaxLength:      cfg.Security.PasswordMaxLength,
+		RequireUpper:   cfg.Security.PasswordRequireUpper,
+		RequireLower:   cfg.Security.PasswordRequireLower,
+		RequireDigit:   cfg.Security.PasswordRequireDigit,
+		RequireSymbol:  cfg.Security.PasswordRequireSymbol,
+		BannedWords:    cfg.Security.PasswordBannedWords,
+		CheckUserInfo:  cfg.Security.PasswordCheckUserInfo,
+		MinScore:       cfg.Security.PasswordMinScore,
+		BreachChecker:  breachChecker,
+		BreachMinCount: cfg.Security.BreachedPasswordsMinCount,
+	})
 	validatorService := validator.New()
+	totpService := totp.NewTOTPService(cfg.Security.MFAIssuer)
+	tokenHashService := tokenhash.NewTokenHashService(cfg.Security.TokenHashPepper)
+
+	var mailSender mailer.Sender
+	switch cfg.Mail.Driver {
+	case "smtp":
+		mailSender, err = mailer.NewSMTPSender(mailer.SMTPConfig{
+			Host:     cfg.Mail.SMTPHost,
+			Port:     cfg.Mail.SMTPPort,
+			Username: cfg.Mail.SMTPUsername,
+			Password: cfg.Mail.SMTPPassword,
+			From:     cfg.Mail.From,
+			TLSMode:  cfg.Mail.SMTPTLS,
+		})
+	case "file":
+		mailSender, err = mailer.NewFileSender(cfg.Mail.FileDir, cfg.Mail.From)
+	case "log":
+		// Log driver ghi cả nội dung email (link reset password, mã xác thực) ra log
+		if cfg.App.Env == "production" {
+			appLogger.Fatal("MAIL_DRIVER=log is not allowed in production, configure smtp")
+		}
+		mailSender = mailer.NewLogSender(appLogger)
+	default:
+		appLogger.Fatal("Unknown MAIL_DRIVER", zap.String("driver", cfg.Mail.Driver))
+	}
+	if err != nil {
+		appLogger.Fatal("Failed to initialize mail sender", zap.Error(err))
+	}
+	asyncMailSender := mailer.NewAsyncSender(mailSender, appLogger, mailer.AsyncConfig{
+		Workers:      cfg.Mail.Workers,
+		QueueSize:    cfg.Mail.QueueSize,
+		MaxRetries:   cfg.Mail.MaxRetries,
+		RetryBackoff: cfg.Mail.RetryBackoff,
+	})
+	mailService, err := mailer.NewService(asyncMailSender, cfg.Mail.AppName, cfg.Mail.LinkBaseURL)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize mail service", zap.Error(err))
+	}
+
+	// Identity provider cho đăng nhập OAuth/OIDC (OAUTH_PROVIDERS)
+	oauthHTTPClient := &http.Client{Timeout: cfg.OAuth.Timeout}
+	var oauthProviderList []oauth.Provider
+	for _, p := range cfg.OAuth.Providers {
+		provider, err := oauth.NewProvider(oauth.ProviderConfig{
+			Name:         p.Name,
+			Type:         p.Type,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  strings.TrimSuffix(cfg.OAuth.RedirectBaseURL, "/") + "/api/v1/auth/oauth/" + p.Name + "/callback",
+			Scopes:       p.Scopes,
+			Issuer:       p.Issuer,
+			AuthURL:      p.AuthURL,
+			TokenURL:     p.TokenURL,
+			APIURL:       p.APIURL,
+		}, oauthHTTPClient)
+		if err != nil {
+			appLogger.Fatal("Failed to configure OAuth provider", zap.Error(err))
+		}
+		oauthProviderList = append(oauthProviderList, provider)
+	}
+	oauthProviders := oauth.NewRegistry(oauthProviderList...)
+	appLogger.Info("OAuth providers configured", zap.Strings("providers", oauthProviders.Names()))
 
 	// 6. Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	tokenRepo := repository.NewTokenRepository(db)
 	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	emailVerificationRepo := repository.NewEmailVerificationRepository(db)
+	mfaBackupCodeRepo := repository.NewMFABackupCodeRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	passwordHistoryRepo := repository.NewPasswordHistoryRepository(db)
+	patRepo := repository.NewPersonalAccessTokenRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	identityRepo := repository.NewUserIdentityRepository(db)
+	oauthStateRepo := repository.NewOAuthStateRepository(db)
+	oauthClientRepo := repository.NewOAuthClientRepository(db)
+	oauthCodeRepo := repository.NewOAuthCodeRepository(db)
 
 	// 7. Initialize usecases
+	// Access token bị thu hồi (logout, reset password...) lưu trong memory (LRU);
+	// có REDIS_URL thì dùng chung qua Redis giữa các instance
+	var sharedRevocations revocation.Store
+	if redisClient != nil {
+		sharedRevocations = revocation.NewRedisStore(redisClient, cfg.Redis.KeyPrefix+"revocation:")
+	}
+	revocationStore := revocation.NewStore(sharedRevocations, cfg.JWT.RevocationCacheSize)
 	authUsecase := usecase.NewAuthUsecase(
 		userRepo,
 		tokenRepo,
 		passwordResetRepo,
+		emailVerificationRepo,
+		mfaBackupCodeRepo,
+		loginAttemptRepo,
+		passwordHistoryRepo,
+		roleRepo,
+		patRepo,
+		orgRepo,
+		auditRepo,
+		webhookRepo,
+		identityRepo,
+		oauthStateRepo,
 		jwtService,
+		revocationStore,
 		passwordService,
+		passwordPolicy,
+		tokenHashService,
+		totpService,
+		oauthProviders,
+		mailService,
+		appLogger,
 		cfg.JWT.AccessTTL,
 		cfg.JWT.RefreshTTL,
+		cfg.Security.RequireEmailVerification,
+		usecase.LockoutPolicy{
+			Threshold:   cfg.Security.LockoutThreshold,
+			IPThreshold: cfg.Security.LockoutIPThreshold,
+			Duration:    cfg.Security.LockoutDuration,
+			BackoffBase: cfg.Security.LoginBackoffBase,
+			BackoffMax:  cfg.Security.LoginBackoffMax,
+		},
+		cfg.Security.PasswordHistorySize,
+	)
+	userUsecase := usecase.NewUserUsecase(
+		userRepo,
+		tokenRepo,
+		passwordResetRepo,
+		loginAttemptRepo,
+		passwordHistoryRepo,
+		roleRepo,
+		patRepo,
+		auditRepo,
+		webhookRepo,
+		passwordService,
+		passwordPolicy,
+		tokenHashService,
+		revocationStore,
+		mailService,
+		appLogger,
+		cfg.JWT.AccessTTL,
+		cfg.Security.PasswordHistorySize,
+	)
+	mfaUsecase := usecase.NewMFAUsecase(userRepo, mfaBackupCodeRepo, auditRepo, totpService, passwordService, mailService, appLogger)
+	sessionUsecase := usecase.NewSessionUsecase(tokenRepo, revocationStore, cfg.JWT.AccessTTL)
+	patUsecase := usecase.NewPersonalAccessTokenUsecase(patRepo, userRepo, roleRepo, auditRepo, tokenHashService, appLogger)
+	roleUsecase := usecase.NewRoleUsecase(roleRepo, userRepo, auditRepo, revocationStore, appLogger, cfg.JWT.AccessTTL)
+	auditUsecase := usecase.NewAuditUsecase(auditRepo)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepo, auditRepo, appLogger)
+	oauthClientUsecase := usecase.NewOAuthClientUsecase(oauthClientRepo, roleRepo, auditRepo, tokenHashService, revocationStore, appLogger, cfg.JWT.AccessTTL)
+	oauthServerUsecase := usecase.NewOAuthServerUsecase(
+		oauthClientRepo,
+		oauthCodeRepo,
+		userRepo,
+		tokenRepo,
+		auditRepo,
+		jwtService,
+		revocationStore,
+		tokenHashService,
+		appLogger,
+		cfg.OIDC.Issuer,
+		cfg.OIDC.CodeTTL,
+		cfg.JWT.AccessTTL,
 	)
-	userUsecase := usecase.NewUserUsecase(userRepo, passwordService)
+	if keyring.Active().IsSymmetric() {
+		appLogger.Warn("JWT signing key is symmetric, the OpenID Connect provider rejects the openid scope", zap.String("algorithm", keyring.Active().Algorithm))
+	}
+
+	// Worker gửi webhook nền (hàng đợi nằm trong database)
+	webhookWorker := usecase.NewWebhookWorker(webhookRepo, webhook.NewClient(cfg.Webhook.Timeout, cfg.Mail.AppName+"-webhooks"), appLogger, usecase.WebhookWorkerConfig{
+		PollInterval:    cfg.Webhook.PollInterval,
+		BatchSize:       cfg.Webhook.BatchSize,
+		MaxAttempts:     cfg.Webhook.MaxAttempts,
+		RetryBackoff:    cfg.Webhook.RetryBackoff,
+		RetryBackoffMax: cfg.Webhook.RetryBackoffMax,
+		SendTimeout:     cfg.Webhook.Timeout,
+	})
+	webhookWorker.Start()
+	orgUsecase := usecase.NewOrganizationUsecase(orgRepo, userRepo, tokenHashService, mailService, appLogger)
 
 	// 8. Initialize handlers
 	authHandler := handler.NewAuthHandler(authUsecase, validatorService)
 	userHandler := handler.NewUserHandler(userUsecase, validatorService)
+	mfaHandler := handler.NewMFAHandler(mfaUsecase, validatorService)
+	sessionHandler := handler.NewSessionHandler(sessionUsecase)
+	tokenHandler := handler.NewPersonalAccessTokenHandler(patUsecase, validatorService)
+	roleHandler := handler.NewRoleHandler(roleUsecase, validatorService)
+	orgHandler := handler.NewOrganizationHandler(orgUsecase, authUsecase, validatorService)
+	auditHandler := handler.NewAuditHandler(auditUsecase, validatorService)
+	webhookHandler := handler.NewWebhookHandler(webhookUsecase, validatorService)
+	oauthHandler := handler.NewOAuthHandler(authUsecase, validatorService, cfg.App.Env == "production")
+	oauthServerHandler := handler.NewOAuthServerHandler(oauthServerUsecase, validatorService, cfg.OIDC.ConsentURL)
+	oauthClientHandler := handler.NewOAuthClientHandler(oauthClientUsecase, validatorService)
 	healthHandler := handler.NewHealthHandler(db)
+	wellKnownHandler := handler.NewWellKnownHandler(jwtService, oauthServerUsecase)
 
 	// 9. Initialize router
+	// Rate limit dùng Redis khi có REDIS_URL để giới hạn chung cho mọi instance
+	rateLimitStore := middleware.NewMemoryRateLimitStore()
+	if redisClient != nil {
+		rateLimitStore = middleware.NewRedisRateLimitStore(redisClient, cfg.Redis.KeyPrefix+"ratelimit:")
+	}
 	r := router.NewRouter(&router.RouterConfig{
-		AuthHandler:   authHandler,
-		UserHandler:   userHandler,
-		HealthHandler: healthHandler,
-		JWTService:    jwtService,
-		Logger:        appLogger,
-		Config:        cfg,
+		AuthHandler:      authHandler,
+		UserHandler:      userHandler,
+		MFAHandler:       mfaHandler,
+		SessionHandler:   sessionHandler,
+		TokenHandler:     tokenHandler,
+		RoleHandler:      roleHandler,
+		OrgHandler:       orgHandler,
+		AuditHandler:     auditHandler,
+		WebhookHandler:   webhookHandler,
+		OAuthHandler:     oauthHandler,
+		OAuthServer:      oauthServerHandler,
+		OAuthClient:      oauthClientHandler,
+		HealthHandler:    healthHandler,
+		WellKnownHandler: wellKnownHandler,
+		JWTService:       jwtService,
+		PATAuthenticator: patUsecase,
+		RateLimitStore:   rateLimitStore,
+		RevocationStore:  revocationStore,
+		Logger:           appLogger,
+		Config:           cfg,
 	})
 
 	// 10. Create HTTP server
@@ -114,23 +374,46 @@ func main() {
 		}
 	}()
 
-	// 12. Wait for interrupt signal to gracefully shutdown
+	// 12. SIGHUP -> đọc lại thư mục keyring (sau khi promote key mới bằng cmd/keyctl)
+	if cfg.JWT.KeysDir != "" {
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
+		go func() {
+			for range reload {
+				if err := keyring.Reload(); err != nil {
+					appLogger.Error("Failed to reload JWT keyring", zap.Error(err))
+					continue
+				}
+				appLogger.Info("JWT keyring reloaded", zap.String("active_kid", keyring.Active().ID))
+			}
+		}()
+	}
+
+	// 13. Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	appLogger.Info("Shutting down server...")
 
-	// 13. Graceful shutdown với timeout
+	// 14. Graceful shutdown với timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// 14. Shutdown server
+	// 15. Shutdown server
 	if err := srv.Shutdown(ctx); err != nil {
 		appLogger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
-	// 15. Close database connection
+	// 16. Gửi nốt email còn trong hàng đợi, chờ batch webhook đang gửi
+	if err := asyncMailSender.Close(ctx); err != nil {
+		appLogger.Warn("Mail queue not fully drained", zap.Error(err))
+	}
+	if err := webhookWorker.Close(ctx); err != nil {
+		appLogger.Warn("Webhook worker did not stop in time", zap.Error(err))
+	}
+
+	// 17. Close database connection
 	sqlDB, err := db.DB()
 	if err == nil {
 		sqlDB.Close()
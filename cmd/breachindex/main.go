@@ -0,0 +1,25 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/me/go-gin-auth/pkg/password"
+)
+
+// Tạo file index <file>.idx cho file Pwned Passwords (SHA-1, ordered by hash)
+// để API tra cứu password bị lộ mà không phải binary search trên toàn bộ file
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("Usage: breachindex <pwned-passwords-sha1-ordered-by-hash.txt>")
+	}
+	path := os.Args[1]
+
+	log.Printf("Building index for %s...", path)
+	start := time.Now()
+	if err := password.BuildBreachIndex(path); err != nil {
+		log.Fatal("Failed to build index:", err)
+	}
+	log.Printf("Index written to %s.idx in %s", path, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/me/go-gin-auth/internal/config"
+	"github.com/me/go-gin-auth/pkg/jwt"
+)
+
+const usage = `Usage: keyctl [-dir DIR] <command> [args]
+
+Commands:
+  generate [-alg RS256|ES256|EdDSA|HS256] [-kid KID]  Tạo key mới (chưa active)
+  promote <kid>                                       Chuyển key sang active
+  list                                                Liệt kê key trong keyring
+  remove <kid>                                        Xóa key đã nghỉ
+
+Sau khi promote/remove, gửi SIGHUP cho API (kill -HUP <pid>) để nạp lại keyring.
+Giữ key cũ ít nhất bằng JWT_ACCESS_TTL trước khi remove.
+`
+
+func main() {
+	dir := flag.String("dir", "", "keyring directory (default: JWT_KEYS_DIR)")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if *dir == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatal("Config load failed:", err)
+		}
+		*dir = cfg.JWT.KeysDir
+	}
+	if *dir == "" {
+		log.Fatal("Keyring directory is required: set JWT_KEYS_DIR or pass -dir")
+	}
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch args[0] {
+	case "generate":
+		err = generate(*dir, args[1:])
+	case "promote":
+		err = promote(*dir, args[1:])
+	case "list":
+		err = list(*dir)
+	case "remove":
+		err = remove(*dir, args[1:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate - tạo key mới và lưu vào thư mục keyring
+func generate(dir string, args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	alg := fs.String("alg", jwt.AlgorithmRS256, "signing algorithm")
+	kid := fs.String("kid", "", "key id (default: derived from key)")
+	_ = fs.Parse(args)
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create keyring dir: %w", err)
+	}
+
+	// HS256: secret ngẫu nhiên 32 bytes
+	if *alg == jwt.AlgorithmHS256 {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return err
+		}
+		secret := base64.RawURLEncoding.EncodeToString(buf)
+		if *kid == "" {
+			*kid = jwt.HMACKeyID(secret)
+		}
+		if err := jwt.WriteKeyFile(dir, *kid, []byte(secret+"\n"), true); err != nil {
+			return err
+		}
+		log.Printf("Generated %s key %s", *alg, *kid)
+		return nil
+	}
+
+	var private interface{}
+	var err error
+	switch *alg {
+	case jwt.AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case jwt.AlgorithmES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case jwt.AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return fmt.Errorf("unsupported algorithm: %s", *alg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return err
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	// kid mặc định = thumbprint RFC 7638
+	key, err := jwt.ParsePrivateKeyPEM(*alg, pemBytes, *kid)
+	if err != nil {
+		return err
+	}
+	if err := jwt.WriteKeyFile(dir, key.ID, pemBytes, false); err != nil {
+		return err
+	}
+
+	log.Printf("Generated %s key %s", *alg, key.ID)
+	return nil
+}
+
+// promote - chuyển key sang active
+func promote(dir string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: keyctl promote <kid>")
+	}
+	id := args[0]
+
+	keys, _, err := jwt.ReadKeyDir(dir)
+	if err != nil {
+		return err
+	}
+	if !containsKey(keys, id) {
+		return fmt.Errorf("key %s not found", id)
+	}
+
+	if err := jwt.SetActiveKey(dir, id); err != nil {
+		return err
+	}
+	log.Printf("Key %s is now active. Send SIGHUP to the API to reload.", id)
+	return nil
+}
+
+// list - liệt kê key trong keyring
+func list(dir string) error {
+	keys, activeID, err := jwt.ReadKeyDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		marker := " "
+		if key.ID == activeID {
+			marker = "*"
+		}
+		fmt.Printf("%s %-8s %s\n", marker, key.Algorithm, key.ID)
+	}
+	return nil
+}
+
+// remove - xóa key đã nghỉ (không cho xóa key đang active)
+func remove(dir string, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: keyctl remove <kid>")
+	}
+	id := args[0]
+
+	_, activeID, err := jwt.ReadKeyDir(dir)
+	if err != nil {
+		return err
+	}
+	if id == activeID {
+		return fmt.Errorf("cannot remove active key %s, promote another key first", id)
+	}
+
+	if err := jwt.RemoveKeyFile(dir, id); err != nil {
+		return err
+	}
+	log.Printf("Removed key %s. Send SIGHUP to the API to reload.", id)
+	return nil
+}
+
+func containsKey(keys []*jwt.SigningKey, id string) bool {
+	for _, key := range keys {
+		if key.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -16,12 +17,22 @@ type Config struct {
 	CORS      CORSConfig      `mapstructure:"cors"`
 	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
 	Security  SecurityConfig  `mapstructure:"security"`
+	Mail      MailConfig      `mapstructure:"mail"`
+	Webhook   WebhookConfig   `mapstructure:"webhook"`
+	OAuth     OAuthConfig     `mapstructure:"oauth"`
+	OIDC      OIDCConfig      `mapstructure:"oidc"`
+	Redis     RedisConfig     `mapstructure:"redis"`
 }
 
 // AppConfig - cài đặt app chung
 type AppConfig struct {
-	Port string `mapstructure:"port"`
-	Env  string `mapstructure:"env"`
+	Port        string `mapstructure:"port"`
+	Env         string `mapstructure:"env"`
+	MaxBodySize int64  `mapstructure:"max_body_size"` // Kích thước request body tối đa (bytes, <= 0 là không giới hạn)
+
+	// IP/CIDR của reverse proxy được tin X-Forwarded-For / X-Real-IP (rỗng = không tin proxy nào, dùng IP kết nối)
+	// Sai cấu hình sẽ để client tự khai IP, vượt rate limit / lockout theo IP và làm giả IP trong audit log
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig - cài đặt database
@@ -35,10 +46,26 @@ type DatabaseConfig struct {
 
 // JWTConfig - cài đặt JWT
 type JWTConfig struct {
-	AccessSecret  string        `mapstructure:"access_secret"`
-	RefreshSecret string        `mapstructure:"refresh_secret"`
-	AccessTTL     time.Duration `mapstructure:"access_ttl"`
-	RefreshTTL    time.Duration `mapstructure:"refresh_ttl"`
+	AccessSecret   string        `mapstructure:"access_secret"`
+	RefreshSecret  string        `mapstructure:"refresh_secret"`
+	AccessTTL      time.Duration `mapstructure:"access_ttl"`
+	RefreshTTL     time.Duration `mapstructure:"refresh_ttl"`
+	Algorithm      string        `mapstructure:"algorithm"`        // HS256 | RS256 | ES256 | EdDSA (cho access token)
+	PrivateKeyPath string        `mapstructure:"private_key_path"` // File PEM, bắt buộc khi không dùng HS256
+	KeyID          string        `mapstructure:"key_id"`           // kid trong header, rỗng -> tự tính
+
+	// Key rotation: key cũ vẫn verify được token đã phát hành cho đến khi hết hạn
+	PreviousAccessSecrets   []string `mapstructure:"previous_access_secrets"`    // Secret HS256 cũ
+	PreviousPrivateKeyPaths []string `mapstructure:"previous_private_key_paths"` // Private key cũ
+	KeysDir                 string   `mapstructure:"keys_dir"`                   // Thư mục keyring (quản lý bằng cmd/keyctl)
+
+	RevocationCacheSize int `mapstructure:"revocation_cache_size"` // Số access token / mốc thu hồi tối đa giữ trong memory
+}
+
+// RedisConfig - Redis dùng chung khi chạy nhiều instance
+type RedisConfig struct {
+	URL       string `mapstructure:"url"`        // redis://[[user]:password@]host:port/db hoặc rediss://, rỗng = chỉ dùng memory (1 instance)
+	KeyPrefix string `mapstructure:"key_prefix"` // Prefix cho mọi key (nhiều app dùng chung 1 Redis)
 }
 
 // LogConfig - cài đặt logging
@@ -53,13 +80,105 @@ type CORSConfig struct {
 
 // RateLimitConfig - cài đặt rate limiting
 type RateLimitConfig struct {
-	Requests int           `mapstructure:"requests"`
+	Requests int           `mapstructure:"requests"` // Giới hạn chung cho /api/v1 (theo IP)
 	Window   time.Duration `mapstructure:"window"`
+
+	LoginRequests int           `mapstructure:"login_requests"` // Giới hạn riêng cho /auth/login, /auth/mfa/verify
+	LoginWindow   time.Duration `mapstructure:"login_window"`
+
+	ForgotPasswordRequests int           `mapstructure:"forgot_password_requests"` // Giới hạn riêng cho forgot/reset password, verify/resend email
+	ForgotPasswordWindow   time.Duration `mapstructure:"forgot_password_window"`
 }
 
 // SecurityConfig - cài đặt bảo mật
 type SecurityConfig struct {
-	BcryptCost int `mapstructure:"bcrypt_cost"`
+	PasswordHashAlgorithm string `mapstructure:"password_hash_algorithm"` // argon2id | bcrypt (cho hash mới)
+	BcryptCost            int    `mapstructure:"bcrypt_cost"`
+	Argon2Memory          uint32 `mapstructure:"argon2_memory"`      // KiB
+	Argon2Iterations      uint32 `mapstructure:"argon2_iterations"`  // Số vòng lặp
+	Argon2Parallelism     uint8  `mapstructure:"argon2_parallelism"` // Số luồng
+
+	// Password policy
+	PasswordMinLength     int      `mapstructure:"password_min_length"`
+	PasswordMaxLength     int      `mapstructure:"password_max_length"`
+	PasswordRequireUpper  bool     `mapstructure:"password_require_upper"`
+	PasswordRequireLower  bool     `mapstructure:"password_require_lower"`
+	PasswordRequireDigit  bool     `mapstructure:"password_require_digit"`
+	PasswordRequireSymbol bool     `mapstructure:"password_require_symbol"`
+	PasswordBannedWords   []string `mapstructure:"password_banned_words"`    // Từ cấm thêm, vd tên sản phẩm/công ty
+	PasswordCheckUserInfo bool     `mapstructure:"password_check_user_info"` // Không cho chứa email/họ tên
+	PasswordMinScore      int      `mapstructure:"password_min_score"`       // Độ mạnh tối thiểu 0-4
+
+	BreachedPasswordsFile     string `mapstructure:"breached_passwords_file"`      // File Pwned Passwords (SHA-1, sắp xếp theo hash), rỗng = tắt
+	BreachedPasswordsMinCount int    `mapstructure:"breached_passwords_min_count"` // Số lần xuất hiện tối thiểu để từ chối
+
+	PasswordHistorySize      int    `mapstructure:"password_history_size"`      // Số password gần nhất không được dùng lại (0 = tắt)
+	RequireEmailVerification bool   `mapstructure:"require_email_verification"` // Chặn login khi email chưa xác thực
+	MFAIssuer                string `mapstructure:"mfa_issuer"`                 // Tên hiển thị trong app authenticator
+	TokenHashPepper          string `mapstructure:"token_hash_pepper"`          // Secret HMAC khi băm refresh/reset token, rỗng -> SHA-256
+
+	LockoutThreshold   int           `mapstructure:"lockout_threshold"`    // Số lần login sai liên tiếp (theo email) trước khi khóa
+	LockoutIPThreshold int           `mapstructure:"lockout_ip_threshold"` // Số lần login sai liên tiếp (theo IP) trước khi khóa
+	LockoutDuration    time.Duration `mapstructure:"lockout_duration"`     // Thời gian khóa tạm thời
+	LoginBackoffBase   time.Duration `mapstructure:"login_backoff_base"`   // Delay sau lần sai đầu tiên, nhân đôi mỗi lần
+	LoginBackoffMax    time.Duration `mapstructure:"login_backoff_max"`    // Delay tối đa
+}
+
+// MailConfig - cài đặt gửi email
+type MailConfig struct {
+	Driver      string `mapstructure:"driver"`        // log (không dùng được khi APP_ENV=production) | file | smtp
+	From        string `mapstructure:"from"`          // "Tên <email>" hoặc "email"
+	AppName     string `mapstructure:"app_name"`      // Tên hiển thị trong nội dung email
+	LinkBaseURL string `mapstructure:"link_base_url"` // URL frontend cho link reset/verify trong email
+	FileDir     string `mapstructure:"file_dir"`      // Thư mục lưu file .eml (driver=file)
+
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     string `mapstructure:"smtp_port"`
+	SMTPUsername string `mapstructure:"smtp_username"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+	SMTPTLS      string `mapstructure:"smtp_tls"` // starttls | tls | none
+
+	Workers      int           `mapstructure:"workers"`       // Số worker gửi nền
+	QueueSize    int           `mapstructure:"queue_size"`    // Số email tối đa trong hàng đợi
+	MaxRetries   int           `mapstructure:"max_retries"`   // Số lần thử lại khi gửi lỗi
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // Chờ trước lần retry đầu, nhân đôi mỗi lần
+}
+
+// WebhookConfig - cài đặt gửi webhook
+type WebhookConfig struct {
+	Timeout         time.Duration `mapstructure:"timeout"`           // Timeout mỗi lần gọi endpoint
+	PollInterval    time.Duration `mapstructure:"poll_interval"`     // Chu kỳ quét hàng đợi
+	BatchSize       int           `mapstructure:"batch_size"`        // Số delivery gửi song song mỗi lần
+	MaxAttempts     int           `mapstructure:"max_attempts"`      // Số lần gửi tối đa
+	RetryBackoff    time.Duration `mapstructure:"retry_backoff"`     // Chờ trước lần retry đầu, nhân đôi mỗi lần
+	RetryBackoffMax time.Duration `mapstructure:"retry_backoff_max"` // Thời gian chờ tối đa giữa 2 lần retry
+}
+
+// OAuthConfig - đăng nhập bằng identity provider bên ngoài (Google, GitHub...)
+type OAuthConfig struct {
+	RedirectBaseURL string                `mapstructure:"redirect_base_url"` // URL public của API, callback = {base}/api/v1/auth/oauth/{provider}/callback
+	Timeout         time.Duration         `mapstructure:"timeout"`           // Timeout khi gọi provider
+	Providers       []OAuthProviderConfig `mapstructure:"providers"`
+}
+
+// OAuthProviderConfig - cài đặt 1 provider (đọc từ OAUTH_<NAME>_*)
+type OAuthProviderConfig struct {
+	Name         string   `mapstructure:"name"`
+	Type         string   `mapstructure:"type"` // oidc | github
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	Issuer       string   `mapstructure:"issuer"` // OIDC issuer (endpoint lấy qua discovery)
+	Scopes       []string `mapstructure:"scopes"` // Rỗng -> scope mặc định
+	AuthURL      string   `mapstructure:"auth_url"`
+	TokenURL     string   `mapstructure:"token_url"`
+	APIURL       string   `mapstructure:"api_url"` // GitHub Enterprise
+}
+
+// OIDCConfig - authorization server (OpenID Connect provider) cho các app nội bộ
+type OIDCConfig struct {
+	Issuer     string        `mapstructure:"issuer"`      // URL public của service, là claim iss và gốc của các endpoint /oauth/*
+	ConsentURL string        `mapstructure:"consent_url"` // Trang frontend đăng nhập + hỏi consent, nhận nguyên query của /oauth/authorize
+	CodeTTL    time.Duration `mapstructure:"code_ttl"`    // Thời gian sống của authorization code
 }
 
 // Load - đọc config từ file .env
@@ -70,11 +189,60 @@ func Load() (*Config, error) {
 	// Giá trị mặc định (nếu không có trong .env)
 	viper.SetDefault("APP_PORT", "8080")
 	viper.SetDefault("APP_ENV", "development")
+	viper.SetDefault("APP_MAX_BODY_SIZE", 1<<20)
 	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("JWT_ALGORITHM", "HS256")
+	viper.SetDefault("JWT_REVOCATION_CACHE_SIZE", 100000)
+	viper.SetDefault("REDIS_KEY_PREFIX", "go-gin-auth:")
 	viper.SetDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000")
 	viper.SetDefault("RATE_LIMIT_REQUESTS", 100)
 	viper.SetDefault("RATE_LIMIT_WINDOW", "1m")
+	viper.SetDefault("RATE_LIMIT_LOGIN_REQUESTS", 5)
+	viper.SetDefault("RATE_LIMIT_LOGIN_WINDOW", "1m")
+	viper.SetDefault("RATE_LIMIT_FORGOT_PASSWORD_REQUESTS", 3)
+	viper.SetDefault("RATE_LIMIT_FORGOT_PASSWORD_WINDOW", "15m")
+	viper.SetDefault("PASSWORD_HASH_ALGORITHM", "argon2id")
 	viper.SetDefault("BCRYPT_COST", 12)
+	viper.SetDefault("ARGON2_MEMORY", 65536)
+	viper.SetDefault("ARGON2_ITERATIONS", 3)
+	viper.SetDefault("ARGON2_PARALLELISM", 2)
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	viper.SetDefault("PASSWORD_MAX_LENGTH", 128)
+	viper.SetDefault("PASSWORD_CHECK_USER_INFO", true)
+	viper.SetDefault("PASSWORD_MIN_SCORE", 2)
+	viper.SetDefault("BREACHED_PASSWORDS_MIN_COUNT", 1)
+	viper.SetDefault("PASSWORD_HISTORY_SIZE", 5)
+	viper.SetDefault("REQUIRE_EMAIL_VERIFICATION", false)
+	viper.SetDefault("MFA_ISSUER", "go-gin-auth")
+	viper.SetDefault("LOCKOUT_THRESHOLD", 5)
+	viper.SetDefault("LOCKOUT_IP_THRESHOLD", 50)
+	viper.SetDefault("LOCKOUT_DURATION", "15m")
+	viper.SetDefault("LOGIN_BACKOFF_BASE", "1s")
+	viper.SetDefault("LOGIN_BACKOFF_MAX", "1m")
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_FROM", "go-gin-auth <no-reply@localhost>")
+	viper.SetDefault("MAIL_APP_NAME", "go-gin-auth")
+	viper.SetDefault("MAIL_LINK_BASE_URL", "http://localhost:3000")
+	viper.SetDefault("MAIL_FILE_DIR", "./tmp/mail")
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("SMTP_TLS", "starttls")
+	viper.SetDefault("MAIL_WORKERS", 2)
+	viper.SetDefault("MAIL_QUEUE_SIZE", 100)
+	viper.SetDefault("MAIL_MAX_RETRIES", 3)
+	viper.SetDefault("MAIL_RETRY_BACKOFF", "2s")
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", "5s")
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 20)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_RETRY_BACKOFF", "30s")
+	viper.SetDefault("WEBHOOK_RETRY_BACKOFF_MAX", "6h")
+	viper.SetDefault("OAUTH_REDIRECT_BASE_URL", "http://localhost:8080")
+	viper.SetDefault("OAUTH_TIMEOUT", "10s")
+	viper.SetDefault("OAUTH_GOOGLE_ISSUER", "https://accounts.google.com")
+	viper.SetDefault("OAUTH_GITHUB_TYPE", "github")
+	viper.SetDefault("OIDC_ISSUER", "http://localhost:8080")
+	viper.SetDefault("OIDC_CONSENT_URL", "http://localhost:3000/oauth/consent")
+	viper.SetDefault("OIDC_CODE_TTL", "1m")
 
 	// Đọc file .env (optional - nếu không có hoặc không đọc được thì skip)
 	if err := viper.ReadInConfig(); err != nil {
@@ -86,8 +254,10 @@ func Load() (*Config, error) {
 	// Tạo struct config từ các giá trị đã đọc
 	config := &Config{
 		App: AppConfig{
-			Port: viper.GetString("APP_PORT"),
-			Env:  viper.GetString("APP_ENV"),
+			Port:           viper.GetString("APP_PORT"),
+			Env:            viper.GetString("APP_ENV"),
+			MaxBodySize:    viper.GetInt64("APP_MAX_BODY_SIZE"),
+			TrustedProxies: splitList(viper.GetString("TRUSTED_PROXIES")),
 		},
 		Database: DatabaseConfig{
 			Host:     viper.GetString("DB_HOST"),
@@ -97,10 +267,17 @@ func Load() (*Config, error) {
 			Name:     viper.GetString("DB_NAME"),
 		},
 		JWT: JWTConfig{
-			AccessSecret:  viper.GetString("JWT_ACCESS_SECRET"),
-			RefreshSecret: viper.GetString("JWT_REFRESH_SECRET"),
-			AccessTTL:     viper.GetDuration("JWT_ACCESS_TTL"),
-			RefreshTTL:    viper.GetDuration("JWT_REFRESH_TTL"),
+			AccessSecret:            viper.GetString("JWT_ACCESS_SECRET"),
+			RefreshSecret:           viper.GetString("JWT_REFRESH_SECRET"),
+			AccessTTL:               viper.GetDuration("JWT_ACCESS_TTL"),
+			RefreshTTL:              viper.GetDuration("JWT_REFRESH_TTL"),
+			Algorithm:               viper.GetString("JWT_ALGORITHM"),
+			PrivateKeyPath:          viper.GetString("JWT_PRIVATE_KEY_PATH"),
+			KeyID:                   viper.GetString("JWT_KEY_ID"),
+			PreviousAccessSecrets:   viper.GetStringSlice("JWT_PREVIOUS_ACCESS_SECRETS"),
+			PreviousPrivateKeyPaths: viper.GetStringSlice("JWT_PREVIOUS_PRIVATE_KEY_PATHS"),
+			KeysDir:                 viper.GetString("JWT_KEYS_DIR"),
+			RevocationCacheSize:     viper.GetInt("JWT_REVOCATION_CACHE_SIZE"),
 		},
 		Log: LogConfig{
 			Level: viper.GetString("LOG_LEVEL"),
@@ -109,13 +286,108 @@ func Load() (*Config, error) {
 			AllowedOrigins: viper.GetStringSlice("CORS_ALLOWED_ORIGINS"),
 		},
 		RateLimit: RateLimitConfig{
-			Requests: viper.GetInt("RATE_LIMIT_REQUESTS"),
-			Window:   viper.GetDuration("RATE_LIMIT_WINDOW"),
+			Requests:               viper.GetInt("RATE_LIMIT_REQUESTS"),
+			Window:                 viper.GetDuration("RATE_LIMIT_WINDOW"),
+			LoginRequests:          viper.GetInt("RATE_LIMIT_LOGIN_REQUESTS"),
+			LoginWindow:            viper.GetDuration("RATE_LIMIT_LOGIN_WINDOW"),
+			ForgotPasswordRequests: viper.GetInt("RATE_LIMIT_FORGOT_PASSWORD_REQUESTS"),
+			ForgotPasswordWindow:   viper.GetDuration("RATE_LIMIT_FORGOT_PASSWORD_WINDOW"),
 		},
 		Security: SecurityConfig{
-			BcryptCost: viper.GetInt("BCRYPT_COST"),
+			PasswordHashAlgorithm:     viper.GetString("PASSWORD_HASH_ALGORITHM"),
+			BcryptCost:                viper.GetInt("BCRYPT_COST"),
+			Argon2Memory:              viper.GetUint32("ARGON2_MEMORY"),
+			Argon2Iterations:          viper.GetUint32("ARGON2_ITERATIONS"),
+			Argon2Parallelism:         uint8(viper.GetUint("ARGON2_PARALLELISM")),
+			PasswordMinLength:         viper.GetInt("PASSWORD_MIN_LENGTH"),
+			PasswordMaxLength:         viper.GetInt("PASSWORD_MAX_LENGTH"),
+			PasswordRequireUpper:      viper.GetBool("PASSWORD_REQUIRE_UPPER"),
+			PasswordRequireLower:      viper.GetBool("PASSWORD_REQUIRE_LOWER"),
+			PasswordRequireDigit:      viper.GetBool("PASSWORD_REQUIRE_DIGIT"),
+			PasswordRequireSymbol:     viper.GetBool("PASSWORD_REQUIRE_SYMBOL"),
+			PasswordBannedWords:       viper.GetStringSlice("PASSWORD_BANNED_WORDS"),
+			PasswordCheckUserInfo:     viper.GetBool("PASSWORD_CHECK_USER_INFO"),
+			PasswordMinScore:          viper.GetInt("PASSWORD_MIN_SCORE"),
+			BreachedPasswordsFile:     viper.GetString("BREACHED_PASSWORDS_FILE"),
+			BreachedPasswordsMinCount: viper.GetInt("BREACHED_PASSWORDS_MIN_COUNT"),
+			PasswordHistorySize:       viper.GetInt("PASSWORD_HISTORY_SIZE"),
+			RequireEmailVerification:  viper.GetBool("REQUIRE_EMAIL_VERIFICATION"),
+			MFAIssuer:                 viper.GetString("MFA_ISSUER"),
+			TokenHashPepper:           viper.GetString("TOKEN_HASH_PEPPER"),
+			LockoutThreshold:          viper.GetInt("LOCKOUT_THRESHOLD"),
+			LockoutIPThreshold:        viper.GetInt("LOCKOUT_IP_THRESHOLD"),
+			LockoutDuration:           viper.GetDuration("LOCKOUT_DURATION"),
+			LoginBackoffBase:          viper.GetDuration("LOGIN_BACKOFF_BASE"),
+			LoginBackoffMax:           viper.GetDuration("LOGIN_BACKOFF_MAX"),
+		},
+		Mail: MailConfig{
+			Driver:       viper.GetString("MAIL_DRIVER"),
+			From:         viper.GetString("MAIL_FROM"),
+			AppName:      viper.GetString("MAIL_APP_NAME"),
+			LinkBaseURL:  viper.GetString("MAIL_LINK_BASE_URL"),
+			FileDir:      viper.GetString("MAIL_FILE_DIR"),
+			SMTPHost:     viper.GetString("SMTP_HOST"),
+			SMTPPort:     viper.GetString("SMTP_PORT"),
+			SMTPUsername: viper.GetString("SMTP_USERNAME"),
+			SMTPPassword: viper.GetString("SMTP_PASSWORD"),
+			SMTPTLS:      viper.GetString("SMTP_TLS"),
+			Workers:      viper.GetInt("MAIL_WORKERS"),
+			QueueSize:    viper.GetInt("MAIL_QUEUE_SIZE"),
+			MaxRetries:   viper.GetInt("MAIL_MAX_RETRIES"),
+			RetryBackoff: viper.GetDuration("MAIL_RETRY_BACKOFF"),
+		},
+		Webhook: WebhookConfig{
+			Timeout:         viper.GetDuration("WEBHOOK_TIMEOUT"),
+			PollInterval:    viper.GetDuration("WEBHOOK_POLL_INTERVAL"),
+			BatchSize:       viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			MaxAttempts:     viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			RetryBackoff:    viper.GetDuration("WEBHOOK_RETRY_BACKOFF"),
+			RetryBackoffMax: viper.GetDuration("WEBHOOK_RETRY_BACKOFF_MAX"),
+		},
+		OAuth: OAuthConfig{
+			RedirectBaseURL: viper.GetString("OAUTH_REDIRECT_BASE_URL"),
+			Timeout:         viper.GetDuration("OAUTH_TIMEOUT"),
+			Providers:       loadOAuthProviders(),
+		},
+		OIDC: OIDCConfig{
+			Issuer:     viper.GetString("OIDC_ISSUER"),
+			ConsentURL: viper.GetString("OIDC_CONSENT_URL"),
+			CodeTTL:    viper.GetDuration("OIDC_CODE_TTL"),
+		},
+		Redis: RedisConfig{
+			URL:       viper.GetString("REDIS_URL"),
+			KeyPrefix: viper.GetString("REDIS_KEY_PREFIX"),
 		},
 	}
 
 	return config, nil
 }
+
+// loadOAuthProviders - đọc các provider liệt kê trong OAUTH_PROVIDERS (vd: google,github)
+func loadOAuthProviders() []OAuthProviderConfig {
+	var providers []OAuthProviderConfig
+	for _, name := range splitList(viper.GetString("OAUTH_PROVIDERS")) {
+		name = strings.ToLower(name)
+		prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+
+		providers = append(providers, OAuthProviderConfig{
+			Name:         name,
+			Type:         viper.GetString(prefix + "TYPE"),
+			ClientID:     viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "CLIENT_SECRET"),
+			Issuer:       viper.GetString(prefix + "ISSUER"),
+			Scopes:       splitList(viper.GetString(prefix + "SCOPES")),
+			AuthURL:      viper.GetString(prefix + "AUTH_URL"),
+			TokenURL:     viper.GetString(prefix + "TOKEN_URL"),
+			APIURL:       viper.GetString(prefix + "API_URL"),
+		})
+	}
+	return providers
+}
+
+// splitList - tách danh sách phân cách bằng dấu phẩy hoặc khoảng trắng
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
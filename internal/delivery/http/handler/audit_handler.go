@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+// AuditHandler - xử lý các API xem audit log (chỉ admin)
+type AuditHandler struct {
+	auditUsecase usecase.AuditUsecase
+	validator    *validator.Validator
+}
+
+// NewAuditHandler - tạo audit handler mới
+func NewAuditHandler(auditUsecase usecase.AuditUsecase, validator *validator.Validator) *AuditHandler {
+	return &AuditHandler{
+		auditUsecase: auditUsecase,
+		validator:    validator,
+	}
+}
+
+// ListLogs - API lấy danh sách audit log
+// GET /api/v1/audit-logs?actor_id=1&action=auth.login&outcome=failure&from=2024-01-01T00:00:00Z
+func (h *AuditHandler) ListLogs(c *gin.Context) {
+	// 1. Parse và validate filter
+	req, ok := h.bindFilter(c)
+	if !ok {
+		return
+	}
+
+	// 2. Call usecase
+	logs, err := h.auditUsecase.ListLogs(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list audit logs", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Audit logs retrieved successfully", logs)
+}
+
+// Export - API tải audit log (cùng filter với ListLogs, không phân trang)
+// GET /api/v1/audit-logs/export?format=csv|json
+func (h *AuditHandler) Export(c *gin.Context) {
+	// 1. Parse và validate filter
+	req, ok := h.bindFilter(c)
+	if !ok {
+		return
+	}
+
+	// 2. Định dạng file
+	format := c.DefaultQuery("format", usecase.AuditExportCSV)
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case usecase.AuditExportCSV:
+	case usecase.AuditExportJSON:
+		contentType = "application/json; charset=utf-8"
+	default:
+		response.Error(c, http.StatusBadRequest, "Invalid export format", fmt.Errorf("format must be %s or %s", usecase.AuditExportCSV, usecase.AuditExportJSON))
+		return
+	}
+
+	// 3. Stream file (lỗi giữa chừng không đổi được status, chỉ dừng ghi)
+	filename := fmt.Sprintf("audit-logs-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := h.auditUsecase.Export(c.Request.Context(), req, format, c.Writer); err != nil {
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// VerifyChain - API kiểm tra audit log có bị sửa/xóa không
+// GET /api/v1/audit-logs/verify
+func (h *AuditHandler) VerifyChain(c *gin.Context) {
+	result, err := h.auditUsecase.VerifyChain(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to verify audit log", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Audit log verified", result)
+}
+
+// bindFilter - parse filter từ query string, tự trả lỗi nếu không hợp lệ
+func (h *AuditHandler) bindFilter(c *gin.Context) (*domain.ListAuditLogsRequest, bool) {
+	var req domain.ListAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return nil, false
+	}
+
+	// Set defaults nếu không có
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return nil, false
+	}
+
+	return &req, true
+}
@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/me/go-gin-auth/internal/domain"
 	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/password"
 	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/utils"
 	"github.com/me/go-gin-auth/pkg/validator"
 )
 
@@ -43,6 +48,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	// 3. Call usecase
 	user, err := h.authUsecase.Register(c.Request.Context(), &req)
 	if err != nil {
+		if passwordPolicyError(c, "password", err) {
+			return
+		}
 		response.Error(c, http.StatusBadRequest, "Registration failed", err)
 		return
 	}
@@ -68,20 +76,48 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// 3. Call usecase
-	accessToken, refreshToken, user, err := h.authUsecase.Login(c.Request.Context(), &req)
+	client := clientInfo(c, req.DeviceName)
+	result, err := h.authUsecase.Login(c.Request.Context(), &req, client)
 	if err != nil {
-		response.Error(c, http.StatusUnauthorized, "Login failed", err)
+		loginError(c, "Login failed", err)
 		return
 	}
 
-	// 4. Return tokens and user info
-	data := gin.H{
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
-		"user":          user,
+	// 4. Nếu cần MFA -> client gọi tiếp /auth/mfa/verify với mfa_token
+	if result.MFARequired {
+		response.Success(c, http.StatusOK, "MFA verification required", result)
+		return
+	}
+
+	// 5. Return tokens and user info
+	response.Success(c, http.StatusOK, "Login successful", result)
+}
+
+// VerifyMFA - API hoàn tất login bằng mã MFA
+// POST /api/v1/auth/mfa/verify
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	// 1. Parse request
+	var req domain.VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 2. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	client := clientInfo(c, req.DeviceName)
+	result, err := h.authUsecase.VerifyMFA(c.Request.Context(), req.MFAToken, req.Code, client)
+	if err != nil {
+		loginError(c, "MFA verification failed", err)
+		return
 	}
 
-	response.Success(c, http.StatusOK, "Login successful", data)
+	response.Success(c, http.StatusOK, "Login successful", result)
 }
 
 // Logout - API đăng xuất
@@ -101,8 +137,8 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	// 3. Call usecase
-	err := h.authUsecase.Logout(c.Request.Context(), userID.(uint), req.RefreshToken)
+	// 3. Call usecase (access token đang dùng bị thu hồi cùng refresh token)
+	err := h.authUsecase.Logout(c.Request.Context(), userID.(uint), req.RefreshToken, c.GetString("token_id"), c.GetTime("token_expires_at"))
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, "Logout failed", err)
 		return
@@ -128,7 +164,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	// 3. Call usecase
-	accessToken, refreshToken, err := h.authUsecase.RefreshToken(c.Request.Context(), req.RefreshToken)
+	accessToken, refreshToken, err := h.authUsecase.RefreshToken(c.Request.Context(), req.RefreshToken, clientInfo(c, ""))
 	if err != nil {
 		response.Error(c, http.StatusUnauthorized, "Token refresh failed", err)
 		return
@@ -188,9 +224,106 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	// 3. Call usecase
 	err := h.authUsecase.ResetPassword(c.Request.Context(), req.Token, req.NewPassword)
 	if err != nil {
+		if passwordPolicyError(c, "new_password", err) {
+			return
+		}
 		response.Error(c, http.StatusBadRequest, "Password reset failed", err)
 		return
 	}
 
 	response.Success(c, http.StatusOK, "Password reset successfully", nil)
 }
+
+// VerifyEmail - API xác thực email
+// POST /api/v1/auth/verify-email
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	// 1. Parse request
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 2. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	err := h.authUsecase.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Email verification failed", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Email verified successfully", nil)
+}
+
+// ResendVerification - API gửi lại email xác thực
+// POST /api/v1/auth/resend-verification
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	// 1. Parse request
+	var req domain.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 2. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	err := h.authUsecase.ResendVerification(c.Request.Context(), req.Email)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to resend verification email", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Verification email sent if the account exists and is not verified", nil)
+}
+
+// loginError - trả về 429 + Retry-After khi bị khóa login, 401 cho các lỗi còn lại
+func loginError(c *gin.Context, message string, err error) {
+	var lockedErr *usecase.LoginLockedError
+	if errors.As(err, &lockedErr) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+		response.Error(c, http.StatusTooManyRequests, message, err)
+		return
+	}
+
+	response.Error(c, http.StatusUnauthorized, message, err)
+}
+
+// passwordPolicyError - trả về từng rule password policy vi phạm dưới dạng lỗi validation
+// Trả về false nếu err không phải lỗi password policy
+func passwordPolicyError(c *gin.Context, field string, err error) bool {
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	errs := make([]validator.ValidationError, len(policyErr.Violations))
+	for i, v := range policyErr.Violations {
+		errs[i] = validator.ValidationError{
+			Field:   field,
+			Tag:     v.Rule,
+			Message: v.Message,
+		}
+	}
+	response.ValidationError(c, "Password does not meet policy", errs)
+	return true
+}
+
+// clientInfo - lấy thông tin client từ request để lưu vào session
+func clientInfo(c *gin.Context, deviceName string) *domain.ClientInfo {
+	// Cắt user agent cho vừa cột user_agent VARCHAR(512)
+	return &domain.ClientInfo{
+		IPAddress:  c.ClientIP(),
+		UserAgent:  utils.Truncate(c.Request.UserAgent(), 512),
+		DeviceName: deviceName,
+	}
+}
@@ -1,13 +1,19 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/usecase"
 	"github.com/me/go-gin-auth/pkg/response"
 	"gorm.io/gorm"
 )
 
+// auditFailureWindow - audit log ghi lỗi trong khoảng này thì health check báo lỗi
+const auditFailureWindow = 5 * time.Minute
+
 // HealthHandler - xử lý health check APIs
 type HealthHandler struct {
 	db *gorm.DB
@@ -27,7 +33,7 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	})
 }
 
-// DatabaseHealthCheck - API health check với database (cần auth)
+// DatabaseHealthCheck - API health check với database và audit log (cần auth)
 // GET /api/v1/health
 func (h *HealthHandler) DatabaseHealthCheck(c *gin.Context) {
 	// Kiểm tra kết nối database
@@ -43,9 +49,18 @@ func (h *HealthHandler) DatabaseHealthCheck(c *gin.Context) {
 		return
 	}
 
+	// Audit log ghi lỗi gần đây (request vẫn thành công nhưng sự kiện bảo mật bị mất)
+	failedWrites, lastFailure := usecase.AuditWriteStatus()
+	if !lastFailure.IsZero() && time.Since(lastFailure) < auditFailureWindow {
+		response.Error(c, http.StatusServiceUnavailable, "Audit log writes are failing",
+			errors.New("audit log write failed at "+lastFailure.UTC().Format(time.RFC3339)))
+		return
+	}
+
 	response.Success(c, http.StatusOK, "Database is healthy", gin.H{
-		"status":   "ok",
-		"database": "connected",
-		"service":  "go-gin-auth",
+		"status":              "ok",
+		"database":            "connected",
+		"audit_failed_writes": failedWrites,
+		"service":             "go-gin-auth",
 	})
 }
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+// MFAHandler - xử lý các API về two-factor authentication
+type MFAHandler struct {
+	mfaUsecase usecase.MFAUsecase
+	validator  *validator.Validator
+}
+
+// NewMFAHandler - tạo MFA handler mới
+func NewMFAHandler(mfaUsecase usecase.MFAUsecase, validator *validator.Validator) *MFAHandler {
+	return &MFAHandler{
+		mfaUsecase: mfaUsecase,
+		validator:  validator,
+	}
+}
+
+// Enroll - API bắt đầu đăng ký MFA (trả về secret + otpauth URI)
+// POST /api/v1/users/me/mfa/enroll
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Call usecase
+	result, err := h.mfaUsecase.Enroll(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to start MFA enrollment", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Scan the QR code and confirm with a code from your authenticator app", result)
+}
+
+// Confirm - API xác nhận đăng ký MFA (trả về backup codes)
+// POST /api/v1/users/me/mfa/confirm
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Parse request
+	var req domain.ConfirmMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	result, err := h.mfaUsecase.Confirm(c.Request.Context(), userID.(uint), req.Code)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to confirm MFA", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "MFA enabled successfully. Store your backup codes in a safe place", result)
+}
+
+// Disable - API tắt MFA
+// POST /api/v1/users/me/mfa/disable
+func (h *MFAHandler) Disable(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Parse request
+	var req domain.DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	err := h.mfaUsecase.Disable(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to disable MFA", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "MFA disabled successfully", nil)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+// OAuthClientHandler - xử lý các API quản lý OAuth client của authorization server (chỉ admin)
+type OAuthClientHandler struct {
+	clientUsecase usecase.OAuthClientUsecase
+	validator     *validator.Validator
+}
+
+// NewOAuthClientHandler - tạo oauth client handler mới
+func NewOAuthClientHandler(clientUsecase usecase.OAuthClientUsecase, validator *validator.Validator) *OAuthClientHandler {
+	return &OAuthClientHandler{
+		clientUsecase: clientUsecase,
+		validator:     validator,
+	}
+}
+
+// ListClients - API lấy danh sách client
+// GET /api/v1/oauth/clients
+func (h *OAuthClientHandler) ListClients(c *gin.Context) {
+	clients, err := h.clientUsecase.ListClients(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list OAuth clients", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OAuth clients retrieved successfully", clients)
+}
+
+// CreateClient - API đăng ký client (secret chỉ hiển thị 1 lần)
+// POST /api/v1/oauth/clients
+func (h *OAuthClientHandler) CreateClient(c *gin.Context) {
+	// 1. Get actor ID
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	// 2. Bind và validate request
+	var req domain.CreateOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	client, err := h.clientUsecase.CreateClient(c.Request.Context(), actorID.(uint), &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to create OAuth client", err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "OAuth client created successfully, store the secret now", client)
+}
+
+// GetClient - API xem chi tiết client
+// GET /api/v1/oauth/clients/:id
+func (h *OAuthClientHandler) GetClient(c *gin.Context) {
+	// 1. Parse client ID
+	id, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid OAuth client ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	client, err := h.clientUsecase.GetClient(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to get OAuth client", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OAuth client retrieved successfully", client)
+}
+
+// UpdateClient - API cập nhật client
+// PUT /api/v1/oauth/clients/:id
+func (h *OAuthClientHandler) UpdateClient(c *gin.Context) {
+	// 1. Get actor ID
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	// 2. Parse client ID
+	id, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid OAuth client ID", err)
+		return
+	}
+
+	// 3. Bind và validate request
+	var req domain.UpdateOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	client, err := h.clientUsecase.UpdateClient(c.Request.Context(), actorID.(uint), id, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update OAuth client", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OAuth client updated successfully", client)
+}
+
+// DeleteClient - API xóa client
+// DELETE /api/v1/oauth/clients/:id
+func (h *OAuthClientHandler) DeleteClient(c *gin.Context) {
+	// 1. Parse client ID
+	id, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid OAuth client ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	if err := h.clientUsecase.DeleteClient(c.Request.Context(), id); err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to delete OAuth client", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OAuth client deleted successfully", nil)
+}
+
+// RotateSecret - API tạo client secret mới
+// POST /api/v1/oauth/clients/:id/rotate-secret
+func (h *OAuthClientHandler) RotateSecret(c *gin.Context) {
+	// 1. Parse client ID
+	id, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid OAuth client ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	client, err := h.clientUsecase.RotateClientSecret(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to rotate OAuth client secret", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "OAuth client secret rotated successfully, store the secret now", client)
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/oauth"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+const (
+	oauthStateCookie     = "oauth_state"
+	oauthStateCookiePath = "/api/v1/auth/oauth"
+	oauthStateCookieAge  = 600 // Giây, bằng thời gian sống của state phía server
+)
+
+// OAuthHandler - xử lý đăng nhập và liên kết tài khoản qua identity provider (Google, GitHub...)
+type OAuthHandler struct {
+	authUsecase  usecase.AuthUsecase
+	validator    *validator.Validator
+	secureCookie bool // Cookie state chỉ gửi qua HTTPS (bật ở production)
+}
+
+// NewOAuthHandler - tạo oauth handler mới
+func NewOAuthHandler(authUsecase usecase.AuthUsecase, validator *validator.Validator, secureCookie bool) *OAuthHandler {
+	return &OAuthHandler{
+		authUsecase:  authUsecase,
+		validator:    validator,
+		secureCookie: secureCookie,
+	}
+}
+
+// Start - API chuyển hướng user sang provider để đăng nhập
+// GET /api/v1/auth/oauth/:provider/start
+func (h *OAuthHandler) Start(c *gin.Context) {
+	// 1. Tạo state và URL
+	authURL, state, err := h.authUsecase.StartOAuth(c.Request.Context(), c.Param("provider"), nil)
+	if err != nil {
+		oauthStartError(c, err)
+		return
+	}
+
+	// 2. Gắn state vào trình duyệt (chống login CSRF) rồi chuyển hướng
+	h.setStateCookie(c, state, oauthStateCookieAge)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback - API provider chuyển user về sau khi đăng nhập
+// GET /api/v1/auth/oauth/:provider/callback?code=...&state=...
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	// 1. Parse query
+	var req domain.OAuthCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	// 2. User từ chối hoặc provider báo lỗi
+	if req.Error != "" {
+		response.Error(c, http.StatusBadRequest, "Provider returned an error", errors.New(req.Error+": "+req.ErrorDescription))
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. State phải khớp cookie của chính trình duyệt đã bắt đầu đăng nhập
+	cookieState, err := c.Cookie(oauthStateCookie)
+	h.setStateCookie(c, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookieState), []byte(req.State)) != 1 {
+		response.Error(c, http.StatusBadRequest, "OAuth state mismatch", errors.New("state does not match this browser"))
+		return
+	}
+
+	// 5. Call usecase
+	result, err := h.authUsecase.OAuthCallback(c.Request.Context(), c.Param("provider"), req.Code, req.State, clientInfo(c, ""))
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "OAuth login failed", err)
+		return
+	}
+
+	switch {
+	case result.LinkedIdentity != nil:
+		response.Success(c, http.StatusOK, "Identity linked successfully", result.LinkedIdentity)
+	case result.MFARequired:
+		response.Success(c, http.StatusOK, "MFA verification required", result.LoginResponse)
+	default:
+		response.Success(c, http.StatusOK, "Login successful", result.LoginResponse)
+	}
+}
+
+// Link - API bắt đầu liên kết provider vào tài khoản đang đăng nhập
+// POST /api/v1/users/me/identities/:provider/link
+func (h *OAuthHandler) Link(c *gin.Context) {
+	// 1. Get user ID từ middleware
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	id := userID.(uint)
+
+	// 2. Tạo state gắn với user
+	authURL, state, err := h.authUsecase.StartOAuth(c.Request.Context(), c.Param("provider"), &id)
+	if err != nil {
+		oauthStartError(c, err)
+		return
+	}
+
+	// 3. Client tự chuyển trình duyệt sang authorization_url (request này mang Authorization header nên không redirect được)
+	h.setStateCookie(c, state, oauthStateCookieAge)
+	response.Success(c, http.StatusOK, "Redirect the user to the authorization url", domain.OAuthStartResponse{AuthorizationURL: authURL})
+}
+
+// ListIdentities - API xem các provider đã liên kết
+// GET /api/v1/users/me/identities
+func (h *OAuthHandler) ListIdentities(c *gin.Context) {
+	// 1. Get user ID từ middleware
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Call usecase
+	identities, err := h.authUsecase.ListIdentities(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list identities", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Identities retrieved successfully", identities)
+}
+
+// UnlinkIdentity - API hủy liên kết provider
+// DELETE /api/v1/users/me/identities/:id
+func (h *OAuthHandler) UnlinkIdentity(c *gin.Context) {
+	// 1. Get user ID từ middleware
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Parse identity ID
+	identityID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid identity ID", err)
+		return
+	}
+
+	// 3. Call usecase
+	if err := h.authUsecase.UnlinkIdentity(c.Request.Context(), userID.(uint), identityID); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to unlink identity", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Identity unlinked successfully", nil)
+}
+
+// setStateCookie - cookie HttpOnly chứa state, SameSite=Lax để vẫn được gửi khi provider chuyển hướng về
+func (h *OAuthHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, value, maxAge, oauthStateCookiePath, "", h.secureCookie, true)
+}
+
+// oauthStartError - provider chưa cấu hình -> 404, không kết nối được provider -> 502
+func oauthStartError(c *gin.Context, err error) {
+	if errors.Is(err, oauth.ErrProviderNotFound) {
+		response.Error(c, http.StatusNotFound, "OAuth provider not found", err)
+		return
+	}
+	response.Error(c, http.StatusBadGateway, "Failed to start OAuth login", err)
+}
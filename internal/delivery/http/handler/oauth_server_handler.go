@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+// OAuthServerHandler - xử lý các endpoint của authorization server (OpenID Connect provider)
+// /oauth/* là endpoint chuẩn nên trả lỗi dạng {"error", "error_description"}, không dùng response wrapper
+type OAuthServerHandler struct {
+	serverUsecase usecase.OAuthServerUsecase
+	validator     *validator.Validator
+	consentURL    string // Trang frontend đăng nhập + hỏi consent
+}
+
+// NewOAuthServerHandler - tạo oauth server handler mới
+func NewOAuthServerHandler(serverUsecase usecase.OAuthServerUsecase, validator *validator.Validator, consentURL string) *OAuthServerHandler {
+	return &OAuthServerHandler{
+		serverUsecase: serverUsecase,
+		validator:     validator,
+		consentURL:    consentURL,
+	}
+}
+
+// Authorize - authorization endpoint, kiểm tra request rồi chuyển trình duyệt sang trang consent
+// GET /oauth/authorize?response_type=code&client_id=...&redirect_uri=...&scope=openid&state=...&code_challenge=...
+func (h *OAuthServerHandler) Authorize(c *gin.Context) {
+	// 1. Parse query
+	var req domain.AuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		oauthErrorJSON(c, &usecase.OAuthError{Code: usecase.OAuthErrInvalidRequest, Description: "invalid query parameters", Status: http.StatusBadRequest})
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		oauthErrorJSON(c, &usecase.OAuthError{Code: usecase.OAuthErrInvalidRequest, Description: "client_id is required", Status: http.StatusBadRequest})
+		return
+	}
+
+	// 2. Kiểm tra client, redirect URI, PKCE, scope
+	if err := h.serverUsecase.ValidateAuthorize(c.Request.Context(), &req); err != nil {
+		var oauthErr *usecase.OAuthError
+		if errors.As(err, &oauthErr) && oauthErr.RedirectTo != "" {
+			c.Redirect(http.StatusFound, oauthErr.RedirectTo)
+			return
+		}
+		oauthErrorJSON(c, err)
+		return
+	}
+
+	// 3. Trang consent giữ nguyên query, đăng nhập (nếu cần) rồi gọi /api/v1/oauth/consent
+	separator := "?"
+	if strings.Contains(h.consentURL, "?") {
+		separator = "&"
+	}
+	c.Redirect(http.StatusFound, h.consentURL+separator+c.Request.URL.RawQuery)
+}
+
+// Token - token endpoint (application/x-www-form-urlencoded)
+// POST /oauth/token
+func (h *OAuthServerHandler) Token(c *gin.Context) {
+	// Token response không được cache (RFC 6749 5.1)
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	// 1. Parse form
+	var req domain.OAuthTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		oauthErrorJSON(c, &usecase.OAuthError{Code: usecase.OAuthErrInvalidRequest, Description: "invalid request body", Status: http.StatusBadRequest})
+		return
+	}
+
+	// 2. client_secret_basic
+	if err := bindClientAuth(c, &req.ClientID, &req.ClientSecret); err != nil {
+		oauthClientErrorJSON(c, err)
+		return
+	}
+
+	// 3. Call usecase
+	resp, err := h.serverUsecase.Token(c.Request.Context(), &req)
+	if err != nil {
+		oauthClientErrorJSON(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Introspect - introspection endpoint cho API gateway / resource server (RFC 7662)
+// POST /oauth/introspect
+func (h *OAuthServerHandler) Introspect(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	// 1. Parse form
+	var req domain.OAuthTokenActionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		oauthErrorJSON(c, &usecase.OAuthError{Code: usecase.OAuthErrInvalidRequest, Description: "invalid request body", Status: http.StatusBadRequest})
+		return
+	}
+
+	// 2. client_secret_basic
+	if err := bindClientAuth(c, &req.ClientID, &req.ClientSecret); err != nil {
+		oauthClientErrorJSON(c, err)
+		return
+	}
+
+	// 3. Call usecase
+	resp, err := h.serverUsecase.Introspect(c.Request.Context(), &req)
+	if err != nil {
+		oauthClientErrorJSON(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Revoke - revocation endpoint (RFC 7009), token không hợp lệ vẫn trả 200
+// POST /oauth/revoke
+func (h *OAuthServerHandler) Revoke(c *gin.Context) {
+	// 1. Parse form
+	var req domain.OAuthTokenActionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		oauthErrorJSON(c, &usecase.OAuthError{Code: usecase.OAuthErrInvalidRequest, Description: "invalid request body", Status: http.StatusBadRequest})
+		return
+	}
+
+	// 2. client_secret_basic
+	if err := bindClientAuth(c, &req.ClientID, &req.ClientSecret); err != nil {
+		oauthClientErrorJSON(c, err)
+		return
+	}
+
+	// 3. Call usecase
+	if err := h.serverUsecase.Revoke(c.Request.Context(), &req); err != nil {
+		oauthClientErrorJSON(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// UserInfo - userinfo endpoint, trả claims của user theo scope của access token
+// GET|POST /oauth/userinfo
+func (h *OAuthServerHandler) UserInfo(c *gin.Context) {
+	// 1. Lấy bearer token
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		c.Header("WWW-Authenticate", `Bearer realm="oauth"`)
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Call usecase
+	info, err := h.serverUsecase.UserInfo(c.Request.Context(), token)
+	if err != nil {
+		var oauthErr *usecase.OAuthError
+		if errors.As(err, &oauthErr) {
+			c.Header("WWW-Authenticate", `Bearer realm="oauth", error="`+oauthErr.Code+`", error_description="`+oauthErr.Description+`"`)
+			c.AbortWithStatus(oauthErr.Status)
+			return
+		}
+		oauthErrorJSON(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, info)
+}
+
+// GetConsent - API lấy thông tin client và scope cho trang consent
+// GET /api/v1/oauth/consent?client_id=...&redirect_uri=...&scope=...
+func (h *OAuthServerHandler) GetConsent(c *gin.Context) {
+	// 1. Get user ID từ middleware
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Parse query
+	var req domain.AuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	info, err := h.serverUsecase.GetConsent(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid authorization request", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Consent information retrieved successfully", info)
+}
+
+// Consent - API user đồng ý / từ chối, trả về URL để frontend chuyển trình duyệt về client
+// POST /api/v1/oauth/consent
+func (h *OAuthServerHandler) Consent(c *gin.Context) {
+	// 1. Get user ID từ middleware
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Bind và validate request
+	var req domain.OAuthConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	result, err := h.serverUsecase.Consent(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid authorization request", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Redirect the user back to the client", result)
+}
+
+// bindClientAuth - client_secret_basic: client_id/secret được form-urlencode trước khi ghép (RFC 6749 2.3.1)
+// Không có Basic header -> giữ client_id/client_secret trong form (client_secret_post, public client)
+func bindClientAuth(c *gin.Context, clientID, clientSecret *string) error {
+	id, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		return nil
+	}
+	if *clientSecret != "" {
+		return &usecase.OAuthError{Code: usecase.OAuthErrInvalidRequest, Description: "use only one client authentication method", Status: http.StatusBadRequest}
+	}
+
+	decodedID, errID := url.QueryUnescape(id)
+	decodedSecret, errSecret := url.QueryUnescape(secret)
+	if errID != nil || errSecret != nil || (*clientID != "" && *clientID != decodedID) {
+		return &usecase.OAuthError{Code: usecase.OAuthErrInvalidClient, Description: "client authentication failed", Status: http.StatusUnauthorized}
+	}
+	*clientID, *clientSecret = decodedID, decodedSecret
+	return nil
+}
+
+// oauthClientErrorJSON - như oauthErrorJSON, thêm WWW-Authenticate khi xác thực client thất bại
+func oauthClientErrorJSON(c *gin.Context, err error) {
+	var oauthErr *usecase.OAuthError
+	if errors.As(err, &oauthErr) && oauthErr.Code == usecase.OAuthErrInvalidClient {
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	oauthErrorJSON(c, err)
+}
+
+// oauthErrorJSON - lỗi dạng RFC 6749 5.2, lỗi không phải OAuthError -> server_error
+func oauthErrorJSON(c *gin.Context, err error) {
+	var oauthErr *usecase.OAuthError
+	if !errors.As(err, &oauthErr) {
+		oauthErr = &usecase.OAuthError{Code: usecase.OAuthErrServerError, Status: http.StatusInternalServerError}
+	}
+
+	body := gin.H{"error": oauthErr.Code}
+	if oauthErr.Description != "" {
+		body["error_description"] = oauthErr.Description
+	}
+	c.JSON(oauthErr.Status, body)
+}
@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+// OrganizationHandler - xử lý các API về organization, thành viên và lời mời
+type OrganizationHandler struct {
+	orgUsecase  usecase.OrganizationUsecase
+	authUsecase usecase.AuthUsecase
+	validator   *validator.Validator
+}
+
+// NewOrganizationHandler - tạo organization handler mới
+func NewOrganizationHandler(orgUsecase usecase.OrganizationUsecase, authUsecase usecase.AuthUsecase, validator *validator.Validator) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgUsecase:  orgUsecase,
+		authUsecase: authUsecase,
+		validator:   validator,
+	}
+}
+
+// List - API lấy các organization của user
+// GET /api/v1/organizations
+func (h *OrganizationHandler) List(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Call usecase
+	orgs, err := h.orgUsecase.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list organizations", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Organizations retrieved successfully", orgs)
+}
+
+// Create - API tạo organization
+// POST /api/v1/organizations
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Parse request
+	var req domain.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	org, err := h.orgUsecase.Create(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to create organization", err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Organization created successfully", org)
+}
+
+// Get - API xem organization
+// GET /api/v1/organizations/:id
+func (h *OrganizationHandler) Get(c *gin.Context) {
+	// 1. Get user ID và organization ID
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+
+	// 2. Call usecase
+	org, err := h.orgUsecase.Get(c.Request.Context(), userID, orgID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to get organization", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Organization retrieved successfully", org)
+}
+
+// Update - API đổi tên organization
+// PUT /api/v1/organizations/:id
+func (h *OrganizationHandler) Update(c *gin.Context) {
+	// 1. Get user ID và organization ID
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+
+	// 2. Parse request
+	var req domain.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	org, err := h.orgUsecase.Update(c.Request.Context(), userID, orgID, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update organization", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Organization updated successfully", org)
+}
+
+// Delete - API xóa organization
+// DELETE /api/v1/organizations/:id
+func (h *OrganizationHandler) Delete(c *gin.Context) {
+	// 1. Get user ID và organization ID
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+
+	// 2. Call usecase
+	if err := h.orgUsecase.Delete(c.Request.Context(), userID, orgID); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to delete organization", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Organization deleted successfully", nil)
+}
+
+// Switch - API chọn organization đang làm việc (trả về access token mới)
+// POST /api/v1/organizations/:id/switch
+func (h *OrganizationHandler) Switch(c *gin.Context) {
+	// 1. Get user ID và organization ID
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+
+	// 2. Call usecase
+	result, err := h.authUsecase.SwitchOrganization(c.Request.Context(), userID, c.GetString("session_id"), orgID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to switch organization", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Organization switched successfully", result)
+}
+
+// ListMembers - API lấy danh sách thành viên (owner/admin)
+// GET /api/v1/organizations/:id/members
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	// 1. Get user ID và organization ID
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+
+	// 2. Parse query parameters
+	var req domain.ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	members, err := h.orgUsecase.ListMembers(c.Request.Context(), userID, orgID, &req)
+	if err != nil {
+		response.Error(c, http.StatusForbidden, "Failed to list members", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Members retrieved successfully", members)
+}
+
+// UpdateMember - API đổi role thành viên
+// PUT /api/v1/organizations/:id/members/:userId
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	// 1. Get user ID, organization ID và thành viên
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+	targetID, err := parseUintParam(c, "userId")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Parse request
+	var req domain.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	if err := h.orgUsecase.UpdateMember(c.Request.Context(), userID, orgID, targetID, &req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update member", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Member updated successfully", nil)
+}
+
+// RemoveMember - API xóa thành viên hoặc tự rời organization
+// DELETE /api/v1/organizations/:id/members/:userId
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	// 1. Get user ID, organization ID và thành viên
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+	targetID, err := parseUintParam(c, "userId")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	if err := h.orgUsecase.RemoveMember(c.Request.Context(), userID, orgID, targetID); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to remove member", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Member removed successfully", nil)
+}
+
+// ListInvitations - API lấy lời mời đang chờ
+// GET /api/v1/organizations/:id/invitations
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	// 1. Get user ID và organization ID
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+
+	// 2. Call usecase
+	invitations, err := h.orgUsecase.ListInvitations(c.Request.Context(), userID, orgID)
+	if err != nil {
+		response.Error(c, http.StatusForbidden, "Failed to list invitations", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+// CreateInvitation - API mời thành viên qua email
+// POST /api/v1/organizations/:id/invitations
+func (h *OrganizationHandler) CreateInvitation(c *gin.Context) {
+	// 1. Get user ID và organization ID
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+
+	// 2. Parse request
+	var req domain.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	invitation, err := h.orgUsecase.CreateInvitation(c.Request.Context(), userID, orgID, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to create invitation", err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Invitation sent successfully", invitation)
+}
+
+// RevokeInvitation - API thu hồi lời mời
+// DELETE /api/v1/organizations/:id/invitations/:invitationId
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	// 1. Get user ID, organization ID và lời mời
+	userID, orgID, ok := h.parseOrganization(c)
+	if !ok {
+		return
+	}
+	invitationID, err := parseUintParam(c, "invitationId")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid invitation ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	if err := h.orgUsecase.RevokeInvitation(c.Request.Context(), userID, orgID, invitationID); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to revoke invitation", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invitation revoked successfully", nil)
+}
+
+// AcceptInvitation - API chấp nhận lời mời
+// POST /api/v1/organizations/invitations/accept
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Parse request
+	var req domain.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	org, err := h.orgUsecase.AcceptInvitation(c.Request.Context(), userID.(uint), req.Token)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to accept invitation", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invitation accepted successfully", org)
+}
+
+// parseOrganization - lấy user ID (từ AuthMiddleware) và organization ID (path param :id)
+// Tự trả response lỗi, ok = false nếu không hợp lệ
+func (h *OrganizationHandler) parseOrganization(c *gin.Context) (uint, uint, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return 0, 0, false
+	}
+
+	orgID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid organization ID", err)
+		return 0, 0, false
+	}
+
+	return userID.(uint), orgID, true
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+// PersonalAccessTokenHandler - xử lý các API quản lý personal access token
+type PersonalAccessTokenHandler struct {
+	patUsecase usecase.PersonalAccessTokenUsecase
+	validator  *validator.Validator
+}
+
+// NewPersonalAccessTokenHandler - tạo personal access token handler mới
+func NewPersonalAccessTokenHandler(patUsecase usecase.PersonalAccessTokenUsecase, validator *validator.Validator) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		patUsecase: patUsecase,
+		validator:  validator,
+	}
+}
+
+// List - API lấy danh sách token
+// GET /api/v1/users/me/tokens
+func (h *PersonalAccessTokenHandler) List(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Call usecase
+	tokens, err := h.patUsecase.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list tokens", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Tokens retrieved successfully", tokens)
+}
+
+// Create - API tạo token mới (token chỉ hiển thị 1 lần)
+// POST /api/v1/users/me/tokens
+func (h *PersonalAccessTokenHandler) Create(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Parse request
+	var req domain.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	result, err := h.patUsecase.Create(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to create token", err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Token created successfully. Copy it now, it will not be shown again", result)
+}
+
+// Revoke - API vô hiệu hóa token
+// DELETE /api/v1/users/me/tokens/:id
+func (h *PersonalAccessTokenHandler) Revoke(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Parse token ID
+	tokenID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid token ID", err)
+		return
+	}
+
+	// 3. Call usecase
+	if err := h.patUsecase.Revoke(c.Request.Context(), userID.(uint), tokenID); err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to revoke token", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Token revoked successfully", nil)
+}
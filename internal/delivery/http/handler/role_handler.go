@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+// RoleHandler - xử lý các API quản lý role và permission (chỉ admin)
+type RoleHandler struct {
+	roleUsecase usecase.RoleUsecase
+	validator   *validator.Validator
+}
+
+// NewRoleHandler - tạo role handler mới
+func NewRoleHandler(roleUsecase usecase.RoleUsecase, validator *validator.Validator) *RoleHandler {
+	return &RoleHandler{
+		roleUsecase: roleUsecase,
+		validator:   validator,
+	}
+}
+
+// ListRoles - API lấy danh sách role
+// GET /api/v1/roles
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleUsecase.ListRoles(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list roles", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Roles retrieved successfully", roles)
+}
+
+// ListPermissions - API lấy danh sách permission
+// GET /api/v1/permissions
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.roleUsecase.ListPermissions(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list permissions", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Permissions retrieved successfully", permissions)
+}
+
+// CreateRole - API tạo role
+// POST /api/v1/roles
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	// 1. Parse request
+	var req domain.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 2. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	role, err := h.roleUsecase.CreateRole(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to create role", err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Role created successfully", role)
+}
+
+// UpdateRole - API cập nhật role
+// PUT /api/v1/roles/:id
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	// 1. Parse role ID
+	roleID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid role ID", err)
+		return
+	}
+
+	// 2. Parse request
+	var req domain.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	role, err := h.roleUsecase.UpdateRole(c.Request.Context(), roleID, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update role", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Role updated successfully", role)
+}
+
+// DeleteRole - API xóa role
+// DELETE /api/v1/roles/:id
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	// 1. Parse role ID
+	roleID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid role ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	if err := h.roleUsecase.DeleteRole(c.Request.Context(), roleID); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to delete role", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Role deleted successfully", nil)
+}
+
+// GetUserRoles - API lấy role và permission của user
+// GET /api/v1/users/:id/roles
+func (h *RoleHandler) GetUserRoles(c *gin.Context) {
+	// 1. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	result, err := h.roleUsecase.GetUserRoles(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to get user roles", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User roles retrieved successfully", result)
+}
+
+// SetUserRoles - API thay role gán thêm của user
+// PUT /api/v1/users/:id/roles
+func (h *RoleHandler) SetUserRoles(c *gin.Context) {
+	// 1. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Parse request
+	var req domain.SetUserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// 3. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	result, err := h.roleUsecase.SetUserRoles(c.Request.Context(), userID, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update user roles", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User roles updated successfully", result)
+}
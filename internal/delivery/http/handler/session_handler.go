@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+)
+
+// SessionHandler - xử lý các API quản lý session đăng nhập
+type SessionHandler struct {
+	sessionUsecase usecase.SessionUsecase
+}
+
+// NewSessionHandler - tạo session handler mới
+func NewSessionHandler(sessionUsecase usecase.SessionUsecase) *SessionHandler {
+	return &SessionHandler{sessionUsecase: sessionUsecase}
+}
+
+// ListSessions - API lấy danh sách session đang đăng nhập
+// GET /api/v1/users/me/sessions
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	// 1. Get user ID và session hiện tại (đã set bởi AuthMiddleware)
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Call usecase
+	sessions, err := h.sessionUsecase.ListSessions(c.Request.Context(), userID.(uint), c.GetString("session_id"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list sessions", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeSession - API đăng xuất 1 session
+// DELETE /api/v1/users/me/sessions/:id
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Call usecase
+	err := h.sessionUsecase.RevokeSession(c.Request.Context(), userID.(uint), c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to revoke session", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeOtherSessions - API đăng xuất tất cả session khác
+// POST /api/v1/users/me/sessions/revoke-others
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	// 1. Get user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// 2. Call usecase
+	err := h.sessionUsecase.RevokeOtherSessions(c.Request.Context(), userID.(uint), c.GetString("session_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to revoke other sessions", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Other sessions revoked successfully", nil)
+}
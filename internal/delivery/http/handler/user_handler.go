@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"net"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/me/go-gin-auth/internal/domain"
@@ -103,6 +106,9 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	// 4. Call usecase
 	err := h.userUsecase.ChangePassword(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
+		if passwordPolicyError(c, "new_password", err) {
+			return
+		}
 		response.Error(c, http.StatusBadRequest, "Failed to change password", err)
 		return
 	}
@@ -143,3 +149,258 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Users retrieved successfully", users)
 }
+
+// GetLockoutStatus - API xem trạng thái khóa login của user (chỉ admin)
+// GET /api/v1/users/:id/lockout
+func (h *UserHandler) GetLockoutStatus(c *gin.Context) {
+	// 1. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	status, err := h.userUsecase.GetLockoutStatus(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to get lockout status", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Lockout status retrieved successfully", status)
+}
+
+// Unlock - API mở khóa login cho user (chỉ admin)
+// DELETE /api/v1/users/:id/lockout?ip=203.0.113.7 (ip: tùy chọn, xóa thêm khóa theo IP)
+func (h *UserHandler) Unlock(c *gin.Context) {
+	// 1. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Parse IP (nếu có)
+	var ip string
+	if raw := c.Query("ip"); raw != "" {
+		parsed := net.ParseIP(raw)
+		if parsed == nil {
+			response.Error(c, http.StatusBadRequest, "Invalid IP address", errors.New("ip must be a valid IPv4 or IPv6 address"))
+			return
+		}
+		ip = parsed.String()
+	}
+
+	// 3. Call usecase
+	if err := h.userUsecase.Unlock(c.Request.Context(), userID, ip); err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to unlock user", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User unlocked successfully", nil)
+}
+
+// GetUser - API xem chi tiết user (chỉ admin)
+// GET /api/v1/users/:id
+func (h *UserHandler) GetUser(c *gin.Context) {
+	// 1. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	user, err := h.userUsecase.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to get user", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User retrieved successfully", user)
+}
+
+// AdminUpdateUser - API cập nhật thông tin user (chỉ admin, chỉ các field được gửi)
+// PATCH /api/v1/users/:id
+func (h *UserHandler) AdminUpdateUser(c *gin.Context) {
+	// 1. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Bind và validate request
+	var req domain.AdminUpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	user, err := h.userUsecase.AdminUpdateUser(c.Request.Context(), userID, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update user", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User updated successfully", user)
+}
+
+// DeleteUser - API xóa user (chỉ admin)
+// DELETE /api/v1/users/:id
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	// 1. Get actor ID
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	// 2. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 3. Call usecase
+	if err := h.userUsecase.DeleteUser(c.Request.Context(), actorID.(uint), userID); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to delete user", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "User deleted successfully", nil)
+}
+
+// ChangeRole - API đổi role chính của user (chỉ admin)
+// PUT /api/v1/users/:id/role
+func (h *UserHandler) ChangeRole(c *gin.Context) {
+	// 1. Get actor ID
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	// 2. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 3. Bind và validate request
+	var req domain.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	user, err := h.userUsecase.ChangeRole(c.Request.Context(), actorID.(uint), userID, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to change role", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Role changed successfully", user)
+}
+
+// ChangeStatus - API khóa / tạm khóa / mở lại tài khoản (chỉ admin)
+// PUT /api/v1/users/:id/status
+func (h *UserHandler) ChangeStatus(c *gin.Context) {
+	// 1. Get actor ID
+	actorID, exists := c.Get("user_id")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	// 2. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 3. Bind và validate request
+	var req domain.ChangeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 4. Call usecase
+	user, err := h.userUsecase.ChangeStatus(c.Request.Context(), actorID.(uint), userID, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to change status", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Status changed successfully", user)
+}
+
+// ForcePasswordReset - API bắt user đặt lại password (chỉ admin)
+// POST /api/v1/users/:id/force-password-reset
+func (h *UserHandler) ForcePasswordReset(c *gin.Context) {
+	// 1. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	if err := h.userUsecase.ForcePasswordReset(c.Request.Context(), userID); err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to force password reset", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Password reset email sent", nil)
+}
+
+// RevokeSessions - API đăng xuất user khỏi mọi thiết bị (chỉ admin)
+// POST /api/v1/users/:id/revoke-sessions
+func (h *UserHandler) RevokeSessions(c *gin.Context) {
+	// 1. Parse user ID
+	userID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	if err := h.userUsecase.RevokeSessions(c.Request.Context(), userID); err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to revoke sessions", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "All sessions revoked successfully", nil)
+}
+
+// parseIDParam - lấy ID số từ path param :id
+func parseIDParam(c *gin.Context) (uint, error) {
+	return parseUintParam(c, "id")
+}
+
+// parseUintParam - lấy ID số từ path param bất kỳ
+func parseUintParam(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
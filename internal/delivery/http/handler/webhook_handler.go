@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/validator"
+)
+
+// WebhookHandler - xử lý các API quản lý webhook (chỉ admin)
+type WebhookHandler struct {
+	webhookUsecase usecase.WebhookUsecase
+	validator      *validator.Validator
+}
+
+// NewWebhookHandler - tạo webhook handler mới
+func NewWebhookHandler(webhookUsecase usecase.WebhookUsecase, validator *validator.Validator) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUsecase: webhookUsecase,
+		validator:      validator,
+	}
+}
+
+// ListEndpoints - API lấy danh sách endpoint
+// GET /api/v1/webhooks
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	endpoints, err := h.webhookUsecase.ListEndpoints(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to list webhook endpoints", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook endpoints retrieved successfully", endpoints)
+}
+
+// CreateEndpoint - API tạo endpoint (secret chỉ hiển thị 1 lần)
+// POST /api/v1/webhooks
+func (h *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	// 1. Bind và validate request
+	var req domain.CreateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 2. Call usecase
+	endpoint, err := h.webhookUsecase.CreateEndpoint(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to create webhook endpoint", err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Webhook endpoint created successfully, store the secret now", endpoint)
+}
+
+// GetEndpoint - API xem chi tiết endpoint
+// GET /api/v1/webhooks/:id
+func (h *WebhookHandler) GetEndpoint(c *gin.Context) {
+	// 1. Parse endpoint ID
+	endpointID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid webhook endpoint ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	endpoint, err := h.webhookUsecase.GetEndpoint(c.Request.Context(), endpointID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to get webhook endpoint", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook endpoint retrieved successfully", endpoint)
+}
+
+// UpdateEndpoint - API cập nhật endpoint
+// PUT /api/v1/webhooks/:id
+func (h *WebhookHandler) UpdateEndpoint(c *gin.Context) {
+	// 1. Parse endpoint ID
+	endpointID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid webhook endpoint ID", err)
+		return
+	}
+
+	// 2. Bind và validate request
+	var req domain.UpdateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 3. Call usecase
+	endpoint, err := h.webhookUsecase.UpdateEndpoint(c.Request.Context(), endpointID, &req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update webhook endpoint", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook endpoint updated successfully", endpoint)
+}
+
+// DeleteEndpoint - API xóa endpoint
+// DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	// 1. Parse endpoint ID
+	endpointID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid webhook endpoint ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	if err := h.webhookUsecase.DeleteEndpoint(c.Request.Context(), endpointID); err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to delete webhook endpoint", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook endpoint deleted successfully", nil)
+}
+
+// RotateSecret - API tạo secret mới cho endpoint
+// POST /api/v1/webhooks/:id/rotate-secret
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	// 1. Parse endpoint ID
+	endpointID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid webhook endpoint ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	endpoint, err := h.webhookUsecase.RotateSecret(c.Request.Context(), endpointID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to rotate webhook secret", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook secret rotated successfully, store the secret now", endpoint)
+}
+
+// ListDeliveries - API xem lịch sử gửi của endpoint
+// GET /api/v1/webhooks/:id/deliveries?status=failed
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	// 1. Parse endpoint ID
+	endpointID, err := parseIDParam(c)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid webhook endpoint ID", err)
+		return
+	}
+
+	// 2. Parse query parameters
+	var req domain.ListWebhookDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", err)
+		return
+	}
+
+	// 3. Set defaults nếu không có
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	// 4. Validate
+	if errs := h.validator.Validate(&req); len(errs) > 0 {
+		response.ValidationError(c, "Validation failed", errs)
+		return
+	}
+
+	// 5. Call usecase
+	deliveries, err := h.webhookUsecase.ListDeliveries(c.Request.Context(), endpointID, &req)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to list webhook deliveries", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook deliveries retrieved successfully", deliveries)
+}
+
+// GetDelivery - API xem chi tiết 1 lần gửi kèm log
+// GET /api/v1/webhooks/deliveries/:deliveryId
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	// 1. Parse delivery ID
+	deliveryID, err := parseUintParam(c, "deliveryId")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid webhook delivery ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	delivery, err := h.webhookUsecase.GetDelivery(c.Request.Context(), deliveryID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to get webhook delivery", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook delivery retrieved successfully", delivery)
+}
+
+// Redeliver - API gửi lại sự kiện
+// POST /api/v1/webhooks/deliveries/:deliveryId/redeliver
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	// 1. Parse delivery ID
+	deliveryID, err := parseUintParam(c, "deliveryId")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid webhook delivery ID", err)
+		return
+	}
+
+	// 2. Call usecase
+	delivery, err := h.webhookUsecase.Redeliver(c.Request.Context(), deliveryID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to redeliver webhook", err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Webhook queued for redelivery", delivery)
+}
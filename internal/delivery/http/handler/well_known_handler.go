@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/usecase"
+	"github.com/me/go-gin-auth/pkg/jwt"
+)
+
+// WellKnownHandler - xử lý các endpoint /.well-known/* (chuẩn, không dùng response wrapper)
+type WellKnownHandler struct {
+	jwtService    jwt.Service
+	serverUsecase usecase.OAuthServerUsecase
+}
+
+// NewWellKnownHandler - tạo well-known handler mới
+func NewWellKnownHandler(jwtService jwt.Service, serverUsecase usecase.OAuthServerUsecase) *WellKnownHandler {
+	return &WellKnownHandler{
+		jwtService:    jwtService,
+		serverUsecase: serverUsecase,
+	}
+}
+
+// JWKS - API trả về public keys để service khác verify access token
+// GET /.well-known/jwks.json
+func (h *WellKnownHandler) JWKS(c *gin.Context) {
+	// Cho phép cache ngắn để client không gọi lại mỗi request
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtService.JWKS())
+}
+
+// OpenIDConfiguration - API trả về metadata của OpenID Connect provider
+// GET /.well-known/openid-configuration
+func (h *WellKnownHandler) OpenIDConfiguration(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.serverUsecase.Discovery())
+}
@@ -1,16 +1,36 @@
 package middleware
 
 import (
+	"context"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/internal/domain"
 	"github.com/me/go-gin-auth/pkg/jwt"
 	"github.com/me/go-gin-auth/pkg/response"
+	"github.com/me/go-gin-auth/pkg/revocation"
 	"github.com/me/go-gin-auth/pkg/utils"
 )
 
-// AuthMiddleware - middleware xác thực JWT token
-func AuthMiddleware(jwtService jwt.Service) gin.HandlerFunc {
+// Cách xác thực của request (context key "auth_method")
+const (
+	AuthMethodJWT                 = "jwt"
+	AuthMethodPersonalAccessToken = "pat"
+	AuthMethodClientCredentials   = "client_credentials"
+)
+
+// PersonalAccessTokenAuthenticator - xác thực personal access token (implement bởi usecase)
+type PersonalAccessTokenAuthenticator interface {
+	Authenticate(ctx context.Context, token, ipAddress string) (*domain.PersonalAccessTokenAuth, error)
+}
+
+// AuthMiddleware - middleware xác thực JWT token hoặc personal access token (prefix gga_)
+// patAuth = nil -> chỉ chấp nhận JWT
+// revocations = nil -> không kiểm tra access token đã bị thu hồi (logout, reset password...)
+// Context key "subject_type" cho biết caller là user (jwt.SubjectTypeUser) hay service (jwt.SubjectTypeService),
+// service không có "user_id" nên các API của user trả 401
+func AuthMiddleware(jwtService jwt.Service, patAuth PersonalAccessTokenAuthenticator, revocations revocation.Store) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 1. Lấy Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -36,7 +56,13 @@ func AuthMiddleware(jwtService jwt.Service) gin.HandlerFunc {
 			return
 		}
 
-		// 4. Validate token
+		// 4. Personal access token
+		if patAuth != nil && strings.HasPrefix(token, domain.PersonalAccessTokenPrefix) {
+			authenticatePersonalAccessToken(c, patAuth, token)
+			return
+		}
+
+		// 5. Validate JWT
 		claims, err := jwtService.ValidateAccessToken(token)
 		if err != nil {
 			response.Unauthorized(c, "Invalid or expired token")
@@ -44,11 +70,120 @@ func AuthMiddleware(jwtService jwt.Service) gin.HandlerFunc {
 			return
 		}
 
-		// 5. Set user info vào context để handler khác dùng
+		// 6. Token đã bị thu hồi theo jti hoặc mốc thu hồi của user
+		// Store lỗi (ví dụ Redis down) -> dùng kết quả kiểm tra được từ cache local, không chặn người dùng thật
+		if revocations != nil {
+			if revoked, _ := revocation.IsAccessTokenRevoked(c.Request.Context(), revocations, claims); revoked {
+				response.Unauthorized(c, "Token has been revoked")
+				c.Abort()
+				return
+			}
+		}
+
+		// Token của service (client credentials): permission là scope được cấp
+		if claims.IsService() {
+			authenticateService(c, claims)
+			return
+		}
+
+		// Token cấp cho OAuth client (app khác) chỉ dùng cho /oauth/userinfo, không gọi được API của app này
+		if claims.ClientID != "" {
+			response.Unauthorized(c, "Token was issued to an OAuth client")
+			c.Abort()
+			return
+		}
+
+		// 7. Set user info vào context để handler khác dùng
 		c.Set("user_id", claims.UserID)
 		c.Set("user_role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Set("token_id", claims.ID)
+		if claims.ExpiresAt != nil {
+			c.Set("token_expires_at", claims.ExpiresAt.Time)
+		}
+		c.Set("permissions", claims.Permissions)
+		c.Set("organization_id", claims.OrganizationID)
+		c.Set("auth_method", AuthMethodJWT)
+		c.Set("subject_type", jwt.SubjectTypeUser)
+		setAuditActor(c, claims.UserID)
+
+		// 8. Continue to next handler
+		c.Next()
+	}
+}
+
+// authenticatePersonalAccessToken - xác thực PAT và kiểm tra scope theo HTTP method
+func authenticatePersonalAccessToken(c *gin.Context, patAuth PersonalAccessTokenAuthenticator, token string) {
+	// 1. Xác thực token
+	auth, err := patAuth.Authenticate(c.Request.Context(), token, c.ClientIP())
+	if err != nil {
+		response.Unauthorized(c, "Invalid or expired token")
+		c.Abort()
+		return
+	}
+
+	// 2. Scope read chỉ được gọi API không thay đổi dữ liệu
+	scopes := auth.Token.ScopeList()
+	if !isReadOnlyMethod(c.Request.Method) && !utils.Contains(scopes, domain.TokenScopeWrite) {
+		response.Forbidden(c, "Token does not have the write scope")
+		c.Abort()
+		return
+	}
+
+	// 3. Set user info vào context (không có session)
+	c.Set("user_id", auth.User.ID)
+	c.Set("user_role", auth.User.Role)
+	c.Set("permissions", auth.Permissions)
+	c.Set("auth_method", AuthMethodPersonalAccessToken)
+	c.Set("subject_type", jwt.SubjectTypeUser)
+	c.Set("token_scopes", scopes)
+	setAuditActor(c, auth.User.ID)
+
+	c.Next()
+}
+
+// authenticateService - set danh tính service vào context (không có user_id, role, session)
+func authenticateService(c *gin.Context, claims *jwt.AccessClaims) {
+	if claims.ClientID == "" {
+		response.Unauthorized(c, "Invalid or expired token")
+		c.Abort()
+		return
+	}
+
+	c.Set("client_id", claims.ClientID)
+	c.Set("permissions", strings.Fields(claims.Scope))
+	c.Set("auth_method", AuthMethodClientCredentials)
+	c.Set("subject_type", jwt.SubjectTypeService)
+
+	c.Next()
+}
+
+// setAuditActor - ghi nhận user đang đăng nhập là người thực hiện trong audit log
+func setAuditActor(c *gin.Context, userID uint) {
+	if meta := domain.RequestMetaFromContext(c.Request.Context()); meta != nil {
+		meta.ActorID = &userID
+	}
+}
+
+// isReadOnlyMethod - HTTP method không thay đổi dữ liệu
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// RequireInteractiveAuth - chặn personal access token và token của service (dùng cho API quản lý token, tránh token tự tạo token)
+func RequireInteractiveAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.GetString("auth_method") {
+		case AuthMethodPersonalAccessToken:
+			response.Forbidden(c, "This endpoint cannot be used with a personal access token")
+			c.Abort()
+			return
+		case AuthMethodClientCredentials:
+			response.Forbidden(c, "This endpoint cannot be used with a service token")
+			c.Abort()
+			return
+		}
 
-		// 6. Continue to next handler
 		c.Next()
 	}
 }
@@ -74,3 +209,22 @@ func RequireRoles(roles ...string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequirePermission - middleware kiểm tra user có đủ các permission (đã set bởi AuthMiddleware)
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 1. Lấy permission từ context
+		granted := c.GetStringSlice("permissions")
+
+		// 2. Phải có tất cả permission yêu cầu
+		for _, permission := range permissions {
+			if !utils.Contains(granted, permission) {
+				response.Forbidden(c, "Insufficient permissions")
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
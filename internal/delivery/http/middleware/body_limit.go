@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/pkg/response"
+)
+
+// BodyLimitMiddleware - giới hạn kích thước request body (maxBytes <= 0 là tắt)
+// Body vượt giới hạn -> bind JSON/form lỗi thay vì đọc hết vào memory
+func BodyLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		// 1. Content-Length đã vượt giới hạn -> từ chối luôn
+		if c.Request.ContentLength > maxBytes {
+			response.PayloadTooLarge(c, "Request body too large")
+			c.Abort()
+			return
+		}
+
+		// 2. Chunked hoặc Content-Length sai -> chặn khi đọc quá giới hạn
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
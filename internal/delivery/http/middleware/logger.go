@@ -5,6 +5,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/pkg/utils"
 	"go.uber.org/zap"
 )
 
@@ -15,6 +17,14 @@ func LoggerMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		requestID := uuid.New().String()
 		c.Set("request_id", requestID)
 
+		// Gắn vào context của request để usecase ghi audit log
+		userAgent := utils.Truncate(c.Request.UserAgent(), 512)
+		c.Request = c.Request.WithContext(domain.WithRequestMeta(c.Request.Context(), &domain.RequestMeta{
+			RequestID: requestID,
+			IPAddress: c.ClientIP(),
+			UserAgent: userAgent,
+		}))
+
 		// 2. Ghi lại thời gian bắt đầu
 		start := time.Now()
 		path := c.Request.URL.Path
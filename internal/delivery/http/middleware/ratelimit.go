@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/me/go-gin-auth/pkg/redisclient"
+	"github.com/me/go-gin-auth/pkg/response"
+)
+
+// RateLimitResult - kết quả kiểm tra rate limit cho 1 request
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // Thời gian đến khi bucket đầy lại
+	RetryAfter time.Duration // Thời gian phải chờ khi bị chặn
+}
+
+// RateLimitStore - interface lưu trạng thái rate limit (memory, Redis...)
+type RateLimitStore interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+}
+
+// RateLimitPolicy - cấu hình rate limit cho 1 nhóm route
+type RateLimitPolicy struct {
+	Name     string                      // Prefix của key, tách bucket giữa các policy
+	Requests int                         // Số request tối đa trong 1 window
+	Window   time.Duration               // Độ dài window
+	KeyFunc  func(c *gin.Context) string // Mặc định theo client IP
+}
+
+// RateLimitMiddleware - middleware giới hạn số request theo policy
+func RateLimitMiddleware(store RateLimitStore, policy RateLimitPolicy) gin.HandlerFunc {
+	keyFunc := policy.KeyFunc
+	if keyFunc == nil {
+		keyFunc = func(c *gin.Context) string { return c.ClientIP() }
+	}
+
+	return func(c *gin.Context) {
+		// 1. Policy bị tắt (requests <= 0) -> bỏ qua
+		if policy.Requests <= 0 || policy.Window <= 0 {
+			c.Next()
+			return
+		}
+
+		// 2. Kiểm tra bucket của client
+		key := policy.Name + ":" + keyFunc(c)
+		result, err := store.Allow(c.Request.Context(), key, policy.Requests, policy.Window)
+		if err != nil {
+			// Store lỗi (ví dụ Redis down) -> fail open, không chặn người dùng thật
+			c.Next()
+			return
+		}
+
+		// 3. Set header chuẩn (IETF draft RateLimit header fields)
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Requests, int(policy.Window.Seconds())))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		// 4. Vượt giới hạn -> 429 + Retry-After
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			response.TooManyRequests(c, "Too many requests, please try again later")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ceilSeconds - làm tròn lên số giây (header chỉ nhận số nguyên)
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+// tokenBucket - trạng thái 1 bucket trong memory store
+type tokenBucket struct {
+	tokens   float64
+	updated  time.Time
+	fullTime time.Duration // Thời gian để bucket từ rỗng -> đầy (= window)
+}
+
+// memoryRateLimitStore - token bucket lưu trong memory (dùng cho 1 instance)
+type memoryRateLimitStore struct {
+	mu          sync.Mutex
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+}
+
+// cleanupInterval - chu kỳ dọn các bucket đã đầy lại (không còn cần giữ)
+const cleanupInterval = time.Minute
+
+// NewMemoryRateLimitStore - tạo rate limit store trong memory
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{
+		buckets:     make(map[string]*tokenBucket),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Allow - lấy 1 token từ bucket, bucket nạp lại đều limit token mỗi window
+func (s *memoryRateLimitStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.cleanup(now)
+
+	// 1. Lấy bucket (mới tạo thì đầy)
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit), updated: now, fullTime: window}
+		s.buckets[key] = bucket
+	}
+
+	// 2. Nạp thêm token theo thời gian đã trôi qua
+	rate := float64(limit) / window.Seconds() // token / giây
+	elapsed := now.Sub(bucket.updated).Seconds()
+	bucket.tokens = math.Min(float64(limit), bucket.tokens+elapsed*rate)
+	bucket.updated = now
+
+	// 3. Lấy token nếu còn
+	result := &RateLimitResult{Limit: limit}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	}
+
+	result.Remaining = int(math.Floor(bucket.tokens))
+	result.Reset = time.Duration((float64(limit) - bucket.tokens) / rate * float64(time.Second))
+
+	return result, nil
+}
+
+// cleanup - xóa các bucket đã nạp đầy lại (giữ map không phình to)
+func (s *memoryRateLimitStore) cleanup(now time.Time) {
+	if now.Sub(s.lastCleanup) < cleanupInterval {
+		return
+	}
+	s.lastCleanup = now
+
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.updated) >= bucket.fullTime {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// redisTokenBucketScript - token bucket atomic trên Redis
+// Trả về {allowed, tokens * 1000, retry_after_ms, reset_ms}
+const redisTokenBucketScript = `
+local limit = tonumber(ARGV[1])
+local window_ms = tonumber(ARGV[2])
+local now_ms = tonumber(ARGV[3])
+local rate = limit / window_ms
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1]) or limit
+local updated = tonumber(state[2]) or now_ms
+
+tokens = math.min(limit, tokens + (now_ms - updated) * rate)
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry_after = math.ceil((1 - tokens) / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tokens, "updated", now_ms)
+redis.call("PEXPIRE", KEYS[1], window_ms)
+
+return {allowed, math.floor(tokens * 1000), retry_after, math.ceil((limit - tokens) / rate)}
+`
+
+// redisRateLimitStore - token bucket lưu trên Redis (dùng khi chạy nhiều instance)
+type redisRateLimitStore struct {
+	client redisclient.Evaler
+	prefix string
+}
+
+// NewRedisRateLimitStore - tạo rate limit store dùng Redis
+func NewRedisRateLimitStore(client redisclient.Evaler, prefix string) RateLimitStore {
+	return &redisRateLimitStore{client: client, prefix: prefix}
+}
+
+// Allow - lấy 1 token từ bucket trên Redis (atomic bằng Lua script)
+func (s *redisRateLimitStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	raw, err := s.client.Eval(ctx, redisTokenBucketScript, []string{s.prefix + key},
+		limit, window.Milliseconds(), time.Now().UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to eval rate limit script: %w", err)
+	}
+
+	values, ok := raw.([]interface{})
+	if !ok || len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", raw)
+	}
+
+	nums := make([]int64, len(values))
+	for i, v := range values {
+		n, ok := v.(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected rate limit script value: %v", v)
+		}
+		nums[i] = n
+	}
+
+	return &RateLimitResult{
+		Allowed:    nums[0] == 1,
+		Limit:      limit,
+		Remaining:  int(nums[1] / 1000),
+		RetryAfter: time.Duration(nums[2]) * time.Millisecond,
+		Reset:      time.Duration(nums[3]) * time.Millisecond,
+	}, nil
+}
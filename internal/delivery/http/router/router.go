@@ -5,18 +5,34 @@ import (
 	"github.com/me/go-gin-auth/internal/config"
 	"github.com/me/go-gin-auth/internal/delivery/http/handler"
 	"github.com/me/go-gin-auth/internal/delivery/http/middleware"
+	"github.com/me/go-gin-auth/internal/domain"
 	"github.com/me/go-gin-auth/pkg/jwt"
+	"github.com/me/go-gin-auth/pkg/revocation"
 	"go.uber.org/zap"
 )
 
 // RouterConfig - config để setup router
 type RouterConfig struct {
-	AuthHandler   *handler.AuthHandler
-	UserHandler   *handler.UserHandler
-	HealthHandler *handler.HealthHandler
-	JWTService    jwt.Service
-	Logger        *zap.Logger
-	Config        *config.Config
+	AuthHandler      *handler.AuthHandler
+	UserHandler      *handler.UserHandler
+	MFAHandler       *handler.MFAHandler
+	SessionHandler   *handler.SessionHandler
+	TokenHandler     *handler.PersonalAccessTokenHandler
+	RoleHandler      *handler.RoleHandler
+	OrgHandler       *handler.OrganizationHandler
+	AuditHandler     *handler.AuditHandler
+	WebhookHandler   *handler.WebhookHandler
+	OAuthHandler     *handler.OAuthHandler
+	OAuthServer      *handler.OAuthServerHandler
+	OAuthClient      *handler.OAuthClientHandler
+	HealthHandler    *handler.HealthHandler
+	WellKnownHandler *handler.WellKnownHandler
+	JWTService       jwt.Service
+	PATAuthenticator middleware.PersonalAccessTokenAuthenticator
+	RateLimitStore   middleware.RateLimitStore
+	RevocationStore  revocation.Store
+	Logger           *zap.Logger
+	Config           *config.Config
 }
 
 // NewRouter - tạo Gin router với tất cả routes
@@ -27,35 +43,80 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 	}
 
 	// 2. Tạo Gin engine
+	// Chỉ lấy IP client từ X-Forwarded-For khi request đi qua proxy đã cấu hình (mặc định Gin tin mọi client)
 	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.Config.App.TrustedProxies); err != nil {
+		cfg.Logger.Fatal("Invalid TRUSTED_PROXIES", zap.Error(err))
+	}
 
 	// 3. Global middleware (áp dụng cho tất cả routes)
-	r.Use(gin.Recovery())                                            // Recover từ panic
-	r.Use(middleware.LoggerMiddleware(cfg.Logger))                   // Log requests
-	r.Use(middleware.CORSMiddleware(cfg.Config.CORS.AllowedOrigins)) // CORS
+	r.Use(gin.Recovery())                                             // Recover từ panic
+	r.Use(middleware.LoggerMiddleware(cfg.Logger))                    // Log requests
+	r.Use(middleware.CORSMiddleware(cfg.Config.CORS.AllowedOrigins))  // CORS
+	r.Use(middleware.BodyLimitMiddleware(cfg.Config.App.MaxBodySize)) // Giới hạn request body
 
 	// 4. Health check endpoint (không cần auth)
 	r.GET("/health", cfg.HealthHandler.HealthCheck)
 
-	// 5. API routes group
+	// Public keys cho các service khác verify access token
+	r.GET("/.well-known/jwks.json", cfg.WellKnownHandler.JWKS)
+	r.GET("/.well-known/openid-configuration", cfg.WellKnownHandler.OpenIDConfiguration)
+
+	// 5. Rate limit policies
+	rateLimit := cfg.Config.RateLimit
+	globalLimit := middleware.RateLimitMiddleware(cfg.RateLimitStore, middleware.RateLimitPolicy{
+		Name: "global", Requests: rateLimit.Requests, Window: rateLimit.Window,
+	})
+	loginLimit := middleware.RateLimitMiddleware(cfg.RateLimitStore, middleware.RateLimitPolicy{
+		Name: "login", Requests: rateLimit.LoginRequests, Window: rateLimit.LoginWindow,
+	})
+	forgotPasswordLimit := middleware.RateLimitMiddleware(cfg.RateLimitStore, middleware.RateLimitPolicy{
+		Name: "forgot-password", Requests: rateLimit.ForgotPasswordRequests, Window: rateLimit.ForgotPasswordWindow,
+	})
+
+	// Password, MFA, session và token chỉ quản lý được khi đăng nhập bằng JWT,
+	// tránh personal access token bị lộ dùng để chiếm tài khoản
+	interactiveOnly := middleware.RequireInteractiveAuth()
+
+	// Authorization server cho app nội bộ (endpoint chuẩn OAuth 2.0 / OpenID Connect)
+	oauthServer := r.Group("/oauth")
+	oauthServer.Use(globalLimit)
+	{
+		oauthServer.GET("/authorize", cfg.OAuthServer.Authorize)
+		oauthServer.POST("/token", cfg.OAuthServer.Token)
+		oauthServer.GET("/userinfo", cfg.OAuthServer.UserInfo)
+		oauthServer.POST("/userinfo", cfg.OAuthServer.UserInfo)
+		oauthServer.POST("/introspect", cfg.OAuthServer.Introspect)
+		oauthServer.POST("/revoke", cfg.OAuthServer.Revoke)
+	}
+
+	// 6. API routes group
 	api := r.Group("/api/v1")
+	api.Use(globalLimit)
 	{
 		// Auth routes (không cần authentication)
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", cfg.AuthHandler.Register)
-			auth.POST("/login", cfg.AuthHandler.Login)
+			auth.POST("/login", loginLimit, cfg.AuthHandler.Login)
 			auth.POST("/refresh", cfg.AuthHandler.RefreshToken)
-			auth.POST("/forgot-password", cfg.AuthHandler.ForgotPassword)
-			auth.POST("/reset-password", cfg.AuthHandler.ResetPassword)
+			auth.POST("/forgot-password", forgotPasswordLimit, cfg.AuthHandler.ForgotPassword)
+			auth.POST("/reset-password", forgotPasswordLimit, cfg.AuthHandler.ResetPassword)
+			auth.POST("/verify-email", forgotPasswordLimit, cfg.AuthHandler.VerifyEmail)
+			auth.POST("/resend-verification", forgotPasswordLimit, cfg.AuthHandler.ResendVerification)
+			auth.POST("/mfa/verify", loginLimit, cfg.AuthHandler.VerifyMFA)
+
+			// Đăng nhập qua identity provider (authorization code + PKCE)
+			auth.GET("/oauth/:provider/start", loginLimit, cfg.OAuthHandler.Start)
+			auth.GET("/oauth/:provider/callback", loginLimit, cfg.OAuthHandler.Callback)
 
 			// Logout cần auth để lấy user_id
-			auth.POST("/logout", middleware.AuthMiddleware(cfg.JWTService), cfg.AuthHandler.Logout)
+			auth.POST("/logout", middleware.AuthMiddleware(cfg.JWTService, nil, cfg.RevocationStore), cfg.AuthHandler.Logout)
 		}
 
 		// Protected routes (cần authentication)
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware(cfg.JWTService))
+		protected.Use(middleware.AuthMiddleware(cfg.JWTService, cfg.PATAuthenticator, cfg.RevocationStore))
 		{
 			// Health check với auth
 			protected.GET("/health", cfg.HealthHandler.DatabaseHealthCheck)
@@ -65,10 +126,120 @@ func NewRouter(cfg *RouterConfig) *gin.Engine {
 			{
 				users.GET("/me", cfg.UserHandler.GetProfile)
 				users.PUT("/me", cfg.UserHandler.UpdateProfile)
-				users.POST("/change-password", cfg.UserHandler.ChangePassword)
+				users.POST("/change-password", interactiveOnly, cfg.UserHandler.ChangePassword)
+
+				// MFA routes
+				mfa := users.Group("/me/mfa")
+				mfa.Use(interactiveOnly)
+				{
+					mfa.POST("/enroll", cfg.MFAHandler.Enroll)
+					mfa.POST("/confirm", cfg.MFAHandler.Confirm)
+					mfa.POST("/disable", cfg.MFAHandler.Disable)
+				}
+
+				// Session routes
+				sessions := users.Group("/me/sessions")
+				sessions.Use(interactiveOnly)
+				{
+					sessions.GET("", cfg.SessionHandler.ListSessions)
+					sessions.DELETE("/:id", cfg.SessionHandler.RevokeSession)
+					sessions.POST("/revoke-others", cfg.SessionHandler.RevokeOtherSessions)
+				}
+
+				// Personal access token routes
+				tokens := users.Group("/me/tokens")
+				tokens.Use(interactiveOnly)
+				{
+					tokens.GET("", cfg.TokenHandler.List)
+					tokens.POST("", cfg.TokenHandler.Create)
+					tokens.DELETE("/:id", cfg.TokenHandler.Revoke)
+				}
+
+				// Identity provider đã liên kết
+				identities := users.Group("/me/identities")
+				identities.Use(interactiveOnly)
+				{
+					identities.GET("", cfg.OAuthHandler.ListIdentities)
+					identities.POST("/:provider/link", cfg.OAuthHandler.Link)
+					identities.DELETE("/:id", cfg.OAuthHandler.UnlinkIdentity)
+				}
+
+				// Admin routes (theo permission)
+				users.GET("", middleware.RequirePermission(domain.PermissionUsersRead), cfg.UserHandler.ListUsers)
+				users.GET("/:id", middleware.RequirePermission(domain.PermissionUsersRead), cfg.UserHandler.GetUser)
+				users.PATCH("/:id", middleware.RequirePermission(domain.PermissionUsersWrite), cfg.UserHandler.AdminUpdateUser)
+				users.DELETE("/:id", middleware.RequirePermission(domain.PermissionUsersWrite), cfg.UserHandler.DeleteUser)
+				users.PUT("/:id/role", middleware.RequirePermission(domain.PermissionUsersWrite, domain.PermissionRolesWrite), cfg.UserHandler.ChangeRole)
+				users.PUT("/:id/status", middleware.RequirePermission(domain.PermissionUsersWrite), cfg.UserHandler.ChangeStatus)
+				users.POST("/:id/force-password-reset", middleware.RequirePermission(domain.PermissionUsersWrite), cfg.UserHandler.ForcePasswordReset)
+				users.POST("/:id/revoke-sessions", middleware.RequirePermission(domain.PermissionUsersWrite), cfg.UserHandler.RevokeSessions)
+				users.GET("/:id/lockout", middleware.RequirePermission(domain.PermissionUsersRead), cfg.UserHandler.GetLockoutStatus)
+				users.DELETE("/:id/lockout", middleware.RequirePermission(domain.PermissionUsersWrite), cfg.UserHandler.Unlock)
+				users.GET("/:id/roles", middleware.RequirePermission(domain.PermissionRolesRead), cfg.RoleHandler.GetUserRoles)
+				users.PUT("/:id/roles", middleware.RequirePermission(domain.PermissionRolesWrite), cfg.RoleHandler.SetUserRoles)
+			}
 
-				// Admin only routes
-				users.GET("", middleware.RequireRoles("admin"), cfg.UserHandler.ListUsers)
+			// Role routes
+			roles := protected.Group("/roles")
+			{
+				roles.GET("", middleware.RequirePermission(domain.PermissionRolesRead), cfg.RoleHandler.ListRoles)
+				roles.POST("", middleware.RequirePermission(domain.PermissionRolesWrite), cfg.RoleHandler.CreateRole)
+				roles.PUT("/:id", middleware.RequirePermission(domain.PermissionRolesWrite), cfg.RoleHandler.UpdateRole)
+				roles.DELETE("/:id", middleware.RequirePermission(domain.PermissionRolesWrite), cfg.RoleHandler.DeleteRole)
+			}
+			protected.GET("/permissions", middleware.RequirePermission(domain.PermissionRolesRead), cfg.RoleHandler.ListPermissions)
+
+			// Audit log routes
+			audit := protected.Group("/audit-logs", middleware.RequirePermission(domain.PermissionAuditRead))
+			{
+				audit.GET("", cfg.AuditHandler.ListLogs)
+				audit.GET("/export", cfg.AuditHandler.Export)
+				audit.GET("/verify", cfg.AuditHandler.VerifyChain)
+			}
+
+			// Webhook routes
+			webhooks := protected.Group("/webhooks")
+			{
+				webhooks.GET("", middleware.RequirePermission(domain.PermissionWebhooksRead), cfg.WebhookHandler.ListEndpoints)
+				webhooks.POST("", middleware.RequirePermission(domain.PermissionWebhooksWrite), cfg.WebhookHandler.CreateEndpoint)
+				webhooks.GET("/deliveries/:deliveryId", middleware.RequirePermission(domain.PermissionWebhooksRead), cfg.WebhookHandler.GetDelivery)
+				webhooks.POST("/deliveries/:deliveryId/redeliver", middleware.RequirePermission(domain.PermissionWebhooksWrite), cfg.WebhookHandler.Redeliver)
+				webhooks.GET("/:id", middleware.RequirePermission(domain.PermissionWebhooksRead), cfg.WebhookHandler.GetEndpoint)
+				webhooks.PUT("/:id", middleware.RequirePermission(domain.PermissionWebhooksWrite), cfg.WebhookHandler.UpdateEndpoint)
+				webhooks.DELETE("/:id", middleware.RequirePermission(domain.PermissionWebhooksWrite), cfg.WebhookHandler.DeleteEndpoint)
+				webhooks.POST("/:id/rotate-secret", middleware.RequirePermission(domain.PermissionWebhooksWrite), cfg.WebhookHandler.RotateSecret)
+				webhooks.GET("/:id/deliveries", middleware.RequirePermission(domain.PermissionWebhooksRead), cfg.WebhookHandler.ListDeliveries)
+			}
+
+			// OAuth routes: consent của user và quản lý client (admin)
+			oauthRoutes := protected.Group("/oauth")
+			{
+				oauthRoutes.GET("/consent", interactiveOnly, cfg.OAuthServer.GetConsent)
+				oauthRoutes.POST("/consent", interactiveOnly, cfg.OAuthServer.Consent)
+				oauthRoutes.GET("/clients", middleware.RequirePermission(domain.PermissionOAuthClientsRead), cfg.OAuthClient.ListClients)
+				oauthRoutes.POST("/clients", middleware.RequirePermission(domain.PermissionOAuthClientsWrite), cfg.OAuthClient.CreateClient)
+				oauthRoutes.GET("/clients/:id", middleware.RequirePermission(domain.PermissionOAuthClientsRead), cfg.OAuthClient.GetClient)
+				oauthRoutes.PUT("/clients/:id", middleware.RequirePermission(domain.PermissionOAuthClientsWrite), cfg.OAuthClient.UpdateClient)
+				oauthRoutes.DELETE("/clients/:id", middleware.RequirePermission(domain.PermissionOAuthClientsWrite), cfg.OAuthClient.DeleteClient)
+				oauthRoutes.POST("/clients/:id/rotate-secret", middleware.RequirePermission(domain.PermissionOAuthClientsWrite), cfg.OAuthClient.RotateSecret)
+			}
+
+			// Organization routes (quyền theo role trong organization, kiểm tra ở usecase)
+			orgs := protected.Group("/organizations")
+			{
+				orgs.GET("", cfg.OrgHandler.List)
+				orgs.POST("", cfg.OrgHandler.Create)
+				orgs.POST("/invitations/accept", cfg.OrgHandler.AcceptInvitation)
+				orgs.GET("/:id", cfg.OrgHandler.Get)
+				orgs.PUT("/:id", cfg.OrgHandler.Update)
+				orgs.DELETE("/:id", interactiveOnly, cfg.OrgHandler.Delete)
+				orgs.POST("/:id/switch", interactiveOnly, cfg.OrgHandler.Switch)
+				orgs.GET("/:id/members", cfg.OrgHandler.ListMembers)
+				orgs.PUT("/:id/members/:userId", cfg.OrgHandler.UpdateMember)
+				orgs.DELETE("/:id/members/:userId", cfg.OrgHandler.RemoveMember)
+				orgs.GET("/:id/invitations", cfg.OrgHandler.ListInvitations)
+				orgs.POST("/:id/invitations", cfg.OrgHandler.CreateInvitation)
+				orgs.DELETE("/:id/invitations/:invitationId", cfg.OrgHandler.RevokeInvitation)
 			}
 		}
 	}
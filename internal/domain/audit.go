@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kết quả của hành động được ghi audit
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// Các hành động được ghi audit
+const (
+	AuditActionRegister             = "auth.register"
+	AuditActionLogin                = "auth.login"
+	AuditActionMFAVerify            = "auth.mfa_verify"
+	AuditActionLogout               = "auth.logout"
+	AuditActionRefreshTokenReuse    = "auth.refresh_token_reuse"
+	AuditActionPasswordResetRequest = "auth.password_reset_request"
+	AuditActionPasswordReset        = "auth.password_reset"
+	AuditActionEmailVerify          = "auth.email_verify"
+	AuditActionOAuthLogin           = "auth.oauth_login"
+	AuditActionOAuthConsent         = "oauth.consent"
+	AuditActionOAuthCodeExchange    = "oauth.code_exchange"
+	AuditActionClientCredentials    = "oauth.client_credentials"
+	AuditActionTokenRevoke          = "oauth.token_revoke"
+	AuditActionProfileUpdate        = "user.profile_update"
+	AuditActionPasswordChange       = "user.password_change"
+	AuditActionIdentityLink         = "user.identity_link"
+	AuditActionIdentityUnlink       = "user.identity_unlink"
+	AuditActionMFAEnable            = "user.mfa_enable"
+	AuditActionMFADisable           = "user.mfa_disable"
+	AuditActionPersonalTokenCreate  = "user.personal_access_token_create"
+	AuditActionPersonalTokenRevoke  = "user.personal_access_token_revoke"
+	AuditActionUserUpdate           = "admin.user_update"
+	AuditActionUserDelete           = "admin.user_delete"
+	AuditActionRoleChange           = "admin.role_change"
+	AuditActionRoleCreate           = "admin.role_create"
+	AuditActionRoleUpdate           = "admin.role_update"
+	AuditActionRoleDelete           = "admin.role_delete"
+	AuditActionUserRolesSet         = "admin.user_roles_set"
+	AuditActionStatusChange         = "admin.status_change"
+	AuditActionForcePasswordReset   = "admin.force_password_reset"
+	AuditActionRevokeSessions       = "admin.revoke_sessions"
+	AuditActionUnlock               = "admin.unlock"
+	AuditActionOAuthClientCreate    = "admin.oauth_client_create"
+	AuditActionOAuthClientUpdate    = "admin.oauth_client_update"
+	AuditActionOAuthClientDelete    = "admin.oauth_client_delete"
+	AuditActionOAuthClientRotate    = "admin.oauth_client_rotate_secret"
+	AuditActionOAuthClientGrant     = "admin.oauth_client_scope_grant"
+	AuditActionWebhookCreate        = "admin.webhook_create"
+	AuditActionWebhookUpdate        = "admin.webhook_update"
+	AuditActionWebhookDelete        = "admin.webhook_delete"
+	AuditActionWebhookRotate        = "admin.webhook_rotate_secret"
+)
+
+// AuditLog - 1 sự kiện bảo mật (chỉ thêm, không sửa/xóa)
+// Hash = SHA-256(PrevHash + nội dung) nên sửa/xóa 1 dòng sẽ làm gãy chuỗi từ dòng đó trở đi
+type AuditLog struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	ActorID   *uint     `json:"actor_id"`  // User thực hiện (nil = chưa đăng nhập / hệ thống)
+	TargetID  *uint     `json:"target_id"` // User bị tác động
+	Action    string    `json:"action" gorm:"not null"`
+	Outcome   string    `json:"outcome" gorm:"type:enum('success','failure');not null"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	RequestID string    `json:"request_id"`
+	Metadata  string    `json:"metadata"` // JSON object, chi tiết thêm (lý do, field thay đổi...)
+	PrevHash  string    `json:"prev_hash" gorm:"not null"`
+	Hash      string    `json:"hash" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ComputeHash - tính hash của dòng dựa trên PrevHash và toàn bộ nội dung (trừ ID, Hash)
+// CreatedAt làm tròn tới giây để khớp với giá trị đọc lại từ database
+func (l *AuditLog) ComputeHash() string {
+	fields := []string{
+		l.PrevHash,
+		optionalID(l.ActorID),
+		optionalID(l.TargetID),
+		l.Action,
+		l.Outcome,
+		l.IPAddress,
+		l.UserAgent,
+		l.RequestID,
+		l.Metadata,
+		l.CreatedAt.UTC().Truncate(time.Second).Format(time.RFC3339),
+	}
+
+	// Mỗi field có độ dài đứng trước để không ghép nhầm ranh giới giữa các field
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteString(strconv.Itoa(len(f)))
+		b.WriteByte(':')
+		b.WriteString(f)
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func optionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// AuditEvent - sự kiện usecase gửi vào audit log (IP, user agent, request ID lấy từ context)
+type AuditEvent struct {
+	ActorID  *uint
+	TargetID *uint
+	Action   string
+	Outcome  string
+	Metadata map[string]interface{}
+}
+
+// RequestMeta - thông tin request dùng cho audit log
+// LoggerMiddleware tạo và gắn vào context, AuthMiddleware điền ActorID sau khi xác thực
+type RequestMeta struct {
+	RequestID string
+	IPAddress string
+	UserAgent string
+	ActorID   *uint
+}
+
+type requestMetaKey struct{}
+
+// WithRequestMeta - gắn thông tin request vào context
+func WithRequestMeta(ctx context.Context, meta *RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// RequestMetaFromContext - lấy thông tin request (nil nếu không phải HTTP request)
+func RequestMetaFromContext(ctx context.Context) *RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(*RequestMeta)
+	return meta
+}
+
+// ListAuditLogsRequest - bộ lọc audit log (admin)
+type ListAuditLogsRequest struct {
+	Page      int        `form:"page" validate:"min=1"`
+	Limit     int        `form:"limit" validate:"min=1,max=100"`
+	ActorID   *uint      `form:"actor_id"`
+	TargetID  *uint      `form:"target_id"`
+	Action    string     `form:"action"`
+	Outcome   string     `form:"outcome" validate:"omitempty,oneof=success failure"`
+	RequestID string     `form:"request_id"`
+	IPAddress string     `form:"ip_address"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// PaginatedAuditLogsResponse - kết quả danh sách audit log có phân trang
+type PaginatedAuditLogsResponse struct {
+	Logs       []AuditLog `json:"logs"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// AuditChainVerification - kết quả kiểm tra tính toàn vẹn của chuỗi hash
+type AuditChainVerification struct {
+	Valid    bool    `json:"valid"`
+	Checked  int64   `json:"checked"`             // Số dòng đã kiểm tra
+	BrokenAt *uint64 `json:"broken_at,omitempty"` // ID dòng đầu tiên bị sửa / thiếu dòng trước
+	LastHash string  `json:"last_hash,omitempty"` // Hash dòng cuối, lưu ra ngoài để phát hiện bị cắt đuôi
+}
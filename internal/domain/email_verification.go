@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+// EmailVerification - struct cho token xác thực email
+type EmailVerification struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null"`
+	Token     string    `json:"-" gorm:"uniqueIndex;not null"` // Digest (SHA-256/HMAC) của token, không lưu token gốc
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	Used      bool      `json:"used" gorm:"default:false"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// VerifyEmailRequest - dữ liệu khi xác thực email
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResendVerificationRequest - dữ liệu khi gửi lại email xác thực
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
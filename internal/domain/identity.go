@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+)
+
+// UserIdentity - liên kết user với tài khoản ở identity provider bên ngoài (Google, GitHub...)
+// Mỗi cặp (provider, subject) chỉ thuộc về 1 user
+type UserIdentity struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	UserID      uint       `json:"-" gorm:"not null;index"`
+	Provider    string     `json:"provider" gorm:"size:50;not null"`
+	Subject     string     `json:"subject" gorm:"size:255;not null"` // ID của user tại provider (claim "sub")
+	Email       string     `json:"email"`                            // Email provider trả về lúc liên kết
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OAuthState - 1 lần đăng nhập OAuth đang chờ callback (dùng 1 lần)
+type OAuthState struct {
+	ID           uint      `gorm:"primaryKey"`
+	StateHash    string    `gorm:"uniqueIndex;not null"` // Digest của state (state gốc nằm trong URL và cookie)
+	Provider     string    `gorm:"size:50;not null"`
+	CodeVerifier string    `gorm:"not null"` // PKCE verifier, gửi kèm code ở bước đổi token
+	Nonce        string    `gorm:"not null"`
+	UserID       *uint     // Khác nil -> liên kết provider vào tài khoản này thay vì đăng nhập
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time
+}
+
+// TableName - tên bảng (GORM mặc định sẽ là o_auth_states)
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
+
+// OAuthStartResponse - URL chuyển hướng user sang provider
+type OAuthStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// OAuthCallbackRequest - query provider gửi về callback
+type OAuthCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state" validate:"required"`
+	Error            string `form:"error"` // User từ chối hoặc provider báo lỗi
+	ErrorDescription string `form:"error_description"`
+}
+
+// OAuthCallbackResponse - kết quả callback: đăng nhập (tokens / MFA challenge) hoặc liên kết tài khoản
+type OAuthCallbackResponse struct {
+	*LoginResponse
+	LinkedIdentity *UserIdentity `json:"linked_identity,omitempty"`
+}
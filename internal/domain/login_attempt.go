@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+)
+
+// Loại đối tượng bị theo dõi login thất bại
+const (
+	LoginAttemptSubjectEmail = "email" // Theo tài khoản (email đã normalize)
+	LoginAttemptSubjectIP    = "ip"    // Theo địa chỉ IP
+)
+
+// LoginAttempt - số lần login thất bại liên tiếp của 1 email hoặc 1 IP
+type LoginAttempt struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	SubjectType  string     `json:"subject_type" gorm:"type:enum('email','ip');not null"`
+	Subject      string     `json:"subject" gorm:"not null"`
+	FailedCount  int        `json:"failed_count" gorm:"default:0"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"` // Không cho login trước thời điểm này (back-off hoặc lock)
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// LockoutStatusResponse - trạng thái khóa login của user (cho admin)
+type LockoutStatusResponse struct {
+	UserID         uint       `json:"user_id"`
+	Locked         bool       `json:"locked"`
+	FailedAttempts int        `json:"failed_attempts"`
+	LastFailedAt   *time.Time `json:"last_failed_at"`
+	LockedUntil    *time.Time `json:"locked_until"`
+}
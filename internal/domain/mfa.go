@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+)
+
+// MFABackupCode - mã dự phòng dùng 1 lần khi mất thiết bị TOTP
+type MFABackupCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null"`
+	CodeHash  string     `json:"-" gorm:"not null"` // SHA-256 của backup code
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MFAEnrollResponse - dữ liệu trả về khi bắt đầu đăng ký MFA
+type MFAEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"` // Dùng để tạo QR code
+}
+
+// MFABackupCodesResponse - danh sách backup codes (chỉ hiển thị 1 lần)
+type MFABackupCodesResponse struct {
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// ConfirmMFARequest - dữ liệu khi xác nhận đăng ký MFA
+type ConfirmMFARequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// DisableMFARequest - dữ liệu khi tắt MFA
+type DisableMFARequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"` // Mã TOTP hoặc backup code
+}
+
+// VerifyMFARequest - dữ liệu khi hoàn tất login bằng MFA
+type VerifyMFARequest struct {
+	MFAToken   string `json:"mfa_token" validate:"required"`
+	Code       string `json:"code" validate:"required"` // Mã TOTP hoặc backup code
+	DeviceName string `json:"device_name" validate:"max=100"`
+}
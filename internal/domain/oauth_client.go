@@ -0,0 +1,262 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Scope OpenID Connect mà authorization server hỗ trợ
+const (
+	OIDCScopeOpenID  = "openid"  // Bắt buộc để nhận ID token
+	OIDCScopeProfile = "profile" // name
+	OIDCScopeEmail   = "email"   // email, email_verified
+)
+
+// OIDCScopes - các scope client được phép đăng ký
+var OIDCScopes = []string{OIDCScopeOpenID, OIDCScopeProfile, OIDCScopeEmail}
+
+// Grant type client được phép dùng
+const (
+	GrantTypeAuthorizationCode = "authorization_code" // App đăng nhập user (OpenID Connect)
+	GrantTypeClientCredentials = "client_credentials" // Service gọi API bằng danh tính của chính nó, scope là tên permission
+)
+
+// OAuthClient - ứng dụng (relying party) đăng nhập user qua authorization server này
+type OAuthClient struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ClientID     string    `json:"client_id" gorm:"uniqueIndex;not null"`
+	SecretHash   string    `json:"-"` // Digest của client secret, rỗng với public client
+	Name         string    `json:"name" gorm:"not null"`
+	RedirectURIs string    `json:"-" gorm:"type:text;not null"` // Phân cách bằng xuống dòng, so khớp chính xác
+	Scopes       string    `json:"-" gorm:"not null"`           // Scope được phép, phân cách bằng khoảng trắng
+	GrantTypes   string    `json:"-" gorm:"not null"`           // Grant type được phép, phân cách bằng khoảng trắng
+	Public       bool      `json:"public" gorm:"default:false"` // SPA / app mobile: không có secret, chỉ dựa vào PKCE
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName - tên bảng (GORM mặc định sẽ là o_auth_clients)
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+// RedirectURIList - danh sách redirect URI đã đăng ký
+func (c *OAuthClient) RedirectURIList() []string {
+	if c.RedirectURIs == "" {
+		return nil
+	}
+	return strings.Split(c.RedirectURIs, "\n")
+}
+
+// ScopeList - danh sách scope được phép
+func (c *OAuthClient) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// GrantTypeList - danh sách grant type được phép
+func (c *OAuthClient) GrantTypeList() []string {
+	return strings.Fields(c.GrantTypes)
+}
+
+// AllowsGrant - client được phép dùng grant type
+func (c *OAuthClient) AllowsGrant(grantType string) bool {
+	for _, g := range c.GrantTypeList() {
+		if g == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// ToResponse - chuyển sang response (không có secret)
+func (c *OAuthClient) ToResponse() *OAuthClientResponse {
+	return &OAuthClientResponse{
+		OAuthClient:  c,
+		RedirectURIs: c.RedirectURIList(),
+		Scopes:       c.ScopeList(),
+		GrantTypes:   c.GrantTypeList(),
+	}
+}
+
+// OAuthAuthorizationCode - authorization code chờ client đổi lấy token (dùng 1 lần, sống rất ngắn)
+type OAuthAuthorizationCode struct {
+	ID            uint      `gorm:"primaryKey"`
+	CodeHash      string    `gorm:"uniqueIndex;not null"`
+	ClientID      string    `gorm:"not null"`
+	UserID        uint      `gorm:"not null"`
+	RedirectURI   string    `gorm:"not null"`
+	Scope         string    `gorm:"not null"`
+	Nonce         string    `gorm:"not null"`
+	CodeChallenge string    `gorm:"not null"` // PKCE S256
+	AuthTime      time.Time `gorm:"not null"` // Thời điểm user xác nhận, đưa vào claim auth_time
+	ExpiresAt     time.Time `gorm:"not null"`
+	Used          bool      `gorm:"default:false"`
+	CreatedAt     time.Time
+}
+
+// TableName - tên bảng (GORM mặc định sẽ là o_auth_authorization_codes)
+func (OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+// OAuthConsent - scope user đã đồng ý cấp cho client
+type OAuthConsent struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null"`
+	ClientID  string `gorm:"not null"`
+	Scope     string `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName - tên bảng (GORM mặc định sẽ là o_auth_consents)
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+// Covers - consent đã bao gồm tất cả scope yêu cầu
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	granted := strings.Fields(c.Scope)
+	for _, scope := range scopes {
+		found := false
+		for _, g := range granted {
+			if g == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateOAuthClientRequest - dữ liệu khi đăng ký client
+type CreateOAuthClientRequest struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,max=10,dive,required,max=2048"`                  // Bắt buộc với authorization_code
+	Scopes       []string `json:"scopes" validate:"omitempty,max=50,dive,required,max=100"`                          // Scope OIDC hoặc tên permission (client_credentials), rỗng -> scope OIDC
+	GrantTypes   []string `json:"grant_types" validate:"omitempty,dive,oneof=authorization_code client_credentials"` // Rỗng -> authorization_code
+	Public       bool     `json:"public"`
+}
+
+// UpdateOAuthClientRequest - dữ liệu khi cập nhật client (field nil/rỗng = giữ nguyên)
+type UpdateOAuthClientRequest struct {
+	Name         *string  `json:"name" validate:"omitempty,min=1,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,max=10,dive,required,max=2048"`
+	Scopes       []string `json:"scopes" validate:"omitempty,max=50,dive,required,max=100"`
+	GrantTypes   []string `json:"grant_types" validate:"omitempty,dive,oneof=authorization_code client_credentials"`
+}
+
+// OAuthClientResponse - thông tin client (ClientSecret chỉ có khi tạo / đổi secret)
+type OAuthClientResponse struct {
+	*OAuthClient
+	RedirectURIs []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+	GrantTypes   []string `json:"grant_types"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+}
+
+// AuthorizeRequest - tham số authorization request (RFC 6749 4.1.1 + PKCE + OIDC nonce)
+type AuthorizeRequest struct {
+	ResponseType        string `form:"response_type" json:"response_type"`
+	ClientID            string `form:"client_id" json:"client_id" validate:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state" validate:"max=512"`
+	Nonce               string `form:"nonce" json:"nonce" validate:"max=255"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+// OAuthConsentRequest - user đồng ý / từ chối authorization request
+type OAuthConsentRequest struct {
+	AuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+// OAuthConsentInfoResponse - thông tin hiển thị trên trang consent
+type OAuthConsentInfoResponse struct {
+	ClientID     string   `json:"client_id"`
+	ClientName   string   `json:"client_name"`
+	Scopes       []string `json:"scopes"`
+	ConsentGiven bool     `json:"consent_given"` // Đã đồng ý các scope này trước đó, frontend có thể tự xác nhận
+}
+
+// OAuthConsentResponse - URL chuyển trình duyệt về client (kèm code hoặc error)
+type OAuthConsentResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// OAuthTokenRequest - tham số token endpoint (form-urlencoded)
+type OAuthTokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Scope        string `form:"scope"` // client_credentials: rỗng -> tất cả scope được phép
+}
+
+// OAuthTokenResponse - response của token endpoint (RFC 6749 5.1)
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	IDToken     string `json:"id_token,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// Giá trị token_type_hint (RFC 7009 2.1, RFC 7662 2.1)
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+)
+
+// OAuthTokenActionRequest - tham số introspection / revocation endpoint (form-urlencoded)
+type OAuthTokenActionRequest struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"` // access_token | refresh_token, chỉ là gợi ý thứ tự tìm
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// IntrospectionResponse - response của introspection endpoint (RFC 7662 2.2)
+// Token không hợp lệ hoặc client không được xem -> chỉ có active=false
+type IntrospectionResponse struct {
+	Active      bool     `json:"active"`
+	Scope       string   `json:"scope,omitempty"`
+	ClientID    string   `json:"client_id,omitempty"`
+	Username    string   `json:"username,omitempty"`
+	TokenType   string   `json:"token_type,omitempty"` // Bearer với access token, refresh token để trống
+	Exp         int64    `json:"exp,omitempty"`
+	Iat         int64    `json:"iat,omitempty"`
+	Nbf         int64    `json:"nbf,omitempty"`
+	Sub         string   `json:"sub,omitempty"`
+	Aud         []string `json:"aud,omitempty"`
+	Iss         string   `json:"iss,omitempty"`
+	Jti         string   `json:"jti,omitempty"`
+	SubjectType string   `json:"sub_type,omitempty"` // user | service
+	SessionID   string   `json:"sid,omitempty"`
+}
+
+// OpenIDConfiguration - metadata ở /.well-known/openid-configuration
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
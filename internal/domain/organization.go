@@ -0,0 +1,133 @@
+package domain
+
+import "time"
+
+// Role của user trong organization
+const (
+	OrgRoleOwner  = "owner"  // Toàn quyền, xóa được organization
+	OrgRoleAdmin  = "admin"  // Quản lý thành viên và lời mời
+	OrgRoleMember = "member" // Thành viên thường
+)
+
+// Organization - công ty/tổ chức (tenant), user có thể thuộc nhiều organization
+type Organization struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrganizationMember - user thuộc organization với role riêng trong organization đó
+type OrganizationMember struct {
+	OrganizationID uint      `json:"organization_id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"primaryKey"`
+	Role           string    `json:"role" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// IsManager - owner/admin được quản lý thành viên
+func (m *OrganizationMember) IsManager() bool {
+	return m.Role == OrgRoleOwner || m.Role == OrgRoleAdmin
+}
+
+// OrganizationInvitation - lời mời tham gia organization gửi qua email
+type OrganizationInvitation struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	OrganizationID uint       `json:"organization_id" gorm:"not null"`
+	Email          string     `json:"email" gorm:"not null"`
+	Role           string     `json:"role" gorm:"not null"`
+	Token          string     `json:"-" gorm:"uniqueIndex;not null"` // Digest của token gửi trong email
+	InvitedBy      uint       `json:"invited_by" gorm:"not null"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"not null"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// CreateOrganizationRequest - dữ liệu tạo organization
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+	Slug string `json:"slug" validate:"omitempty,min=2,max=50"` // Bỏ trống -> tạo từ name
+}
+
+// UpdateOrganizationRequest - dữ liệu cập nhật organization
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+// UpdateMemberRequest - đổi role của thành viên
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+// CreateInvitationRequest - dữ liệu mời thành viên
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+// AcceptInvitationRequest - dữ liệu chấp nhận lời mời
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// OrganizationResponse - organization kèm role của user hiện tại
+type OrganizationResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Role      string    `json:"role"` // Role của user hiện tại trong organization
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrganizationMembership - organization + role của user (kết quả join)
+type OrganizationMembership struct {
+	Organization
+	Role string
+}
+
+// ToResponse - chuyển sang OrganizationResponse
+func (m *OrganizationMembership) ToResponse() *OrganizationResponse {
+	return &OrganizationResponse{
+		ID:        m.ID,
+		Name:      m.Name,
+		Slug:      m.Slug,
+		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// MemberResponse - thành viên organization
+type MemberResponse struct {
+	UserResponse
+	OrganizationRole string    `json:"organization_role"`
+	JoinedAt         time.Time `json:"joined_at"`
+}
+
+// OrganizationUser - user + thông tin membership (kết quả join)
+type OrganizationUser struct {
+	User
+	OrganizationRole string
+	JoinedAt         time.Time
+}
+
+// ToMemberResponse - chuyển sang MemberResponse
+func (u *OrganizationUser) ToMemberResponse() *MemberResponse {
+	return &MemberResponse{
+		UserResponse:     *u.User.ToResponse(),
+		OrganizationRole: u.OrganizationRole,
+		JoinedAt:         u.JoinedAt,
+	}
+}
+
+// PaginatedMembersResponse - danh sách thành viên có phân trang
+type PaginatedMembersResponse struct {
+	Members    []MemberResponse `json:"members"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+// SwitchOrganizationResponse - access token mới với organization đang làm việc
+type SwitchOrganizationResponse struct {
+	AccessToken    string `json:"access_token"`
+	OrganizationID uint   `json:"organization_id"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+)
+
+// PasswordHistory - hash các password đã dùng của user (chống dùng lại password cũ)
+type PasswordHistory struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"not null"`
+	PasswordHash string    `json:"-" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName - tên bảng (GORM mặc định sẽ là password_histories)
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
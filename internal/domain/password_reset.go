@@ -8,7 +8,7 @@ import (
 type PasswordReset struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	Email     string    `json:"email" gorm:"not null"`
-	Token     string    `json:"token" gorm:"uniqueIndex;not null"`
+	Token     string    `json:"-" gorm:"uniqueIndex;not null"` // Digest (SHA-256/HMAC) của token, không lưu token gốc
 	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
 	Used      bool      `json:"used" gorm:"default:false"`
 	CreatedAt time.Time `json:"created_at"`
@@ -22,5 +22,5 @@ type ForgotPasswordRequest struct {
 // ResetPasswordRequest - dữ liệu khi reset password
 type ResetPasswordRequest struct {
 	Token       string `json:"token" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=6"`
+	NewPassword string `json:"new_password" validate:"required"` // Kiểm tra bằng password policy
 }
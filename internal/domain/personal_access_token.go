@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// PersonalAccessTokenPrefix - prefix nhận diện personal access token (phân biệt với JWT)
+const PersonalAccessTokenPrefix = "gga_"
+
+// Scope của personal access token
+const (
+	TokenScopeRead  = "read"  // Chỉ gọi API GET/HEAD
+	TokenScopeWrite = "write" // Gọi tất cả API
+)
+
+// PersonalAccessToken - token dài hạn cho script/CI, gọi API thay cho user
+type PersonalAccessToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null"`
+	Name       string     `json:"name" gorm:"not null"`
+	TokenHash  string     `json:"-" gorm:"uniqueIndex;not null"` // Digest của token, token gốc chỉ trả về 1 lần khi tạo
+	Prefix     string     `json:"prefix" gorm:"not null"`        // Vài ký tự đầu để user nhận ra token
+	Scopes     string     `json:"scopes" gorm:"not null"`        // Phân cách bằng dấu phẩy
+	ExpiresAt  *time.Time `json:"expires_at"`                    // nil = không hết hạn
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ScopeList - danh sách scope
+func (t *PersonalAccessToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return nil
+	}
+	return strings.Split(t.Scopes, ",")
+}
+
+// IsActive - token chưa bị revoke và chưa hết hạn
+func (t *PersonalAccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || t.ExpiresAt.After(now))
+}
+
+// PersonalAccessTokenAuth - kết quả xác thực personal access token
+type PersonalAccessTokenAuth struct {
+	Token       *PersonalAccessToken
+	User        *User
+	Permissions []string
+}
+
+// CreatePersonalAccessTokenRequest - dữ liệu khi tạo token
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=read write"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"min=0,max=3650"` // 0 = không hết hạn
+}
+
+// PersonalAccessTokenResponse - thông tin token (không có token gốc)
+type PersonalAccessTokenResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatePersonalAccessTokenResponse - kết quả tạo token (token gốc chỉ trả về lần này)
+type CreatePersonalAccessTokenResponse struct {
+	Token string `json:"token"`
+	*PersonalAccessTokenResponse
+}
+
+// ToResponse - chuyển sang PersonalAccessTokenResponse
+func (t *PersonalAccessToken) ToResponse() *PersonalAccessTokenResponse {
+	return &PersonalAccessTokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     t.ScopeList(),
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		LastUsedIP: t.LastUsedIP,
+		CreatedAt:  t.CreatedAt,
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// Permission dùng trong middleware.RequirePermission (tạo sẵn bằng migration)
+const (
+	PermissionUsersRead         = "users:read"
+	PermissionUsersWrite        = "users:write"
+	PermissionRolesRead         = "roles:read"
+	PermissionRolesWrite        = "roles:write"
+	PermissionAuditRead         = "audit:read"
+	PermissionWebhooksRead      = "webhooks:read"
+	PermissionWebhooksWrite     = "webhooks:write"
+	PermissionOAuthClientsRead  = "oauth_clients:read"
+	PermissionOAuthClientsWrite = "oauth_clients:write"
+	PermissionTokensIntrospect  = "tokens:introspect" // Scope của service: introspect token cấp cho client khác
+	PermissionTokensRevoke      = "tokens:revoke"     // Scope của service: revoke token cấp cho client khác
+)
+
+// Role - nhóm permission, gán cho user qua users.role (role chính) hoặc user_roles
+type Role struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	Name        string       `json:"name" gorm:"uniqueIndex;not null"`
+	Description string       `json:"description"`
+	IsSystem    bool         `json:"is_system" gorm:"default:false"` // Role có sẵn, không được xóa/đổi tên
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Permission - quyền thực hiện 1 nhóm API, dạng "<resource>:<action>"
+type Permission struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"uniqueIndex;not null"`
+	Description string `json:"description"`
+}
+
+// UserRole - role gán thêm cho user
+type UserRole struct {
+	UserID    uint `gorm:"primaryKey"`
+	RoleID    uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
+
+// CreateRoleRequest - dữ liệu tạo role
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=50"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required"`
+}
+
+// UpdateRoleRequest - dữ liệu cập nhật role (thay toàn bộ permission)
+type UpdateRoleRequest struct {
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required"`
+}
+
+// SetUserRolesRequest - thay toàn bộ role gán thêm của user
+type SetUserRolesRequest struct {
+	Roles []string `json:"roles" validate:"dive,required"`
+}
+
+// UserRolesResponse - role và permission hiệu lực của user
+type UserRolesResponse struct {
+	UserID      uint     `json:"user_id"`
+	PrimaryRole string   `json:"primary_role"`
+	Roles       []string `json:"roles"`       // Role gán thêm
+	Permissions []string `json:"permissions"` // Gộp từ role chính + role gán thêm
+}
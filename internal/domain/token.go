@@ -5,16 +5,62 @@ import (
 )
 
 // RefreshToken - struct cho refresh token trong database
+// Mỗi lần login tạo 1 family mới, mỗi lần rotate tạo token con cùng family
 type RefreshToken struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id" gorm:"not null"`
-	Token     string    `json:"token" gorm:"uniqueIndex;not null"`
-	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
-	Revoked   bool      `json:"revoked" gorm:"default:false"`
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"not null"`
+	Token          string     `json:"-" gorm:"uniqueIndex;not null"`   // Digest (SHA-256/HMAC) của token, không lưu token gốc
+	FamilyID       string     `json:"family_id" gorm:"index;not null"` // Chuỗi token sinh ra từ cùng 1 lần login
+	ParentID       *uint      `json:"parent_id"`                       // Token đã bị rotate để sinh ra token này
+	OrganizationID *uint      `json:"organization_id"`                 // Organization đang làm việc của session
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"not null"`
+	Revoked        bool       `json:"revoked" gorm:"default:false"`
+	RotatedAt      *time.Time `json:"rotated_at"` // Khác nil nghĩa là token đã được đổi sang token mới
+
+	// Thông tin thiết bị của session
+	UserAgent       string    `json:"user_agent"`
+	IPAddress       string    `json:"ip_address"`
+	DeviceName      string    `json:"device_name"`
+	AuthenticatedAt time.Time `json:"authenticated_at"` // Thời điểm login ban đầu của family
+	LastUsedAt      time.Time `json:"last_used_at"`
+
 	CreatedAt time.Time `json:"created_at"`
 	User      User      `json:"user" gorm:"foreignKey:UserID"` // Quan hệ với bảng users
 }
 
+// ClientInfo - thông tin client gửi request (lấy từ HTTP layer)
+type ClientInfo struct {
+	IPAddress  string
+	UserAgent  string
+	DeviceName string
+}
+
+// SessionResponse - 1 session đang đăng nhập (tương ứng 1 refresh token family)
+type SessionResponse struct {
+	ID              string    `json:"id"`
+	DeviceName      string    `json:"device_name"`
+	UserAgent       string    `json:"user_agent"`
+	IPAddress       string    `json:"ip_address"`
+	AuthenticatedAt time.Time `json:"authenticated_at"`
+	LastUsedAt      time.Time `json:"last_used_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	Current         bool      `json:"current"` // Session của access token đang dùng
+}
+
+// ToSessionResponse - chuyển refresh token đang active sang SessionResponse
+func (t *RefreshToken) ToSessionResponse(currentSessionID string) *SessionResponse {
+	return &SessionResponse{
+		ID:              t.FamilyID,
+		DeviceName:      t.DeviceName,
+		UserAgent:       t.UserAgent,
+		IPAddress:       t.IPAddress,
+		AuthenticatedAt: t.AuthenticatedAt,
+		LastUsedAt:      t.LastUsedAt,
+		ExpiresAt:       t.ExpiresAt,
+		Current:         t.FamilyID == currentSessionID,
+	}
+}
+
 // TokenPair - cặp access token và refresh token
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
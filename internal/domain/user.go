@@ -4,17 +4,30 @@ import (
 	"time"
 )
 
+// Trạng thái tài khoản
+const (
+	UserStatusActive    = "active"
+	UserStatusInactive  = "inactive"
+	UserStatusSuspended = "suspended"
+)
+
 // User - struct đại diện cho user trong database
 type User struct {
-	ID              uint       `json:"id" gorm:"primaryKey"`
-	Email           string     `json:"email" gorm:"uniqueIndex;not null"`
-	PasswordHash    string     `json:"-" gorm:"not null"` // Dấu "-" nghĩa là không trả về trong JSON
-	FullName        string     `json:"full_name" gorm:"not null"`
-	Role            string     `json:"role" gorm:"type:enum('admin','user');default:'user'"`
-	Status          string     `json:"status" gorm:"type:enum('active','inactive','suspended');default:'active'"`
-	EmailVerifiedAt *time.Time `json:"email_verified_at"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
+	ID                    uint       `json:"id" gorm:"primaryKey"`
+	Email                 string     `json:"email" gorm:"uniqueIndex;not null"`
+	PasswordHash          string     `json:"-" gorm:"not null"` // Dấu "-" nghĩa là không trả về trong JSON
+	FullName              string     `json:"full_name" gorm:"not null"`
+	Role                  string     `json:"role" gorm:"size:50;default:'user'"` // Role chính (xem Role)
+	Status                string     `json:"status" gorm:"type:enum('active','inactive','suspended');default:'active'"`
+	StatusReason          string     `json:"status_reason"`                                // Lý do admin đổi trạng thái
+	SuspendedUntil        *time.Time `json:"suspended_until"`                              // Tạm khóa có thời hạn (nil = đến khi admin mở)
+	PasswordResetRequired bool       `json:"password_reset_required" gorm:"default:false"` // Admin yêu cầu đặt lại password trước khi login
+	EmailVerifiedAt       *time.Time `json:"email_verified_at"`
+	MFAEnabled            bool       `json:"mfa_enabled" gorm:"default:false"`
+	MFASecret             string     `json:"-"` // TOTP secret (base32)
+	MFALastStep           int64      `json:"-"` // Time step cuối cùng đã dùng, chống replay OTP
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
 }
 
 // DTO (Data Transfer Object) - dùng cho API request/response
@@ -22,14 +35,24 @@ type User struct {
 // RegisterRequest - dữ liệu khi user đăng ký
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required"` // Độ dài/độ mạnh kiểm tra bằng password policy
 	FullName string `json:"full_name" validate:"required,min=2,max=100"`
 }
 
 // LoginRequest - dữ liệu khi user login
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required"`
+	DeviceName string `json:"device_name" validate:"max=100"` // Tên thiết bị hiển thị trong danh sách session
+}
+
+// LoginResponse - kết quả login (tokens hoặc MFA challenge)
+type LoginResponse struct {
+	AccessToken  string        `json:"access_token,omitempty"`
+	RefreshToken string        `json:"refresh_token,omitempty"`
+	User         *UserResponse `json:"user,omitempty"`
+	MFARequired  bool          `json:"mfa_required"`
+	MFAToken     string        `json:"mfa_token,omitempty"` // Challenge token ngắn hạn, dùng cho /auth/mfa/verify
 }
 
 // UserResponse - dữ liệu trả về (không có password)
@@ -40,6 +63,7 @@ type UserResponse struct {
 	Role            string     `json:"role"`
 	Status          string     `json:"status"`
 	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	MFAEnabled      bool       `json:"mfa_enabled"`
 	CreatedAt       time.Time  `json:"created_at"`
 }
 
@@ -51,7 +75,7 @@ type UpdateProfileRequest struct {
 // ChangePasswordRequest - dữ liệu đổi password
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=6"`
+	NewPassword string `json:"new_password" validate:"required"` // Kiểm tra bằng password policy
 }
 
 // ListUsersRequest - dữ liệu lọc danh sách user
@@ -77,6 +101,53 @@ type Pagination struct {
 	TotalPages int   `json:"total_pages"`
 }
 
+// IsActive - tài khoản được phép đăng nhập (tạm khóa đã hết hạn coi như active)
+func (u *User) IsActive(now time.Time) bool {
+	if u.Status == UserStatusSuspended && u.SuspendedUntil != nil {
+		return !u.SuspendedUntil.After(now)
+	}
+	return u.Status == UserStatusActive
+}
+
+// AdminUpdateUserRequest - admin cập nhật thông tin user (chỉ field được gửi)
+type AdminUpdateUserRequest struct {
+	FullName      *string `json:"full_name" validate:"omitempty,min=2,max=100"`
+	Email         *string `json:"email" validate:"omitempty,email"`
+	EmailVerified *bool   `json:"email_verified"`
+}
+
+// ChangeRoleRequest - đổi role chính của user
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,max=50"`
+}
+
+// ChangeStatusRequest - đổi trạng thái tài khoản
+type ChangeStatusRequest struct {
+	Status         string     `json:"status" validate:"required,oneof=active inactive suspended"`
+	Reason         string     `json:"reason" validate:"max=255"`
+	SuspendedUntil *time.Time `json:"suspended_until"` // Chỉ dùng với suspended, nil = vô thời hạn
+}
+
+// AdminUserResponse - thông tin user cho admin
+type AdminUserResponse struct {
+	UserResponse
+	StatusReason          string     `json:"status_reason"`
+	SuspendedUntil        *time.Time `json:"suspended_until"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// ToAdminResponse - chuyển từ User entity sang AdminUserResponse
+func (u *User) ToAdminResponse() *AdminUserResponse {
+	return &AdminUserResponse{
+		UserResponse:          *u.ToResponse(),
+		StatusReason:          u.StatusReason,
+		SuspendedUntil:        u.SuspendedUntil,
+		PasswordResetRequired: u.PasswordResetRequired,
+		UpdatedAt:             u.UpdatedAt,
+	}
+}
+
 // ToResponse - chuyển từ User entity sang UserResponse
 func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
@@ -86,6 +157,7 @@ func (u *User) ToResponse() *UserResponse {
 		Role:            u.Role,
 		Status:          u.Status,
 		EmailVerifiedAt: u.EmailVerifiedAt,
+		MFAEnabled:      u.MFAEnabled,
 		CreatedAt:       u.CreatedAt,
 	}
 }
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Sự kiện gửi qua webhook
+const (
+	WebhookEventUserRegistered    = "user.registered"
+	WebhookEventUserEmailVerified = "user.email_verified"
+	WebhookEventUserRoleChanged   = "user.role_changed"
+	WebhookEventUserStatusChanged = "user.status_changed"
+	WebhookEventUserDeleted       = "user.deleted"
+)
+
+// WebhookEvents - tất cả sự kiện có thể đăng ký
+var WebhookEvents = []string{
+	WebhookEventUserRegistered,
+	WebhookEventUserEmailVerified,
+	WebhookEventUserRoleChanged,
+	WebhookEventUserStatusChanged,
+	WebhookEventUserDeleted,
+}
+
+// Trạng thái của 1 lần gửi webhook
+const (
+	WebhookDeliveryPending   = "pending"   // Chờ gửi / chờ retry
+	WebhookDeliverySucceeded = "succeeded" // Endpoint trả về 2xx
+	WebhookDeliveryFailed    = "failed"    // Hết số lần retry hoặc endpoint đã tắt
+)
+
+// WebhookEndpoint - URL nhận sự kiện của hệ thống khác (billing, CRM...)
+type WebhookEndpoint struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	URL         string    `json:"url" gorm:"not null"`
+	Description string    `json:"description"`
+	Secret      string    `json:"-" gorm:"not null"` // Key ký HMAC, chỉ trả về lúc tạo / rotate
+	Events      string    `json:"-" gorm:"not null"` // Danh sách sự kiện, phân cách bằng dấu phẩy
+	Active      bool      `json:"active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// EventList - danh sách sự kiện đã đăng ký
+func (e *WebhookEndpoint) EventList() []string {
+	if e.Events == "" {
+		return nil
+	}
+	return strings.Split(e.Events, ",")
+}
+
+// WebhookDelivery - 1 sự kiện cần gửi tới 1 endpoint (cũng là hàng đợi gửi)
+type WebhookDelivery struct {
+	ID             uint                     `json:"id" gorm:"primaryKey"`
+	EndpointID     uint                     `json:"endpoint_id" gorm:"not null;index"`
+	EventID        string                   `json:"event_id" gorm:"not null"` // Giống nhau giữa các endpoint và khi gửi lại
+	EventType      string                   `json:"event_type" gorm:"not null"`
+	Payload        string                   `json:"payload" gorm:"type:text;not null"`
+	Status         string                   `json:"status" gorm:"type:enum('pending','succeeded','failed');default:'pending'"`
+	AttemptCount   int                      `json:"attempt_count" gorm:"default:0"`
+	NextAttemptAt  time.Time                `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time               `json:"last_attempt_at"`
+	LastStatusCode int                      `json:"last_status_code"`
+	LastError      string                   `json:"last_error"`
+	Endpoint       *WebhookEndpoint         `json:"-" gorm:"foreignKey:EndpointID"`
+	Attempts       []WebhookDeliveryAttempt `json:"attempts,omitempty" gorm:"foreignKey:DeliveryID"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+}
+
+// WebhookDeliveryAttempt - log của 1 lần gọi endpoint
+type WebhookDeliveryAttempt struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	DeliveryID    uint      `json:"delivery_id" gorm:"not null;index"`
+	AttemptNumber int       `json:"attempt_number"`
+	StatusCode    int       `json:"status_code"`   // 0 nếu không nhận được response
+	ResponseBody  string    `json:"response_body"` // Cắt ngắn
+	Error         string    `json:"error"`
+	DurationMs    int64     `json:"duration_ms"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// WebhookPayload - body gửi tới endpoint
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// CreateWebhookEndpointRequest - dữ liệu tạo endpoint
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	Description string   `json:"description" validate:"max=255"`
+	Events      []string `json:"events" validate:"required,min=1,dive,oneof=user.registered user.email_verified user.role_changed user.status_changed user.deleted"`
+}
+
+// UpdateWebhookEndpointRequest - dữ liệu cập nhật endpoint (chỉ các field được gửi)
+type UpdateWebhookEndpointRequest struct {
+	URL         *string  `json:"url" validate:"omitempty,url,max=2048"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Events      []string `json:"events" validate:"omitempty,min=1,dive,oneof=user.registered user.email_verified user.role_changed user.status_changed user.deleted"`
+	Active      *bool    `json:"active"`
+}
+
+// ListWebhookDeliveriesRequest - bộ lọc lịch sử gửi của 1 endpoint
+type ListWebhookDeliveriesRequest struct {
+	Page   int    `form:"page" validate:"min=1"`
+	Limit  int    `form:"limit" validate:"min=1,max=100"`
+	Status string `form:"status" validate:"omitempty,oneof=pending succeeded failed"`
+}
+
+// WebhookEndpointResponse - thông tin endpoint trả về cho client
+type WebhookEndpointResponse struct {
+	*WebhookEndpoint
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"` // Chỉ có khi tạo / rotate
+}
+
+// ToResponse - chuyển endpoint sang response (không kèm secret)
+func (e *WebhookEndpoint) ToResponse() *WebhookEndpointResponse {
+	return &WebhookEndpointResponse{WebhookEndpoint: e, Events: e.EventList()}
+}
+
+// PaginatedWebhookDeliveriesResponse - lịch sử gửi có phân trang
+type PaginatedWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Pagination Pagination        `json:"pagination"`
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// auditBatchSize - số dòng đọc mỗi lần khi export / kiểm tra chuỗi hash
+const auditBatchSize = 500
+
+// auditChainHead - dòng duy nhất trong bảng audit_chain_head
+type auditChainHead struct {
+	ID       uint8
+	LastHash string
+}
+
+func (auditChainHead) TableName() string {
+	return "audit_chain_head"
+}
+
+// auditLogRepository - implement AuditLogRepository interface
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository - tạo audit log repository mới
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// Append - nối log vào cuối chuỗi hash
+// Khóa audit_chain_head để các request ghi log tuần tự, PrevHash luôn là hash của dòng ngay trước
+func (r *auditLogRepository) Append(ctx context.Context, log *domain.AuditLog) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Lấy hash dòng cuối (giữ lock đến khi commit)
+		var head auditChainHead
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&head, 1).Error; err != nil {
+			return err
+		}
+
+		// 2. Tính hash và lưu
+		log.PrevHash = head.LastHash
+		log.CreatedAt = time.Now().Truncate(time.Second)
+		log.Hash = log.ComputeHash()
+		if err := tx.Create(log).Error; err != nil {
+			return err
+		}
+
+		// 3. Dời đầu chuỗi
+		return tx.Model(&auditChainHead{}).Where("id = ?", 1).Update("last_hash", log.Hash).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append audit log: %w", err)
+	}
+	return nil
+}
+
+// List - lấy danh sách log với filter và pagination (mới nhất trước)
+func (r *auditLogRepository) List(ctx context.Context, filter *domain.ListAuditLogsRequest, limit, offset int) ([]domain.AuditLog, int64, error) {
+	var logs []domain.AuditLog
+	var total int64
+
+	query := r.filtered(ctx, filter)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&logs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// ForEach - duyệt các log khớp filter theo thứ tự ID tăng dần, từng batch (filter nil = tất cả)
+func (r *auditLogRepository) ForEach(ctx context.Context, filter *domain.ListAuditLogsRequest, fn func([]domain.AuditLog) error) error {
+	var batch []domain.AuditLog
+	err := r.filtered(ctx, filter).FindInBatches(&batch, auditBatchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to read audit logs: %w", err)
+	}
+	return nil
+}
+
+// GetChainHead - hash của dòng cuối cùng đã ghi
+func (r *auditLogRepository) GetChainHead(ctx context.Context) (string, error) {
+	var head auditChainHead
+	if err := r.db.WithContext(ctx).First(&head, 1).Error; err != nil {
+		return "", fmt.Errorf("failed to get audit chain head: %w", err)
+	}
+	return head.LastHash, nil
+}
+
+// filtered - query builder áp dụng filter
+func (r *auditLogRepository) filtered(ctx context.Context, filter *domain.ListAuditLogsRequest) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.AuditLog{})
+	if filter == nil {
+		return query
+	}
+
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+)
+
+// emailVerificationRepository - implement EmailVerificationRepository interface
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailVerificationRepository - tạo email verification repository mới
+func NewEmailVerificationRepository(db *gorm.DB) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+// Create - tạo token xác thực email
+func (r *emailVerificationRepository) Create(ctx context.Context, verification *domain.EmailVerification) error {
+	if err := r.db.WithContext(ctx).Create(verification).Error; err != nil {
+		return fmt.Errorf("failed to create email verification: %w", err)
+	}
+	return nil
+}
+
+// GetByToken - lấy email verification theo digest của token
+func (r *emailVerificationRepository) GetByToken(ctx context.Context, token string) (*domain.EmailVerification, error) {
+	var verification domain.EmailVerification
+
+	err := r.db.WithContext(ctx).Where("token = ?", token).First(&verification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get email verification by token: %w", err)
+	}
+
+	return &verification, nil
+}
+
+// MarkAsUsed - đánh dấu token (digest) đã được sử dụng
+// Update có điều kiện used = false nên 2 request dùng cùng 1 token cùng lúc chỉ 1 request thành công
+func (r *emailVerificationRepository) MarkAsUsed(ctx context.Context, token string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.EmailVerification{}).
+		Where("token = ? AND used = ?", token, false).
+		Update("used", true)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark email verification as used: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// InvalidateAllForUser - vô hiệu hóa tất cả token chưa dùng của user
+func (r *emailVerificationRepository) InvalidateAllForUser(ctx context.Context, userID uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.EmailVerification{}).
+		Where("user_id = ? AND used = ?", userID, false).
+		Update("used", true).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to invalidate email verifications for user: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpired - xóa các token hết hạn hoặc đã dùng
+func (r *emailVerificationRepository) CleanupExpired(ctx context.Context) error {
+	err := r.db.WithContext(ctx).
+		Where("expires_at < ? OR used = ?", time.Now(), true).
+		Delete(&domain.EmailVerification{}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to cleanup expired email verifications: %w", err)
+	}
+	return nil
+}
@@ -2,32 +2,182 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/me/go-gin-auth/internal/domain"
 )
 
 // UserRepository - interface định nghĩa các thao tác với user
 type UserRepository interface {
-	Create(ctx context.Context, user *domain.User) error                                                    // Tạo user mới
-	GetByEmail(ctx context.Context, email string) (*domain.User, error)                                     // Lấy user theo email
-	GetByID(ctx context.Context, id uint) (*domain.User, error)                                             // Lấy user theo ID
-	Update(ctx context.Context, user *domain.User) error                                                    // Cập nhật user
-	List(ctx context.Context, limit, offset int, search, role, status string) ([]domain.User, int64, error) // Lấy danh sách user
+	Create(ctx context.Context, user *domain.User) error                                                         // Tạo user mới
+	CreateWithVerification(ctx context.Context, user *domain.User, verification *domain.EmailVerification) error // Tạo user mới kèm token xác thực email (1 transaction)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)                                          // Lấy user theo email
+	GetByID(ctx context.Context, id uint) (*domain.User, error)                                                  // Lấy user theo ID
+	Update(ctx context.Context, user *domain.User) error                                                         // Cập nhật user
+	ConsumeMFAStep(ctx context.Context, userID uint, step int64) (bool, error)                                   // Đánh dấu TOTP step đã dùng (false = step đã dùng)
+	Delete(ctx context.Context, id uint) error                                                                   // Xóa user
+	List(ctx context.Context, limit, offset int, search, role, status string) ([]domain.User, int64, error)      // Lấy danh sách user
 }
 
 // TokenRepository - interface cho refresh token
 type TokenRepository interface {
-	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error        // Tạo refresh token
-	GetRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error) // Lấy refresh token
-	RevokeRefreshToken(ctx context.Context, token string) error                      // Vô hiệu hóa token
-	RevokeAllForUser(ctx context.Context, userID uint) error                         // Vô hiệu hóa tất cả token của user
-	CleanupExpired(ctx context.Context) error                                        // Xóa token hết hạn
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error                        // Tạo refresh token
+	GetRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error)                 // Lấy refresh token theo digest
+	RevokeRefreshToken(ctx context.Context, token string) error                                      // Vô hiệu hóa token theo digest
+	RotateRefreshToken(ctx context.Context, oldID uint, newToken *domain.RefreshToken) (bool, error) // Đổi token cũ sang token mới
+	RevokeFamily(ctx context.Context, familyID string) error                                         // Vô hiệu hóa cả family
+	IsFamilyActive(ctx context.Context, familyID string) (bool, error)                               // Family còn token chưa revoke, chưa hết hạn
+	RevokeAllForUser(ctx context.Context, userID uint) error                                         // Vô hiệu hóa tất cả token của user
+	ListActiveForUser(ctx context.Context, userID uint) ([]domain.RefreshToken, error)               // Lấy các session đang active
+	RevokeFamilyForUser(ctx context.Context, userID uint, familyID string) (bool, error)             // Vô hiệu hóa 1 session của user
+	RevokeAllForUserExcept(ctx context.Context, userID uint, familyID string) error                  // Vô hiệu hóa các session khác
+	SetFamilyOrganization(ctx context.Context, familyID string, orgID *uint) error                   // Đổi organization đang làm việc của session
+	CleanupExpired(ctx context.Context) error                                                        // Xóa token hết hạn
 }
 
 // PasswordResetRepository - interface cho reset password
 type PasswordResetRepository interface {
 	Create(ctx context.Context, reset *domain.PasswordReset) error               // Tạo reset request
-	GetByToken(ctx context.Context, token string) (*domain.PasswordReset, error) // Lấy theo token
-	MarkAsUsed(ctx context.Context, token string) error                          // Đánh dấu đã dùng
+	GetByToken(ctx context.Context, token string) (*domain.PasswordReset, error) // Lấy theo digest của token
+	MarkAsUsed(ctx context.Context, token string) (bool, error)                  // Đánh dấu đã dùng (false = đã dùng trước đó)
 	CleanupExpired(ctx context.Context) error                                    // Xóa request hết hạn
 }
+
+// PasswordHistoryRepository - interface cho lịch sử password
+type PasswordHistoryRepository interface {
+	Add(ctx context.Context, userID uint, passwordHash string, keep int) error                // Lưu hash mới, chỉ giữ keep mục gần nhất
+	ListRecent(ctx context.Context, userID uint, limit int) ([]domain.PasswordHistory, error) // Lấy các hash gần nhất
+}
+
+// RoleRepository - interface cho role, permission và role gán cho user
+type RoleRepository interface {
+	List(ctx context.Context) ([]domain.Role, error)                                        // Lấy tất cả role
+	GetByID(ctx context.Context, id uint) (*domain.Role, error)                             // Lấy role theo ID
+	GetByNames(ctx context.Context, names []string) ([]domain.Role, error)                  // Lấy role theo tên
+	Create(ctx context.Context, role *domain.Role) error                                    // Tạo role
+	Update(ctx context.Context, role *domain.Role) error                                    // Cập nhật role + permission
+	Delete(ctx context.Context, id uint) error                                              // Xóa role
+	CountPrimaryUsers(ctx context.Context, name string) (int64, error)                      // Số user dùng role làm role chính
+	ListUserIDs(ctx context.Context, roleID uint) ([]uint, error)                           // User có role (role chính hoặc gán thêm)
+	ListPermissions(ctx context.Context) ([]domain.Permission, error)                       // Lấy tất cả permission
+	GetPermissionsByNames(ctx context.Context, names []string) ([]domain.Permission, error) // Lấy permission theo tên
+	ListUserRoles(ctx context.Context, userID uint) ([]domain.Role, error)                  // Role gán thêm của user
+	SetUserRoles(ctx context.Context, userID uint, roleIDs []uint) error                    // Thay role gán thêm của user
+	GetUserPermissions(ctx context.Context, userID uint) ([]string, error)                  // Permission hiệu lực của user
+}
+
+// OrganizationRepository - interface cho organization, thành viên và lời mời
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *domain.Organization, ownerID uint) error                                                              // Tạo organization + owner
+	GetByID(ctx context.Context, id uint) (*domain.Organization, error)                                                                    // Lấy theo ID
+	GetBySlug(ctx context.Context, slug string) (*domain.Organization, error)                                                              // Lấy theo slug
+	Update(ctx context.Context, org *domain.Organization) error                                                                            // Cập nhật
+	Delete(ctx context.Context, id uint) error                                                                                             // Xóa
+	ListForUser(ctx context.Context, userID uint) ([]domain.OrganizationMembership, error)                                                 // Organization của user
+	GetMember(ctx context.Context, orgID, userID uint) (*domain.OrganizationMember, error)                                                 // Membership của user
+	FirstMembership(ctx context.Context, userID uint) (*domain.OrganizationMember, error)                                                  // Organization mặc định
+	UpdateMemberRole(ctx context.Context, orgID, userID uint, role string) error                                                           // Đổi role thành viên
+	RemoveMember(ctx context.Context, orgID, userID uint) error                                                                            // Xóa thành viên
+	CountOwners(ctx context.Context, orgID uint) (int64, error)                                                                            // Số owner
+	ListMembers(ctx context.Context, orgID uint, limit, offset int, search, role, status string) ([]domain.OrganizationUser, int64, error) // Danh sách thành viên
+	CreateInvitation(ctx context.Context, invitation *domain.OrganizationInvitation) error                                                 // Tạo lời mời
+	GetInvitationByToken(ctx context.Context, token string) (*domain.OrganizationInvitation, error)                                        // Lấy lời mời theo digest
+	ListPendingInvitations(ctx context.Context, orgID uint) ([]domain.OrganizationInvitation, error)                                       // Lời mời đang chờ
+	DeleteInvitation(ctx context.Context, orgID, id uint) (bool, error)                                                                    // Thu hồi lời mời
+	AcceptInvitation(ctx context.Context, invitationID uint, member *domain.OrganizationMember) (bool, error)                              // Chấp nhận lời mời
+}
+
+// PersonalAccessTokenRepository - interface cho personal access token
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, token *domain.PersonalAccessToken) error                  // Tạo token
+	GetByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) // Lấy theo digest
+	ListForUser(ctx context.Context, userID uint) ([]domain.PersonalAccessToken, error)   // Lấy các token chưa revoke
+	Revoke(ctx context.Context, userID, id uint) (bool, error)                            // Vô hiệu hóa 1 token
+	RevokeAllForUser(ctx context.Context, userID uint) error                              // Vô hiệu hóa mọi token của user
+	TouchLastUsed(ctx context.Context, id uint, ipAddress string, usedAt time.Time) error // Cập nhật lần dùng gần nhất
+}
+
+// EmailVerificationRepository - interface cho xác thực email
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, verification *domain.EmailVerification) error        // Tạo token xác thực
+	GetByToken(ctx context.Context, token string) (*domain.EmailVerification, error) // Lấy theo digest của token
+	MarkAsUsed(ctx context.Context, token string) (bool, error)                      // Đánh dấu đã dùng (false = đã dùng trước đó)
+	InvalidateAllForUser(ctx context.Context, userID uint) error                     // Vô hiệu hóa token cũ của user
+	CleanupExpired(ctx context.Context) error                                        // Xóa token hết hạn
+}
+
+// MFABackupCodeRepository - interface cho MFA backup codes
+type MFABackupCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID uint, codeHashes []string) error // Thay thế toàn bộ backup codes
+	Consume(ctx context.Context, userID uint, codeHash string) (bool, error)    // Dùng 1 backup code
+	DeleteForUser(ctx context.Context, userID uint) error                       // Xóa backup codes của user
+}
+
+// LoginAttemptRepository - interface theo dõi login thất bại (theo email và IP)
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, subjectType, subject string) (*domain.LoginAttempt, error)                                     // Lấy trạng thái
+	RecordFailure(ctx context.Context, subjectType, subject string, resetAfter time.Duration) (*domain.LoginAttempt, error) // Ghi nhận 1 lần thất bại
+	SetLockedUntil(ctx context.Context, id uint, lockedUntil time.Time) error                                               // Khóa đến thời điểm
+	Reset(ctx context.Context, subjectType, subject string) error                                                           // Xóa trạng thái thất bại
+	CleanupExpired(ctx context.Context, olderThan time.Duration) error                                                      // Xóa bản ghi cũ
+}
+
+// AuditLogRepository - interface cho audit log (append-only, hash chain)
+type AuditLogRepository interface {
+	Append(ctx context.Context, log *domain.AuditLog) error                                                             // Nối log vào cuối chuỗi (tính PrevHash/Hash)
+	List(ctx context.Context, filter *domain.ListAuditLogsRequest, limit, offset int) ([]domain.AuditLog, int64, error) // Danh sách có filter + pagination
+	ForEach(ctx context.Context, filter *domain.ListAuditLogsRequest, fn func([]domain.AuditLog) error) error           // Duyệt theo ID tăng dần từng batch
+	GetChainHead(ctx context.Context) (string, error)                                                                   // Hash dòng cuối
+}
+
+// WebhookRepository - interface cho webhook endpoint và hàng đợi gửi
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error                                                     // Tạo endpoint
+	GetEndpoint(ctx context.Context, id uint) (*domain.WebhookEndpoint, error)                                                      // Lấy endpoint theo ID
+	ListEndpoints(ctx context.Context) ([]domain.WebhookEndpoint, error)                                                            // Tất cả endpoint
+	ListSubscribed(ctx context.Context, eventType string) ([]domain.WebhookEndpoint, error)                                         // Endpoint đang bật có đăng ký sự kiện
+	UpdateEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error                                                     // Cập nhật endpoint
+	DeleteEndpoint(ctx context.Context, id uint) (bool, error)                                                                      // Xóa endpoint
+	CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error                                                // Đưa vào hàng đợi
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)                                 // Nhận delivery đến hạn (kèm endpoint)
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookDeliveryAttempt) error              // Lưu log gửi + trạng thái
+	ListDeliveries(ctx context.Context, endpointID uint, status string, limit, offset int) ([]domain.WebhookDelivery, int64, error) // Lịch sử gửi
+	GetDelivery(ctx context.Context, id uint) (*domain.WebhookDelivery, error)                                                      // Delivery kèm log
+}
+
+// UserIdentityRepository - interface cho tài khoản provider bên ngoài liên kết với user
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *domain.UserIdentity) error                                  // Liên kết identity
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) // Lấy theo provider + subject
+	ListForUser(ctx context.Context, userID uint) ([]domain.UserIdentity, error)                      // Các identity của user
+	CountForUser(ctx context.Context, userID uint) (int64, error)                                     // Số identity của user
+	TouchLogin(ctx context.Context, id uint, email string) error                                      // Cập nhật lần đăng nhập cuối
+	DeleteForUser(ctx context.Context, userID, id uint) (bool, error)                                 // Hủy liên kết
+}
+
+// OAuthStateRepository - interface cho state của đăng nhập OAuth đang chờ callback
+type OAuthStateRepository interface {
+	Create(ctx context.Context, state *domain.OAuthState) error                // Lưu state
+	Consume(ctx context.Context, stateHash string) (*domain.OAuthState, error) // Lấy và xóa state (dùng 1 lần)
+	CleanupExpired(ctx context.Context) error                                  // Xóa state hết hạn
+}
+
+// OAuthClientRepository - interface cho client của authorization server và consent của user
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *domain.OAuthClient) error                               // Đăng ký client
+	GetByID(ctx context.Context, id uint) (*domain.OAuthClient, error)                          // Lấy theo ID
+	GetByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error)            // Lấy theo client_id
+	List(ctx context.Context) ([]domain.OAuthClient, error)                                     // Tất cả client
+	Update(ctx context.Context, client *domain.OAuthClient) error                               // Cập nhật client
+	Delete(ctx context.Context, id uint) (bool, error)                                          // Xóa client (code, consent xóa theo FK)
+	GetConsent(ctx context.Context, userID uint, clientID string) (*domain.OAuthConsent, error) // Consent của user cho client
+	SaveConsent(ctx context.Context, consent *domain.OAuthConsent) error                        // Tạo hoặc cập nhật consent
+}
+
+// OAuthCodeRepository - interface cho authorization code đang chờ đổi lấy token
+type OAuthCodeRepository interface {
+	Create(ctx context.Context, code *domain.OAuthAuthorizationCode) error                  // Lưu code
+	GetByHash(ctx context.Context, codeHash string) (*domain.OAuthAuthorizationCode, error) // Lấy code (kể cả code đã dùng)
+	MarkUsed(ctx context.Context, id uint) (bool, error)                                    // Đánh dấu đã dùng (false = đã dùng trước đó)
+	CleanupExpired(ctx context.Context) error                                               // Xóa code hết hạn
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// loginAttemptRepository - implement LoginAttemptRepository interface
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository - tạo login attempt repository mới
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// Get - lấy trạng thái login thất bại của 1 email/IP
+func (r *loginAttemptRepository) Get(ctx context.Context, subjectType, subject string) (*domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+
+	err := r.db.WithContext(ctx).
+		Where("subject_type = ? AND subject = ?", subjectType, subject).
+		First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get login attempt: %w", err)
+	}
+
+	return &attempt, nil
+}
+
+// RecordFailure - tăng số lần thất bại (atomic), đếm lại từ 1 nếu lần thất bại trước đã quá resetAfter
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, subjectType, subject string, resetAfter time.Duration) (*domain.LoginAttempt, error) {
+	now := time.Now()
+	staleBefore := now.Add(-resetAfter)
+
+	// INSERT ... ON DUPLICATE KEY UPDATE để 2 request đồng thời không mất lượt đếm
+	attempt := &domain.LoginAttempt{
+		SubjectType:  subjectType,
+		Subject:      subject,
+		FailedCount:  1,
+		LastFailedAt: now,
+	}
+	// Thứ tự quan trọng: failed_count phải đọc last_failed_at cũ trước khi bị ghi đè
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "failed_count"}, Value: gorm.Expr("IF(last_failed_at < ?, 1, failed_count + 1)", staleBefore)},
+			{Column: clause.Column{Name: "last_failed_at"}, Value: now},
+		},
+	}).Create(attempt).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	// Đọc lại để lấy failed_count sau khi update
+	return r.Get(ctx, subjectType, subject)
+}
+
+// SetLockedUntil - khóa login của 1 email/IP đến thời điểm lockedUntil
+func (r *loginAttemptRepository) SetLockedUntil(ctx context.Context, id uint, lockedUntil time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.LoginAttempt{}).
+		Where("id = ?", id).
+		Update("locked_until", lockedUntil).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to set login lock: %w", err)
+	}
+	return nil
+}
+
+// Reset - xóa trạng thái thất bại (login thành công, reset password, admin unlock)
+func (r *loginAttemptRepository) Reset(ctx context.Context, subjectType, subject string) error {
+	err := r.db.WithContext(ctx).
+		Where("subject_type = ? AND subject = ?", subjectType, subject).
+		Delete(&domain.LoginAttempt{}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpired - xóa các bản ghi đã hết khóa và không còn thất bại gần đây
+func (r *loginAttemptRepository) CleanupExpired(ctx context.Context, olderThan time.Duration) error {
+	cutoff := time.Now().Add(-olderThan)
+	err := r.db.WithContext(ctx).
+		Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)", cutoff, time.Now()).
+		Delete(&domain.LoginAttempt{}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to cleanup login attempts: %w", err)
+	}
+	return nil
+}
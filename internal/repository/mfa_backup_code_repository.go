@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+)
+
+// mfaBackupCodeRepository - implement MFABackupCodeRepository interface
+type mfaBackupCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewMFABackupCodeRepository - tạo MFA backup code repository mới
+func NewMFABackupCodeRepository(db *gorm.DB) MFABackupCodeRepository {
+	return &mfaBackupCodeRepository{db: db}
+}
+
+// ReplaceForUser - xóa backup codes cũ và lưu bộ mới (trong 1 transaction)
+func (r *mfaBackupCodeRepository) ReplaceForUser(ctx context.Context, userID uint, codeHashes []string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.MFABackupCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]domain.MFABackupCode, len(codeHashes))
+		for i, hash := range codeHashes {
+			codes[i] = domain.MFABackupCode{UserID: userID, CodeHash: hash}
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to replace backup codes: %w", err)
+	}
+	return nil
+}
+
+// Consume - đánh dấu 1 backup code đã dùng, trả về false nếu không có code hợp lệ
+func (r *mfaBackupCodeRepository) Consume(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	// Update có điều kiện used_at IS NULL để tránh dùng 1 code 2 lần đồng thời
+	result := r.db.WithContext(ctx).Model(&domain.MFABackupCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume backup code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteForUser - xóa tất cả backup codes của user
+func (r *mfaBackupCodeRepository) DeleteForUser(ctx context.Context, userID uint) error {
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Delete(&domain.MFABackupCode{}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to delete backup codes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// oauthClientRepository - implement OAuthClientRepository interface
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthClientRepository - tạo oauth client repository mới
+func NewOAuthClientRepository(db *gorm.DB) OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+// Create - đăng ký client mới
+func (r *oauthClientRepository) Create(ctx context.Context, client *domain.OAuthClient) error {
+	if err := r.db.WithContext(ctx).Create(client).Error; err != nil {
+		return fmt.Errorf("failed to create oauth client: %w", err)
+	}
+	return nil
+}
+
+// GetByID - lấy client theo ID
+func (r *oauthClientRepository) GetByID(ctx context.Context, id uint) (*domain.OAuthClient, error) {
+	var client domain.OAuthClient
+
+	err := r.db.WithContext(ctx).First(&client, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get oauth client: %w", err)
+	}
+
+	return &client, nil
+}
+
+// GetByClientID - lấy client theo client_id
+func (r *oauthClientRepository) GetByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	var client domain.OAuthClient
+
+	err := r.db.WithContext(ctx).Where("client_id = ?", clientID).First(&client).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get oauth client: %w", err)
+	}
+
+	return &client, nil
+}
+
+// List - tất cả client
+func (r *oauthClientRepository) List(ctx context.Context) ([]domain.OAuthClient, error) {
+	var clients []domain.OAuthClient
+	if err := r.db.WithContext(ctx).Order("id").Find(&clients).Error; err != nil {
+		return nil, fmt.Errorf("failed to list oauth clients: %w", err)
+	}
+	return clients, nil
+}
+
+// Update - cập nhật client
+func (r *oauthClientRepository) Update(ctx context.Context, client *domain.OAuthClient) error {
+	if err := r.db.WithContext(ctx).Save(client).Error; err != nil {
+		return fmt.Errorf("failed to update oauth client: %w", err)
+	}
+	return nil
+}
+
+// Delete - xóa client (code và consent tự xóa theo FK)
+func (r *oauthClientRepository) Delete(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&domain.OAuthClient{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete oauth client: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetConsent - consent của user cho client
+func (r *oauthClientRepository) GetConsent(ctx context.Context, userID uint, clientID string) (*domain.OAuthConsent, error) {
+	var consent domain.OAuthConsent
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND client_id = ?", userID, clientID).
+		First(&consent).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get oauth consent: %w", err)
+	}
+
+	return &consent, nil
+}
+
+// SaveConsent - tạo consent hoặc thay scope nếu đã có
+func (r *oauthClientRepository) SaveConsent(ctx context.Context, consent *domain.OAuthConsent) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"scope", "updated_at"})}).
+		Create(consent).Error
+	if err != nil {
+		return fmt.Errorf("failed to save oauth consent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+)
+
+// oauthCodeRepository - implement OAuthCodeRepository interface
+type oauthCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthCodeRepository - tạo authorization code repository mới
+func NewOAuthCodeRepository(db *gorm.DB) OAuthCodeRepository {
+	return &oauthCodeRepository{db: db}
+}
+
+// Create - lưu authorization code
+func (r *oauthCodeRepository) Create(ctx context.Context, code *domain.OAuthAuthorizationCode) error {
+	if err := r.db.WithContext(ctx).Create(code).Error; err != nil {
+		return fmt.Errorf("failed to create authorization code: %w", err)
+	}
+	return nil
+}
+
+// GetByHash - lấy code theo digest
+// Code đã dùng vẫn được trả về (Used = true) để usecase phát hiện replay
+func (r *oauthCodeRepository) GetByHash(ctx context.Context, codeHash string) (*domain.OAuthAuthorizationCode, error) {
+	var code domain.OAuthAuthorizationCode
+
+	err := r.db.WithContext(ctx).Where("code_hash = ?", codeHash).First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get authorization code: %w", err)
+	}
+
+	return &code, nil
+}
+
+// MarkUsed - đánh dấu code đã dùng
+// Update có điều kiện used = false nên 2 request đổi cùng 1 code cùng lúc chỉ 1 request thành công
+func (r *oauthCodeRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.OAuthAuthorizationCode{}).
+		Where("id = ? AND used = ?", id, false).
+		Update("used", true)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume authorization code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CleanupExpired - xóa các code hết hạn
+func (r *oauthCodeRepository) CleanupExpired(ctx context.Context) error {
+	err := r.db.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&domain.OAuthAuthorizationCode{}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to cleanup expired authorization codes: %w", err)
+	}
+	return nil
+}
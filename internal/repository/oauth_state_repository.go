@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// oauthStateRepository - implement OAuthStateRepository interface
+type oauthStateRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthStateRepository - tạo oauth state repository mới
+func NewOAuthStateRepository(db *gorm.DB) OAuthStateRepository {
+	return &oauthStateRepository{db: db}
+}
+
+// Create - lưu state
+func (r *oauthStateRepository) Create(ctx context.Context, state *domain.OAuthState) error {
+	if err := r.db.WithContext(ctx).Create(state).Error; err != nil {
+		return fmt.Errorf("failed to create oauth state: %w", err)
+	}
+	return nil
+}
+
+// Consume - lấy và xóa state trong 1 transaction (callback gửi lại 2 lần chỉ thành công 1 lần)
+func (r *oauthStateRepository) Consume(ctx context.Context, stateHash string) (*domain.OAuthState, error) {
+	var state domain.OAuthState
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("state_hash = ?", stateHash).
+			First(&state).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&state).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume oauth state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// CleanupExpired - xóa các state hết hạn (user bỏ dở giữa chừng)
+func (r *oauthStateRepository) CleanupExpired(ctx context.Context) error {
+	err := r.db.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&domain.OAuthState{}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to cleanup expired oauth states: %w", err)
+	}
+	return nil
+}
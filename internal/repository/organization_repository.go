@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// organizationRepository - implement OrganizationRepository interface
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository - tạo organization repository mới
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+// Create - tạo organization và thêm người tạo làm owner
+func (r *organizationRepository) Create(ctx context.Context, org *domain.Organization, ownerID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&domain.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         ownerID,
+			Role:           domain.OrgRoleOwner,
+		}).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	return nil
+}
+
+// GetByID - lấy organization theo ID
+func (r *organizationRepository) GetByID(ctx context.Context, id uint) (*domain.Organization, error) {
+	var org domain.Organization
+
+	err := r.db.WithContext(ctx).First(&org, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return &org, nil
+}
+
+// GetBySlug - lấy organization theo slug
+func (r *organizationRepository) GetBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	var org domain.Organization
+
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&org).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return &org, nil
+}
+
+// Update - cập nhật organization
+func (r *organizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	if err := r.db.WithContext(ctx).Save(org).Error; err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	return nil
+}
+
+// Delete - xóa organization (thành viên, lời mời tự xóa theo FK)
+func (r *organizationRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&domain.Organization{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	return nil
+}
+
+// ListForUser - các organization user đang là thành viên
+func (r *organizationRepository) ListForUser(ctx context.Context, userID uint) ([]domain.OrganizationMembership, error) {
+	var memberships []domain.OrganizationMembership
+
+	err := r.db.WithContext(ctx).
+		Table("organizations").
+		Select("organizations.*, organization_members.role").
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name").
+		Scan(&memberships).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	return memberships, nil
+}
+
+// GetMember - lấy membership của user trong organization (nil nếu không phải thành viên)
+func (r *organizationRepository) GetMember(ctx context.Context, orgID, userID uint) (*domain.OrganizationMember, error) {
+	var member domain.OrganizationMember
+
+	err := r.db.WithContext(ctx).Where("organization_id = ? AND user_id = ?", orgID, userID).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+	return &member, nil
+}
+
+// FirstMembership - organization user tham gia sớm nhất (organization mặc định khi login)
+func (r *organizationRepository) FirstMembership(ctx context.Context, userID uint) (*domain.OrganizationMember, error) {
+	var member domain.OrganizationMember
+
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at, organization_id").First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+	return &member, nil
+}
+
+// UpdateMemberRole - đổi role của thành viên
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uint, role string) error {
+	err := r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("role", role).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update organization member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember - xóa thành viên khỏi organization
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&domain.OrganizationMember{}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+	return nil
+}
+
+// CountOwners - số owner của organization
+func (r *organizationRepository) CountOwners(ctx context.Context, orgID uint) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, domain.OrgRoleOwner).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count organization owners: %w", err)
+	}
+	return count, nil
+}
+
+// ListMembers - danh sách user thuộc organization (giống UserRepository.List nhưng giới hạn trong organization)
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID uint, limit, offset int, search, role, status string) ([]domain.OrganizationUser, int64, error) {
+	var members []domain.OrganizationUser
+	var total int64
+
+	// Tạo query builder
+	query := r.db.WithContext(ctx).
+		Table("users").
+		Joins("JOIN organization_members ON organization_members.user_id = users.id").
+		Where("organization_members.organization_id = ?", orgID)
+
+	// Apply filters (nếu có), role là role trong organization
+	if search != "" {
+		query = query.Where("users.full_name LIKE ? OR users.email LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if role != "" {
+		query = query.Where("organization_members.role = ?", role)
+	}
+	if status != "" {
+		query = query.Where("users.status = ?", status)
+	}
+
+	// Đếm tổng số record
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count organization members: %w", err)
+	}
+
+	// Lấy danh sách với pagination
+	err := query.
+		Select("users.*, organization_members.role AS organization_role, organization_members.created_at AS joined_at").
+		Order("organization_members.created_at").
+		Limit(limit).Offset(offset).
+		Scan(&members).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list organization members: %w", err)
+	}
+
+	return members, total, nil
+}
+
+// CreateInvitation - lưu lời mời mới
+func (r *organizationRepository) CreateInvitation(ctx context.Context, invitation *domain.OrganizationInvitation) error {
+	if err := r.db.WithContext(ctx).Create(invitation).Error; err != nil {
+		return fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return nil
+}
+
+// GetInvitationByToken - lấy lời mời theo digest của token
+func (r *organizationRepository) GetInvitationByToken(ctx context.Context, token string) (*domain.OrganizationInvitation, error) {
+	var invitation domain.OrganizationInvitation
+
+	err := r.db.WithContext(ctx).Where("token = ?", token).First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+// ListPendingInvitations - lời mời chưa chấp nhận và chưa hết hạn
+func (r *organizationRepository) ListPendingInvitations(ctx context.Context, orgID uint) ([]domain.OrganizationInvitation, error) {
+	var invitations []domain.OrganizationInvitation
+
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND accepted_at IS NULL AND expires_at > ?", orgID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// DeleteInvitation - thu hồi lời mời chưa chấp nhận, trả về false nếu không tìm thấy
+func (r *organizationRepository) DeleteInvitation(ctx context.Context, orgID, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND organization_id = ? AND accepted_at IS NULL", id, orgID).
+		Delete(&domain.OrganizationInvitation{})
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete invitation: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// AcceptInvitation - đánh dấu lời mời đã dùng và thêm user vào organization (cùng transaction)
+// Trả về false nếu lời mời đã được dùng bởi request khác
+func (r *organizationRepository) AcceptInvitation(ctx context.Context, invitationID uint, member *domain.OrganizationMember) (bool, error) {
+	accepted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Chỉ 1 request dùng được lời mời
+		result := tx.Model(&domain.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitationID).
+			Update("accepted_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// 2. Thêm thành viên (đã là thành viên -> giữ role hiện tại)
+		accepted = true
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error
+	})
+
+	if err != nil {
+		return false, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	return accepted, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+)
+
+// passwordHistoryRepository - implement PasswordHistoryRepository interface
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepository - tạo password history repository mới
+func NewPasswordHistoryRepository(db *gorm.DB) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+// Add - lưu hash password mới và chỉ giữ lại keep mục gần nhất (trong 1 transaction)
+func (r *passwordHistoryRepository) Add(ctx context.Context, userID uint, passwordHash string, keep int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&domain.PasswordHistory{UserID: userID, PasswordHash: passwordHash}).Error; err != nil {
+			return err
+		}
+
+		// Lấy ID của các mục cần giữ, xóa phần còn lại
+		var keepIDs []uint
+		if err := tx.Model(&domain.PasswordHistory{}).
+			Where("user_id = ?", userID).
+			Order("id DESC").
+			Limit(keep).
+			Pluck("id", &keepIDs).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND id NOT IN ?", userID, keepIDs).
+			Delete(&domain.PasswordHistory{}).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to add password history: %w", err)
+	}
+	return nil
+}
+
+// ListRecent - lấy limit hash password gần nhất của user (mới nhất trước)
+func (r *passwordHistoryRepository) ListRecent(ctx context.Context, userID uint, limit int) ([]domain.PasswordHistory, error) {
+	var history []domain.PasswordHistory
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&history).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list password history: %w", err)
+	}
+	return history, nil
+}
@@ -44,15 +44,16 @@ func (r *passwordResetRepository) GetByToken(ctx context.Context, token string)
 }
 
 // MarkAsUsed - đánh dấu token đã được sử dụng
-func (r *passwordResetRepository) MarkAsUsed(ctx context.Context, token string) error {
-	err := r.db.WithContext(ctx).Model(&domain.PasswordReset{}).
-		Where("token = ?", token).
-		Update("used", true).Error
+// Update có điều kiện used = false nên 2 request dùng cùng 1 token cùng lúc chỉ 1 request thành công
+func (r *passwordResetRepository) MarkAsUsed(ctx context.Context, token string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.PasswordReset{}).
+		Where("token = ? AND used = ?", token, false).
+		Update("used", true)
 
-	if err != nil {
-		return fmt.Errorf("failed to mark password reset as used: %w", err)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark password reset as used: %w", result.Error)
 	}
-	return nil
+	return result.RowsAffected > 0, nil
 }
 
 // CleanupExpired - xóa các request hết hạn hoặc đã dùng
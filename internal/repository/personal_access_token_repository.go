@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+)
+
+// personalAccessTokenRepository - implement PersonalAccessTokenRepository interface
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalAccessTokenRepository - tạo personal access token repository mới
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+// Create - lưu token mới
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *domain.PersonalAccessToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return fmt.Errorf("failed to create personal access token: %w", err)
+	}
+	return nil
+}
+
+// GetByHash - lấy token theo digest
+func (r *personalAccessTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// ListForUser - lấy các token chưa bị revoke của user (mới nhất trước)
+func (r *personalAccessTokenRepository) ListForUser(ctx context.Context, userID uint) ([]domain.PersonalAccessToken, error) {
+	var tokens []domain.PersonalAccessToken
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("id DESC").
+		Find(&tokens).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke - vô hiệu hóa 1 token của user, trả về false nếu không tìm thấy
+func (r *personalAccessTokenRepository) Revoke(ctx context.Context, userID, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to revoke personal access token: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeAllForUser - vô hiệu hóa mọi token chưa revoke của user (tài khoản bị chiếm, reset password)
+func (r *personalAccessTokenRepository) RevokeAllForUser(ctx context.Context, userID uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke personal access tokens: %w", err)
+	}
+	return nil
+}
+
+// TouchLastUsed - cập nhật thời điểm và IP dùng token gần nhất
+func (r *personalAccessTokenRepository) TouchLastUsed(ctx context.Context, id uint, ipAddress string, usedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ipAddress}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update personal access token usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+)
+
+// roleRepository - implement RoleRepository interface
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository - tạo role repository mới
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// List - lấy tất cả role kèm permission
+func (r *roleRepository) List(ctx context.Context) ([]domain.Role, error) {
+	var roles []domain.Role
+
+	if err := r.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// GetByID - lấy role theo ID kèm permission
+func (r *roleRepository) GetByID(ctx context.Context, id uint) (*domain.Role, error) {
+	var role domain.Role
+
+	err := r.db.WithContext(ctx).Preload("Permissions").First(&role, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return &role, nil
+}
+
+// GetByNames - lấy các role theo tên
+func (r *roleRepository) GetByNames(ctx context.Context, names []string) ([]domain.Role, error) {
+	var roles []domain.Role
+	if len(names) == 0 {
+		return roles, nil
+	}
+
+	if err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+	return roles, nil
+}
+
+// Create - tạo role kèm permission
+func (r *roleRepository) Create(ctx context.Context, role *domain.Role) error {
+	if err := r.db.WithContext(ctx).Omit("Permissions.*").Create(role).Error; err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+	return nil
+}
+
+// Update - cập nhật mô tả và thay toàn bộ permission của role
+func (r *roleRepository) Update(ctx context.Context, role *domain.Role) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Update("description", role.Description).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Omit("Permissions.*").Association("Permissions").Replace(role.Permissions)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	return nil
+}
+
+// Delete - xóa role (role_permissions, user_roles tự xóa theo FK)
+func (r *roleRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&domain.Role{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	return nil
+}
+
+// CountPrimaryUsers - số user đang dùng role này làm role chính
+func (r *roleRepository) CountPrimaryUsers(ctx context.Context, name string) (int64, error) {
+	var count int64
+
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).Where("role = ?", name).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count users with role: %w", err)
+	}
+	return count, nil
+}
+
+// ListUserIDs - ID các user có role (role chính hoặc role gán thêm)
+func (r *roleRepository) ListUserIDs(ctx context.Context, roleID uint) ([]uint, error) {
+	var ids []uint
+
+	err := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("role = (SELECT name FROM roles WHERE id = ?) OR id IN (SELECT user_id FROM user_roles WHERE role_id = ?)", roleID, roleID).
+		Pluck("id", &ids).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users with role: %w", err)
+	}
+	return ids, nil
+}
+
+// ListPermissions - lấy tất cả permission
+func (r *roleRepository) ListPermissions(ctx context.Context) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+
+	if err := r.db.WithContext(ctx).Order("name").Find(&permissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list permissions: %w", err)
+	}
+	return permissions, nil
+}
+
+// GetPermissionsByNames - lấy các permission theo tên
+func (r *roleRepository) GetPermissionsByNames(ctx context.Context, names []string) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	if err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+	return permissions, nil
+}
+
+// ListUserRoles - lấy các role gán thêm của user
+func (r *roleRepository) ListUserRoles(ctx context.Context, userID uint) ([]domain.Role, error) {
+	var roles []domain.Role
+
+	err := r.db.WithContext(ctx).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roles).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user roles: %w", err)
+	}
+	return roles, nil
+}
+
+// SetUserRoles - thay toàn bộ role gán thêm của user
+func (r *roleRepository) SetUserRoles(ctx context.Context, userID uint, roleIDs []uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.UserRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+
+		userRoles := make([]domain.UserRole, len(roleIDs))
+		for i, roleID := range roleIDs {
+			userRoles[i] = domain.UserRole{UserID: userID, RoleID: roleID}
+		}
+		return tx.Create(&userRoles).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set user roles: %w", err)
+	}
+	return nil
+}
+
+// GetUserPermissions - permission hiệu lực của user (role chính + role gán thêm)
+func (r *roleRepository) GetUserPermissions(ctx context.Context, userID uint) ([]string, error) {
+	var names []string
+
+	err := r.db.WithContext(ctx).
+		Table("permissions").
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = (SELECT role FROM users WHERE id = ?) OR roles.id IN (SELECT role_id FROM user_roles WHERE user_id = ?)", userID, userID).
+		Order("permissions.name").
+		Pluck("permissions.name", &names).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user permissions: %w", err)
+	}
+	return names, nil
+}
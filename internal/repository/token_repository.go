@@ -56,6 +56,62 @@ func (r *tokenRepository) RevokeRefreshToken(ctx context.Context, token string)
 	return nil
 }
 
+// RotateRefreshToken - đánh dấu token cũ đã rotate và lưu token mới (trong 1 transaction)
+// Trả về false nếu token cũ đã bị revoke/rotate trước đó (ví dụ 2 request refresh đồng thời)
+func (r *tokenRepository) RotateRefreshToken(ctx context.Context, oldID uint, newToken *domain.RefreshToken) (bool, error) {
+	rotated := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Update có điều kiện revoked = false để chỉ 1 request rotate thành công
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND revoked = ?", oldID, false).
+			Updates(map[string]interface{}{"revoked": true, "rotated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(newToken).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	return rotated, nil
+}
+
+// RevokeFamily - vô hiệu hóa tất cả token trong 1 family
+func (r *tokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	err := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("family_id = ?", familyID).
+		Update("revoked", true).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+// IsFamilyActive - family (session) còn token chưa bị revoke và chưa hết hạn
+func (r *tokenRepository) IsFamilyActive(ctx context.Context, familyID string) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked = ? AND expires_at > ?", familyID, false, time.Now()).
+		Count(&count).Error
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check token family: %w", err)
+	}
+	return count > 0, nil
+}
+
 // RevokeAllForUser - vô hiệu hóa tất cả token của 1 user
 func (r *tokenRepository) RevokeAllForUser(ctx context.Context, userID uint) error {
 	err := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
@@ -68,11 +124,64 @@ func (r *tokenRepository) RevokeAllForUser(ctx context.Context, userID uint) err
 	return nil
 }
 
+// ListActiveForUser - lấy các token còn hiệu lực của user (mỗi family có đúng 1 token active)
+func (r *tokenRepository) ListActiveForUser(ctx context.Context, userID uint) ([]domain.RefreshToken, error) {
+	var tokens []domain.RefreshToken
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked = ? AND expires_at > ?", userID, false, time.Now()).
+		Order("last_used_at DESC").
+		Find(&tokens).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeFamilyForUser - vô hiệu hóa 1 family, chỉ khi family thuộc về user
+// Trả về false nếu không có token active nào trong family
+func (r *tokenRepository) RevokeFamilyForUser(ctx context.Context, userID uint, familyID string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND family_id = ? AND revoked = ?", userID, familyID, false).
+		Update("revoked", true)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to revoke session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeAllForUserExcept - vô hiệu hóa tất cả token của user trừ 1 family
+func (r *tokenRepository) RevokeAllForUserExcept(ctx context.Context, userID uint, familyID string) error {
+	err := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND family_id <> ?", userID, familyID).
+		Update("revoked", true).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke other sessions: %w", err)
+	}
+	return nil
+}
+
+// SetFamilyOrganization - đổi organization đang làm việc cho cả family (token đang dùng và token cũ)
+func (r *tokenRepository) SetFamilyOrganization(ctx context.Context, familyID string, orgID *uint) error {
+	err := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("family_id = ?", familyID).
+		Update("organization_id", orgID).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update session organization: %w", err)
+	}
+	return nil
+}
+
 // CleanupExpired - xóa các token đã hết hạn hoặc bị revoke
 func (r *tokenRepository) CleanupExpired(ctx context.Context) error {
 	// Xóa token hết hạn hoặc bị revoke
+	// Token đã rotate được giữ lại đến khi hết hạn để còn phát hiện reuse
 	err := r.db.WithContext(ctx).
-		Where("expires_at < ? OR revoked = ?", time.Now(), true).
+		Where("expires_at < ? OR (revoked = ? AND rotated_at IS NULL)", time.Now(), true).
 		Delete(&domain.RefreshToken{}).Error
 
 	if err != nil {
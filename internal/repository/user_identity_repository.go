@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+)
+
+// userIdentityRepository - implement UserIdentityRepository interface
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository - tạo user identity repository mới
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+// Create - liên kết identity với user
+func (r *userIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	if err := r.db.WithContext(ctx).Create(identity).Error; err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	return nil
+}
+
+// GetByProviderSubject - lấy identity theo provider + subject
+func (r *userIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+// ListForUser - các identity của user
+func (r *userIdentityRepository) ListForUser(ctx context.Context, userID uint) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&identities).Error; err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+	return identities, nil
+}
+
+// CountForUser - số identity của user
+func (r *userIdentityRepository) CountForUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.UserIdentity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count user identities: %w", err)
+	}
+	return count, nil
+}
+
+// TouchLogin - cập nhật thời điểm đăng nhập và email mới nhất provider trả về
+func (r *userIdentityRepository) TouchLogin(ctx context.Context, id uint, email string) error {
+	err := r.db.WithContext(ctx).Model(&domain.UserIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_login_at": time.Now(), "email": email}).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to update user identity: %w", err)
+	}
+	return nil
+}
+
+// DeleteForUser - hủy liên kết identity (chỉ của chính user)
+func (r *userIdentityRepository) DeleteForUser(ctx context.Context, userID, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&domain.UserIdentity{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete user identity: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
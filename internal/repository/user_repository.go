@@ -28,6 +28,23 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// CreateWithVerification - tạo user và token xác thực email trong 1 transaction
+// Không để lại user không có token xác thực khi lưu token lỗi
+func (r *userRepository) CreateWithVerification(ctx context.Context, user *domain.User, verification *domain.EmailVerification) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		verification.UserID = user.ID
+		return tx.Create(verification).Error
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
 // GetByEmail - lấy user theo email
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
@@ -67,6 +84,28 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// ConsumeMFAStep - lưu TOTP step vừa dùng, chỉ thành công khi step mới hơn step đã lưu
+// Điều kiện nằm trong câu UPDATE nên 2 request dùng cùng 1 mã cùng lúc chỉ 1 request thành công
+func (r *userRepository) ConsumeMFAStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ? AND mfa_last_step < ?", userID, step).
+		Update("mfa_last_step", step)
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume mfa step: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete - xóa user (token, session, membership... tự xóa theo FK)
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&domain.User{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
 // List - lấy danh sách user với filter và pagination
 func (r *userRepository) List(ctx context.Context, limit, offset int, search, role, status string) ([]domain.User, int64, error) {
 	var users []domain.User
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// webhookRepository - implement WebhookRepository interface
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository - tạo webhook repository mới
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// CreateEndpoint - tạo endpoint mới
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	if err := r.db.WithContext(ctx).Create(endpoint).Error; err != nil {
+		return fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// GetEndpoint - lấy endpoint theo ID
+func (r *webhookRepository) GetEndpoint(ctx context.Context, id uint) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+
+	err := r.db.WithContext(ctx).First(&endpoint, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	return &endpoint, nil
+}
+
+// ListEndpoints - tất cả endpoint
+func (r *webhookRepository) ListEndpoints(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	var endpoints []domain.WebhookEndpoint
+	if err := r.db.WithContext(ctx).Order("id").Find(&endpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// ListSubscribed - các endpoint đang bật có đăng ký sự kiện
+func (r *webhookRepository) ListSubscribed(ctx context.Context, eventType string) ([]domain.WebhookEndpoint, error) {
+	var endpoints []domain.WebhookEndpoint
+
+	err := r.db.WithContext(ctx).
+		Where("active = ? AND FIND_IN_SET(?, events) > 0", true, eventType).
+		Find(&endpoints).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscribed webhook endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+// UpdateEndpoint - cập nhật endpoint
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	if err := r.db.WithContext(ctx).Save(endpoint).Error; err != nil {
+		return fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// DeleteEndpoint - xóa endpoint (lịch sử gửi tự xóa theo FK)
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&domain.WebhookEndpoint{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete webhook endpoint: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CreateDeliveries - đưa sự kiện vào hàng đợi gửi
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue - nhận các delivery đến hạn gửi (kèm endpoint)
+// Dời next_attempt_at thêm lease để worker khác (instance khác) không lấy trùng;
+// worker chết giữa chừng thì delivery tự được gửi lại sau lease
+func (r *webhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// 1. Khóa các dòng đến hạn, bỏ qua dòng worker khác đang khóa
+		var ids []uint
+		err := tx.Model(&domain.WebhookDelivery{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		// 2. Giữ chỗ
+		err = tx.Model(&domain.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return err
+		}
+
+		// 3. Lấy kèm endpoint
+		return tx.Preload("Endpoint").Where("id IN ?", ids).Order("id").Find(&deliveries).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt - lưu log 1 lần gửi và trạng thái mới của delivery
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookDeliveryAttempt) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if attempt != nil {
+			if err := tx.Create(attempt).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&domain.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+			"status":           delivery.Status,
+			"attempt_count":    delivery.AttemptCount,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"last_attempt_at":  delivery.LastAttemptAt,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries - lịch sử gửi của endpoint (mới nhất trước)
+func (r *webhookRepository) ListDeliveries(ctx context.Context, endpointID uint, status string, limit, offset int) ([]domain.WebhookDelivery, int64, error) {
+	var deliveries []domain.WebhookDelivery
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
+
+// GetDelivery - lấy delivery kèm log các lần gửi
+func (r *webhookRepository) GetDelivery(ctx context.Context, id uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	err := r.db.WithContext(ctx).
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB { return db.Order("attempt_number") }).
+		First(&delivery, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS mfa_backup_codes;
+
+ALTER TABLE users
+    DROP COLUMN mfa_last_step,
+    DROP COLUMN mfa_secret,
+    DROP COLUMN mfa_enabled;
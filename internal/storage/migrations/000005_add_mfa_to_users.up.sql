@@ -0,0 +1,16 @@
+ALTER TABLE users
+    ADD COLUMN mfa_enabled BOOLEAN NOT NULL DEFAULT FALSE AFTER email_verified_at,
+    ADD COLUMN mfa_secret VARCHAR(64) NOT NULL DEFAULT '' AFTER mfa_enabled,
+    ADD COLUMN mfa_last_step BIGINT NOT NULL DEFAULT 0 AFTER mfa_secret;
+
+CREATE TABLE mfa_backup_codes (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_mfa_backup_codes_user_id (user_id),
+    INDEX idx_mfa_backup_codes_code_hash (code_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,5 @@
+ALTER TABLE refresh_tokens
+    DROP INDEX idx_refresh_tokens_family_id,
+    DROP COLUMN rotated_at,
+    DROP COLUMN parent_id,
+    DROP COLUMN family_id;
@@ -0,0 +1,8 @@
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id CHAR(36) NOT NULL DEFAULT '' AFTER token,
+    ADD COLUMN parent_id INT UNSIGNED NULL AFTER family_id,
+    ADD COLUMN rotated_at TIMESTAMP NULL AFTER revoked,
+    ADD INDEX idx_refresh_tokens_family_id (family_id);
+
+-- Token cũ chưa có family -> mỗi token là 1 family riêng
+UPDATE refresh_tokens SET family_id = UUID() WHERE family_id = '';
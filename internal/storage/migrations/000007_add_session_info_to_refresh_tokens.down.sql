@@ -0,0 +1,6 @@
+ALTER TABLE refresh_tokens
+    DROP COLUMN last_used_at,
+    DROP COLUMN authenticated_at,
+    DROP COLUMN device_name,
+    DROP COLUMN ip_address,
+    DROP COLUMN user_agent;
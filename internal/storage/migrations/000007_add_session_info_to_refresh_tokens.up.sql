@@ -0,0 +1,9 @@
+ALTER TABLE refresh_tokens
+    ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '' AFTER rotated_at,
+    ADD COLUMN ip_address VARCHAR(45) NOT NULL DEFAULT '' AFTER user_agent,
+    ADD COLUMN device_name VARCHAR(100) NOT NULL DEFAULT '' AFTER ip_address,
+    ADD COLUMN authenticated_at TIMESTAMP NULL AFTER device_name,
+    ADD COLUMN last_used_at TIMESTAMP NULL AFTER authenticated_at;
+
+-- Token cũ: lấy created_at làm thời điểm login/sử dụng gần nhất
+UPDATE refresh_tokens SET authenticated_at = created_at, last_used_at = created_at WHERE authenticated_at IS NULL;
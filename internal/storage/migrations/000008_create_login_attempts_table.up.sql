@@ -0,0 +1,13 @@
+CREATE TABLE login_attempts (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    subject_type ENUM('email', 'ip') NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    failed_count INT UNSIGNED NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NULL,
+    locked_until TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    UNIQUE INDEX idx_login_attempts_subject (subject_type, subject),
+    INDEX idx_login_attempts_locked_until (locked_until)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
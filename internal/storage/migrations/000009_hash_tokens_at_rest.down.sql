@@ -0,0 +1,3 @@
+-- Không khôi phục được token gốc từ digest: token đang có sẽ không còn khớp (user phải login lại)
+ALTER TABLE refresh_tokens MODIFY token VARCHAR(500) NOT NULL;
+ALTER TABLE password_resets MODIFY token VARCHAR(255) NOT NULL;
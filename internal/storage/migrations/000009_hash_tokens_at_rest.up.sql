@@ -0,0 +1,7 @@
+-- Chỉ lưu digest của token, DB bị lộ cũng không dùng lại được token
+-- Row cũ chuyển sang SHA-256 (hex thường), app vẫn tra cứu được kể cả khi bật TOKEN_HASH_PEPPER
+UPDATE refresh_tokens SET token = SHA2(token, 256);
+ALTER TABLE refresh_tokens MODIFY token CHAR(64) NOT NULL;
+
+UPDATE password_resets SET token = SHA2(token, 256);
+ALTER TABLE password_resets MODIFY token CHAR(64) NOT NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE password_history (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_password_history_user_id (user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Password hiện tại của user đã có là mục đầu tiên trong lịch sử
+INSERT INTO password_history (user_id, password_hash, created_at)
+SELECT id, password_hash, updated_at FROM users;
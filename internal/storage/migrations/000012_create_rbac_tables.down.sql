@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+
+UPDATE users SET role = 'user' WHERE role NOT IN ('admin', 'user');
+ALTER TABLE users MODIFY role ENUM('admin', 'user') NOT NULL DEFAULT 'user';
@@ -0,0 +1,7 @@
+ALTER TABLE refresh_tokens
+    DROP FOREIGN KEY fk_refresh_tokens_organization,
+    DROP COLUMN organization_id;
+
+DROP TABLE IF EXISTS organization_invitations;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN password_reset_required,
+    DROP COLUMN suspended_until,
+    DROP COLUMN status_reason;
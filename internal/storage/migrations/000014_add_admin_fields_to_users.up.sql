@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN status_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER status,
+    ADD COLUMN suspended_until TIMESTAMP NULL AFTER status_reason,
+    ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE AFTER suspended_until;
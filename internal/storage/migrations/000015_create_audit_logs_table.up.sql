@@ -0,0 +1,42 @@
+CREATE TABLE audit_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT UNSIGNED NULL,
+    target_id INT UNSIGNED NULL,
+    action VARCHAR(64) NOT NULL,
+    outcome ENUM('success', 'failure') NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    metadata TEXT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    
+    -- Không FK tới users: log phải giữ lại sau khi user bị xóa
+    INDEX idx_audit_logs_actor_id (actor_id),
+    INDEX idx_audit_logs_target_id (target_id),
+    INDEX idx_audit_logs_action (action),
+    INDEX idx_audit_logs_request_id (request_id),
+    INDEX idx_audit_logs_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Hash của dòng cuối cùng, khóa row này để ghi log tuần tự (chuỗi hash không bị rẽ nhánh)
+CREATE TABLE audit_chain_head (
+    id TINYINT UNSIGNED PRIMARY KEY,
+    last_hash CHAR(64) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO audit_chain_head (id, last_hash) VALUES (1, REPEAT('0', 64));
+
+-- Append-only: chặn sửa/xóa ở tầng database
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE ON audit_logs
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+
+CREATE TRIGGER audit_logs_no_delete BEFORE DELETE ON audit_logs
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+
+INSERT INTO permissions (name, description) VALUES
+    ('audit:read', 'View, export and verify the audit log');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin' AND p.name = 'audit:read';
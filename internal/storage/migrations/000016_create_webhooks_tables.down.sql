@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE name IN ('webhooks:read', 'webhooks:write');
+
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
@@ -0,0 +1,54 @@
+CREATE TABLE webhook_endpoints (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    secret VARCHAR(64) NOT NULL,
+    events VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Hàng đợi gửi: worker lấy các dòng pending đến hạn (next_attempt_at)
+CREATE TABLE webhook_deliveries (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    endpoint_id INT UNSIGNED NOT NULL,
+    event_id CHAR(36) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status ENUM('pending', 'succeeded', 'failed') NOT NULL DEFAULT 'pending',
+    attempt_count INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at TIMESTAMP NULL,
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    INDEX idx_webhook_deliveries_endpoint_id (endpoint_id, id),
+    INDEX idx_webhook_deliveries_event_id (event_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE webhook_delivery_attempts (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    delivery_id INT UNSIGNED NOT NULL,
+    attempt_number INT NOT NULL,
+    status_code INT NOT NULL DEFAULT 0,
+    response_body TEXT NULL,
+    error VARCHAR(1024) NOT NULL DEFAULT '',
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    INDEX idx_webhook_delivery_attempts_delivery_id (delivery_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO permissions (name, description) VALUES
+    ('webhooks:read', 'View webhook endpoints and delivery logs'),
+    ('webhooks:write', 'Manage webhook endpoints and redeliver events');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('webhooks:read', 'webhooks:write');
@@ -0,0 +1,31 @@
+-- Tài khoản ở identity provider bên ngoài (Google, GitHub...) liên kết với user
+CREATE TABLE user_identities (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    last_login_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_user_identities_provider_subject (provider, subject),
+    INDEX idx_user_identities_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- State của các lần đăng nhập OAuth đang chờ callback (xóa khi dùng hoặc hết hạn)
+CREATE TABLE oauth_states (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    state_hash VARCHAR(64) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    user_id INT UNSIGNED NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_oauth_states_state_hash (state_hash),
+    INDEX idx_oauth_states_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
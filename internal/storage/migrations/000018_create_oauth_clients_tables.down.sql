@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE name IN ('oauth_clients:read', 'oauth_clients:write');
+
+DROP TABLE IF EXISTS oauth_consents;
+DROP TABLE IF EXISTS oauth_authorization_codes;
+DROP TABLE IF EXISTS oauth_clients;
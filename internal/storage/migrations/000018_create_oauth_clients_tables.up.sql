@@ -0,0 +1,57 @@
+-- Relying party đăng nhập user qua authorization server này (OpenID Connect)
+CREATE TABLE oauth_clients (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    client_id VARCHAR(64) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    redirect_uris TEXT NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    public BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE INDEX idx_oauth_clients_client_id (client_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Authorization code dùng 1 lần, chỉ lưu digest
+CREATE TABLE oauth_authorization_codes (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code_hash VARCHAR(64) NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    redirect_uri VARCHAR(2048) NOT NULL,
+    scope VARCHAR(255) NOT NULL,
+    nonce VARCHAR(255) NOT NULL DEFAULT '',
+    code_challenge VARCHAR(128) NOT NULL,
+    auth_time TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (client_id) REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_oauth_authorization_codes_code_hash (code_hash),
+    INDEX idx_oauth_authorization_codes_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Scope user đã đồng ý cấp cho client (không hỏi lại lần sau)
+CREATE TABLE oauth_consents (
+    id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id INT UNSIGNED NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    scope VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (client_id) REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_oauth_consents_user_client (user_id, client_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO permissions (name, description) VALUES
+    ('oauth_clients:read', 'View OAuth clients'),
+    ('oauth_clients:write', 'Register and manage OAuth clients');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('oauth_clients:read', 'oauth_clients:write');
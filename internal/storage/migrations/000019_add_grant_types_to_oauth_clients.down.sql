@@ -0,0 +1,6 @@
+-- Client chỉ dùng client_credentials không còn dùng được
+DELETE FROM oauth_clients WHERE grant_types NOT LIKE '%authorization_code%';
+
+ALTER TABLE oauth_clients
+    DROP COLUMN grant_types,
+    MODIFY COLUMN scopes VARCHAR(255) NOT NULL;
@@ -0,0 +1,4 @@
+-- Grant type client được phép dùng; client_credentials dùng cho service, scope là tên permission
+ALTER TABLE oauth_clients
+    ADD COLUMN grant_types VARCHAR(100) NOT NULL DEFAULT 'authorization_code' AFTER scopes,
+    MODIFY COLUMN scopes VARCHAR(2048) NOT NULL;
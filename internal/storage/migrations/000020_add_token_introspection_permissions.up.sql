@@ -0,0 +1,8 @@
+-- Scope cho service gọi /oauth/introspect, /oauth/revoke với token cấp cho client khác
+INSERT INTO permissions (name, description) VALUES
+    ('tokens:introspect', 'Introspect tokens issued to any client'),
+    ('tokens:revoke', 'Revoke tokens issued to any client');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin' AND p.name IN ('tokens:introspect', 'tokens:revoke');
@@ -0,0 +1,2 @@
+-- Không khôi phục được token gốc từ digest: link xác thực đã gửi sẽ không còn dùng được (user gửi lại)
+ALTER TABLE email_verifications MODIFY token VARCHAR(255) NOT NULL;
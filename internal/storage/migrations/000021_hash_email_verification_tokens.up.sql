@@ -0,0 +1,4 @@
+-- Token xác thực email chỉ lưu digest như refresh token và reset token
+-- Row cũ chuyển sang SHA-256 (hex thường), link đã gửi vẫn dùng được kể cả khi bật TOKEN_HASH_PEPPER
+UPDATE email_verifications SET token = SHA2(token, 256);
+ALTER TABLE email_verifications MODIFY token CHAR(64) NOT NULL;
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+)
+
+// Định dạng export audit log
+const (
+	AuditExportCSV  = "csv"
+	AuditExportJSON = "json"
+)
+
+// auditGenesisHash - PrevHash của dòng đầu tiên
+var auditGenesisHash = strings.Repeat("0", 64)
+
+// auditCSVHeader - cột của file CSV export
+var auditCSVHeader = []string{
+	"id", "created_at", "actor_id", "target_id", "action", "outcome",
+	"ip_address", "user_agent", "request_id", "metadata", "prev_hash", "hash",
+}
+
+// auditUsecase - implement AuditUsecase interface
+type auditUsecase struct {
+	auditRepo repository.AuditLogRepository
+}
+
+// NewAuditUsecase - tạo audit usecase mới
+func NewAuditUsecase(auditRepo repository.AuditLogRepository) AuditUsecase {
+	return &auditUsecase{auditRepo: auditRepo}
+}
+
+// ListLogs - lấy danh sách audit log (admin only)
+func (u *auditUsecase) ListLogs(ctx context.Context, req *domain.ListAuditLogsRequest) (*domain.PaginatedAuditLogsResponse, error) {
+	// 1. Set defaults
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	// 2. Lấy danh sách từ repository
+	logs, total, err := u.auditRepo.List(ctx, req, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Tạo response
+	return &domain.PaginatedAuditLogsResponse{
+		Logs: logs,
+		Pagination: domain.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+		},
+	}, nil
+}
+
+// Export - ghi toàn bộ log khớp filter ra w (CSV hoặc JSON array), không load hết vào memory
+func (u *auditUsecase) Export(ctx context.Context, req *domain.ListAuditLogsRequest, format string, w io.Writer) error {
+	switch format {
+	case AuditExportCSV:
+		return u.exportCSV(ctx, req, w)
+	case AuditExportJSON:
+		return u.exportJSON(ctx, req, w)
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// exportCSV - 1 dòng header + 1 dòng mỗi log
+func (u *auditUsecase) exportCSV(ctx context.Context, req *domain.ListAuditLogsRequest, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return err
+	}
+
+	err := u.auditRepo.ForEach(ctx, req, func(logs []domain.AuditLog) error {
+		for _, log := range logs {
+			record := []string{
+				strconv.FormatUint(log.ID, 10),
+				log.CreatedAt.UTC().Format(time.RFC3339),
+				optionalCSVID(log.ActorID),
+				optionalCSVID(log.TargetID),
+				log.Action,
+				log.Outcome,
+				log.IPAddress,
+				log.UserAgent,
+				log.RequestID,
+				log.Metadata,
+				log.PrevHash,
+				log.Hash,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportJSON - JSON array, mỗi phần tử là 1 log
+func (u *auditUsecase) exportJSON(ctx context.Context, req *domain.ListAuditLogsRequest, w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	err := u.auditRepo.ForEach(ctx, req, func(logs []domain.AuditLog) error {
+		for _, log := range logs {
+			data, err := json.Marshal(log)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// VerifyChain - tính lại hash từng dòng và kiểm tra liên kết PrevHash (admin only)
+// Phát hiện dòng bị sửa, bị xóa ở giữa hoặc bị cắt đuôi (so với audit_chain_head)
+func (u *auditUsecase) VerifyChain(ctx context.Context) (*domain.AuditChainVerification, error) {
+	result := &domain.AuditChainVerification{Valid: true}
+	prevHash := auditGenesisHash
+
+	// 1. Duyệt toàn bộ chuỗi theo thứ tự ghi
+	err := u.auditRepo.ForEach(ctx, nil, func(logs []domain.AuditLog) error {
+		for i := range logs {
+			log := &logs[i]
+			if !result.Valid {
+				return nil
+			}
+			if log.PrevHash != prevHash || log.ComputeHash() != log.Hash {
+				id := log.ID
+				result.Valid = false
+				result.BrokenAt = &id
+				return nil
+			}
+			prevHash = log.Hash
+			result.Checked++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Valid {
+		return result, nil
+	}
+
+	// 2. Dòng cuối phải khớp đầu chuỗi đã lưu
+	head, err := u.auditRepo.GetChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head != prevHash {
+		result.Valid = false
+	}
+	result.LastHash = prevHash
+
+	return result, nil
+}
+
+// optionalCSVID - ô CSV cho ID có thể nil
+func optionalCSVID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
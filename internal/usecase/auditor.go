@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"go.uber.org/zap"
+)
+
+// auditFailures - số lần ghi audit log lỗi của mọi auditor trong process (hiển thị ở health check)
+var auditFailures struct {
+	count atomic.Int64
+	last  atomic.Int64 // Unix nano của lần lỗi gần nhất
+}
+
+// AuditWriteStatus - số lần ghi audit log lỗi từ khi khởi động và thời điểm lỗi gần nhất (zero = chưa lỗi)
+func AuditWriteStatus() (int64, time.Time) {
+	last := auditFailures.last.Load()
+	if last == 0 {
+		return 0, time.Time{}
+	}
+	return auditFailures.count.Load(), time.Unix(0, last)
+}
+
+// auditor - ghi sự kiện bảo mật vào audit log
+// Lỗi ghi log không làm fail request: được log ra zap và đếm lại để health check báo lỗi
+type auditor struct {
+	repo   repository.AuditLogRepository
+	logger *zap.Logger
+}
+
+// record - ghi 1 sự kiện, IP / user agent / request ID lấy từ context
+// ActorID không truyền -> dùng user đang đăng nhập của request
+func (a *auditor) record(ctx context.Context, event domain.AuditEvent) {
+	log := &domain.AuditLog{
+		ActorID:  event.ActorID,
+		TargetID: event.TargetID,
+		Action:   event.Action,
+		Outcome:  event.Outcome,
+	}
+	if log.Outcome == "" {
+		log.Outcome = domain.AuditOutcomeSuccess
+	}
+
+	if meta := domain.RequestMetaFromContext(ctx); meta != nil {
+		log.IPAddress = meta.IPAddress
+		log.UserAgent = meta.UserAgent
+		log.RequestID = meta.RequestID
+		if log.ActorID == nil {
+			log.ActorID = meta.ActorID
+		}
+	}
+
+	if len(event.Metadata) > 0 {
+		metadata, err := json.Marshal(event.Metadata)
+		if err != nil {
+			a.logger.Warn("Failed to encode audit metadata", zap.String("action", event.Action), zap.Error(err))
+		} else {
+			log.Metadata = string(metadata)
+		}
+	}
+
+	// Không gắn vào việc hủy request (client ngắt kết nối vẫn phải ghi được log)
+	if err := a.repo.Append(context.WithoutCancel(ctx), log); err != nil {
+		auditFailures.count.Add(1)
+		auditFailures.last.Store(time.Now().UnixNano())
+		a.logger.Error("Failed to write audit log",
+			zap.String("action", event.Action),
+			zap.String("request_id", log.RequestID),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/pkg/oauth"
+	"go.uber.org/zap"
+)
+
+// oauthStateTTL - thời gian user có để đăng nhập ở provider và quay lại callback
+const oauthStateTTL = 10 * time.Minute
+
+// StartOAuth - tạo state, nonce, PKCE verifier và URL chuyển hướng sang provider
+// linkUserID khác nil -> callback liên kết provider vào tài khoản này thay vì đăng nhập
+func (u *authUsecase) StartOAuth(ctx context.Context, providerName string, linkUserID *uint) (string, string, error) {
+	// 1. Lấy provider
+	provider, err := u.oauthProviders.Get(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	// 2. Tạo state, nonce và PKCE verifier
+	state, err := oauth.RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oauth.RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	codeVerifier, err := oauth.GenerateCodeVerifier()
+	if err != nil {
+		return "", "", err
+	}
+
+	// 3. Tạo URL (OIDC tải metadata ở lần đầu)
+	authURL, err := provider.AuthCodeURL(ctx, oauth.AuthRequest{
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: oauth.CodeChallengeS256(codeVerifier),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// 4. Lưu digest của state, verifier chỉ nằm phía server
+	oauthState := &domain.OAuthState{
+		StateHash:    u.tokenHashService.Hash(state),
+		Provider:     providerName,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		UserID:       linkUserID,
+		ExpiresAt:    time.Now().Add(oauthStateTTL),
+	}
+	if err := u.oauthStateRepo.Create(ctx, oauthState); err != nil {
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+// OAuthCallback - đổi code lấy identity rồi đăng nhập (hoặc liên kết tài khoản nếu state được tạo khi đã đăng nhập)
+func (u *authUsecase) OAuthCallback(ctx context.Context, providerName, code, state string, client *domain.ClientInfo) (*domain.OAuthCallbackResponse, error) {
+	// 1. Lấy và xóa state (dùng 1 lần)
+	oauthState, err := u.oauthStateRepo.Consume(ctx, u.tokenHashService.Hash(state))
+	if err != nil {
+		return nil, err
+	}
+	if oauthState == nil || oauthState.Provider != providerName || oauthState.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("invalid or expired oauth state")
+	}
+
+	// 2. Đổi code lấy identity (verify ID token, nonce, PKCE)
+	provider, err := u.oauthProviders.Get(providerName)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := provider.Exchange(ctx, code, oauthState.CodeVerifier, oauthState.Nonce)
+	if err != nil {
+		u.logger.Warn("OAuth code exchange failed", zap.String("provider", providerName), zap.Error(err))
+		return nil, fmt.Errorf("failed to sign in with %s: %w", providerName, err)
+	}
+
+	// 3. Luồng liên kết tài khoản
+	if oauthState.UserID != nil {
+		linked, err := u.linkIdentity(ctx, *oauthState.UserID, identity)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.OAuthCallbackResponse{LinkedIdentity: linked}, nil
+	}
+
+	// 4. Luồng đăng nhập: tìm user theo identity / email, không có thì tạo mới
+	user, err := u.resolveOAuthUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Kiểm tra user status và yêu cầu đặt lại password của admin
+	if !user.IsActive(time.Now()) {
+		u.auditLoginFailure(ctx, domain.AuditActionOAuthLogin, user.Email, user, "inactive")
+		return nil, errors.New("user account is not active")
+	}
+	if user.PasswordResetRequired {
+		u.auditLoginFailure(ctx, domain.AuditActionOAuthLogin, user.Email, user, "password_reset_required")
+		return nil, errors.New("password reset required, use the link sent to your email")
+	}
+	if u.requireEmailVerification && user.EmailVerifiedAt == nil {
+		u.auditLoginFailure(ctx, domain.AuditActionOAuthLogin, user.Email, user, "email_not_verified")
+		return nil, errors.New("email address is not verified")
+	}
+
+	// 6. Nếu bật MFA -> trả về challenge token như login bằng password
+	if user.MFAEnabled {
+		mfaToken, err := u.jwtService.GenerateMFAToken(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate mfa token: %w", err)
+		}
+		return &domain.OAuthCallbackResponse{LoginResponse: &domain.LoginResponse{MFARequired: true, MFAToken: mfaToken}}, nil
+	}
+
+	// 7. Tạo access/refresh token
+	resp, err := u.issueTokensAudited(ctx, user, client, domain.AuditActionOAuthLogin)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OAuthCallbackResponse{LoginResponse: resp}, nil
+}
+
+// resolveOAuthUser - quy tắc liên kết khi đăng nhập bằng provider:
+//  1. (provider, subject) đã liên kết -> user đó
+//  2. Email trùng user có sẵn -> chỉ tự liên kết khi cả provider và hệ thống đều đã xác thực email,
+//     tránh chiếm tài khoản bằng cách tạo tài khoản provider với email của người khác
+//  3. Chưa có user -> tạo user mới (không có password) nếu provider đã xác thực email
+func (u *authUsecase) resolveOAuthUser(ctx context.Context, identity *oauth.Identity) (*domain.User, error) {
+	// 1. Identity đã liên kết
+	existing, err := u.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		user, err := u.userRepo.GetByID(ctx, existing.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return nil, errors.New("user not found")
+		}
+		if err := u.identityRepo.TouchLogin(ctx, existing.ID, identity.Email); err != nil {
+			u.logger.Warn("Failed to update identity last login", zap.Uint("identity_id", existing.ID), zap.Error(err))
+		}
+		return user, nil
+	}
+
+	if identity.Email == "" {
+		return nil, errors.New("provider did not return an email address")
+	}
+
+	// 2. Email trùng user có sẵn
+	user, err := u.userRepo.GetByEmail(ctx, identity.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user != nil {
+		if !identity.EmailVerified || user.EmailVerifiedAt == nil {
+			u.auditLoginFailure(ctx, domain.AuditActionOAuthLogin, identity.Email, user, "account_exists")
+			return nil, errors.New("an account with this email already exists, sign in and link the provider from your account settings")
+		}
+		if _, err := u.createIdentity(ctx, user.ID, identity, "email_match"); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	// 3. Tạo user mới
+	if !identity.EmailVerified {
+		return nil, errors.New("email address is not verified by the provider")
+	}
+
+	now := time.Now()
+	user = &domain.User{
+		Email:           identity.Email,
+		PasswordHash:    "", // Chưa có password, đặt qua forgot-password nếu muốn login bằng password
+		FullName:        oauthFullName(identity),
+		Role:            "user",
+		Status:          domain.UserStatusActive,
+		EmailVerifiedAt: &now,
+	}
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		ActorID:  &user.ID,
+		TargetID: &user.ID,
+		Action:   domain.AuditActionRegister,
+		Metadata: map[string]interface{}{"provider": identity.Provider},
+	})
+	u.webhooks.publish(ctx, domain.WebhookEventUserRegistered, userEventData(user, map[string]interface{}{"provider": identity.Provider}))
+
+	if _, err := u.createIdentity(ctx, user.ID, identity, "signup"); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkIdentity - liên kết provider vào tài khoản đang đăng nhập
+func (u *authUsecase) linkIdentity(ctx context.Context, userID uint, identity *oauth.Identity) (*domain.UserIdentity, error) {
+	existing, err := u.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.UserID != userID {
+			return nil, errors.New("this provider account is already linked to another user")
+		}
+		return existing, nil
+	}
+
+	return u.createIdentity(ctx, userID, identity, "user")
+}
+
+// createIdentity - lưu identity và ghi audit (source: signup | email_match | user)
+func (u *authUsecase) createIdentity(ctx context.Context, userID uint, identity *oauth.Identity, source string) (*domain.UserIdentity, error) {
+	now := time.Now()
+	userIdentity := &domain.UserIdentity{
+		UserID:      userID,
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		LastLoginAt: &now,
+	}
+	if err := u.identityRepo.Create(ctx, userIdentity); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		ActorID:  &userID,
+		TargetID: &userID,
+		Action:   domain.AuditActionIdentityLink,
+		Metadata: map[string]interface{}{"provider": identity.Provider, "subject": identity.Subject, "source": source},
+	})
+	return userIdentity, nil
+}
+
+// ListIdentities - các provider đã liên kết với user
+func (u *authUsecase) ListIdentities(ctx context.Context, userID uint) ([]domain.UserIdentity, error) {
+	return u.identityRepo.ListForUser(ctx, userID)
+}
+
+// UnlinkIdentity - hủy liên kết provider (không cho xóa cách đăng nhập cuối cùng)
+func (u *authUsecase) UnlinkIdentity(ctx context.Context, userID, identityID uint) error {
+	// 1. Lấy user
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	// 2. User chưa có password thì phải còn ít nhất 1 provider
+	if user.PasswordHash == "" {
+		count, err := u.identityRepo.CountForUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return errors.New("cannot unlink the last sign-in method, set a password first")
+		}
+	}
+
+	// 3. Xóa
+	deleted, err := u.identityRepo.DeleteForUser(ctx, userID, identityID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("identity not found")
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		TargetID: &userID,
+		Action:   domain.AuditActionIdentityUnlink,
+		Metadata: map[string]interface{}{"identity_id": identityID},
+	})
+	return nil
+}
+
+// oauthFullName - tên hiển thị từ provider, không có thì lấy phần trước @ của email
+func oauthFullName(identity *oauth.Identity) string {
+	name := strings.TrimSpace(identity.Name)
+	if len(name) < 2 {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+	return name
+}
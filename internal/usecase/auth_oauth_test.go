@@ -0,0 +1,505 @@
+package usecase
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/jwt"
+	"github.com/me/go-gin-auth/pkg/oauth"
+	"github.com/me/go-gin-auth/pkg/oauth/oauthtest"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+	"go.uber.org/zap"
+)
+
+// Repository giả lập trong memory, chỉ implement các method luồng OAuth dùng
+// (gọi method khác sẽ panic vì interface nhúng là nil)
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[uint]*domain.User
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	return r.users[id], nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *domain.User) error {
+	user.ID = uint(len(r.users) + 1)
+	r.users[user.ID] = user
+	return nil
+}
+
+type fakeIdentityRepo struct {
+	repository.UserIdentityRepository
+	identities []*domain.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	identity.ID = uint(len(r.identities) + 1)
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeIdentityRepo) TouchLogin(ctx context.Context, id uint, email string) error {
+	return nil
+}
+
+type fakeOAuthStateRepo struct {
+	repository.OAuthStateRepository
+	states map[string]*domain.OAuthState
+}
+
+func (r *fakeOAuthStateRepo) Create(ctx context.Context, state *domain.OAuthState) error {
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *fakeOAuthStateRepo) Consume(ctx context.Context, stateHash string) (*domain.OAuthState, error) {
+	state := r.states[stateHash]
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+type fakeTokenRepo struct {
+	repository.TokenRepository
+	refreshTokens []*domain.RefreshToken
+}
+
+func (r *fakeTokenRepo) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	r.refreshTokens = append(r.refreshTokens, token)
+	return nil
+}
+
+type fakeRoleRepo struct{ repository.RoleRepository }
+
+func (r *fakeRoleRepo) GetUserPermissions(ctx context.Context, userID uint) ([]string, error) {
+	return nil, nil
+}
+
+type fakeOrgRepo struct {
+	repository.OrganizationRepository
+}
+
+func (r *fakeOrgRepo) FirstMembership(ctx context.Context, userID uint) (*domain.OrganizationMember, error) {
+	return nil, nil
+}
+
+type fakeAuditRepo struct {
+	repository.AuditLogRepository
+	logs []*domain.AuditLog
+}
+
+func (r *fakeAuditRepo) Append(ctx context.Context, log *domain.AuditLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+type fakeWebhookRepo struct{ repository.WebhookRepository }
+
+func (r *fakeWebhookRepo) ListSubscribed(ctx context.Context, eventType string) ([]domain.WebhookEndpoint, error) {
+	return nil, nil
+}
+
+// oauthTestEnv - auth usecase nối với provider OIDC giả lập
+type oauthTestEnv struct {
+	usecase    *authUsecase
+	server     *oauthtest.Server
+	users      *fakeUserRepo
+	identities *fakeIdentityRepo
+	states     *fakeOAuthStateRepo
+	tokens     *fakeTokenRepo
+}
+
+func newOAuthTestEnv(t *testing.T) *oauthTestEnv {
+	t.Helper()
+
+	server := oauthtest.NewServer(t)
+	provider, err := oauth.NewProvider(oauth.ProviderConfig{
+		Name:         "test",
+		Type:         oauth.ProviderTypeOIDC,
+		ClientID:     "test-client",
+		ClientSecret: oauthtest.ClientSecret,
+		RedirectURL:  "https://app.example.com/api/v1/auth/oauth/test/callback",
+		Issuer:       server.Issuer(),
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+
+	keyring, err := jwt.NewKeyring(jwt.NewHMACKey("test", "access-secret-for-oauth-tests-0123456789"))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+
+	env := &oauthTestEnv{
+		server:     server,
+		users:      &fakeUserRepo{users: make(map[uint]*domain.User)},
+		identities: &fakeIdentityRepo{},
+		states:     &fakeOAuthStateRepo{states: make(map[string]*domain.OAuthState)},
+		tokens:     &fakeTokenRepo{},
+	}
+	logger := zap.NewNop()
+	env.usecase = &authUsecase{
+		userRepo:         env.users,
+		tokenRepo:        env.tokens,
+		roleRepo:         &fakeRoleRepo{},
+		orgRepo:          &fakeOrgRepo{},
+		identityRepo:     env.identities,
+		oauthStateRepo:   env.states,
+		audit:            &auditor{repo: &fakeAuditRepo{}, logger: logger},
+		webhooks:         &webhookPublisher{repo: &fakeWebhookRepo{}, logger: logger},
+		jwtService:       jwt.NewJWTService(keyring, "refresh-secret-for-oauth-tests-0123456789", 15*time.Minute, time.Hour),
+		tokenHashService: tokenhash.NewTokenHashService("pepper"),
+		oauthProviders:   oauth.NewRegistry(provider),
+		logger:           logger,
+		refreshTokenTTL:  time.Hour,
+	}
+	return env
+}
+
+// addUser - user có sẵn trong hệ thống
+func (e *oauthTestEnv) addUser(email string, emailVerified bool) *domain.User {
+	user := &domain.User{Email: email, PasswordHash: "hash", FullName: "Existing", Role: "user", Status: domain.UserStatusActive}
+	if emailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	_ = e.users.Create(context.Background(), user)
+	return user
+}
+
+// start - bắt đầu luồng và giả lập user đồng ý ở provider, trả về code và state nhận ở callback
+func (e *oauthTestEnv) start(t *testing.T, linkUserID *uint, user oauthtest.User) (string, string) {
+	t.Helper()
+
+	authURL, state, err := e.usecase.StartOAuth(context.Background(), "test", linkUserID)
+	if err != nil {
+		t.Fatalf("StartOAuth: %v", err)
+	}
+	code, returnedState := e.server.Authorize(t, authURL, user)
+	if returnedState != state {
+		t.Fatalf("provider returned state %q, want %q", returnedState, state)
+	}
+	return code, state
+}
+
+// callback - gọi OAuthCallback như handler
+func (e *oauthTestEnv) callback(code, state string) (*domain.OAuthCallbackResponse, error) {
+	return e.usecase.OAuthCallback(context.Background(), "test", code, state, &domain.ClientInfo{IPAddress: "203.0.113.1"})
+}
+
+var verifiedAlice = oauthtest.User{Subject: "alice-sub", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+
+func TestStartOAuthStoresStateDigestAndPKCEVerifier(t *testing.T) {
+	env := newOAuthTestEnv(t)
+
+	authURL, state, err := env.usecase.StartOAuth(context.Background(), "test", nil)
+	if err != nil {
+		t.Fatalf("StartOAuth: %v", err)
+	}
+
+	// State chỉ lưu dạng digest, verifier chỉ nằm phía server
+	stored := env.states.states[env.usecase.tokenHashService.Hash(state)]
+	if stored == nil {
+		t.Fatal("state digest not stored")
+	}
+	if _, ok := env.states.states[state]; ok {
+		t.Error("raw state stored")
+	}
+
+	query := mustQuery(t, authURL)
+	if query.Get("code_challenge") != oauth.CodeChallengeS256(stored.CodeVerifier) || query.Get("code_challenge_method") != "S256" {
+		t.Errorf("code_challenge does not match stored verifier")
+	}
+	if strings.Contains(authURL, stored.CodeVerifier) {
+		t.Error("code verifier leaked in auth url")
+	}
+	if query.Get("nonce") != stored.Nonce || stored.Nonce == "" {
+		t.Errorf("nonce = %q, want stored nonce", query.Get("nonce"))
+	}
+	if stored.ExpiresAt.Sub(time.Now()) > oauthStateTTL {
+		t.Errorf("state expires too late: %v", stored.ExpiresAt)
+	}
+}
+
+func TestOAuthCallbackSignsUpNewUser(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	code, state := env.start(t, nil, verifiedAlice)
+
+	resp, err := env.callback(code, state)
+	if err != nil {
+		t.Fatalf("OAuthCallback: %v", err)
+	}
+	if resp.LoginResponse == nil || resp.AccessToken == "" || resp.RefreshToken == "" {
+		t.Fatalf("response = %+v, want tokens", resp)
+	}
+
+	user, _ := env.users.GetByEmail(context.Background(), verifiedAlice.Email)
+	if user == nil || user.PasswordHash != "" || user.EmailVerifiedAt == nil || user.FullName != "Alice" {
+		t.Fatalf("created user = %+v", user)
+	}
+	identity, _ := env.identities.GetByProviderSubject(context.Background(), "test", verifiedAlice.Subject)
+	if identity == nil || identity.UserID != user.ID {
+		t.Fatalf("identity = %+v, want linked to user %d", identity, user.ID)
+	}
+	if len(env.tokens.refreshTokens) != 1 || env.tokens.refreshTokens[0].IPAddress != "203.0.113.1" {
+		t.Errorf("refresh tokens = %+v", env.tokens.refreshTokens)
+	}
+}
+
+func TestOAuthCallbackRejectsInvalidState(t *testing.T) {
+	t.Run("reused", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		code, state := env.start(t, nil, verifiedAlice)
+		if _, err := env.callback(code, state); err != nil {
+			t.Fatalf("first callback: %v", err)
+		}
+
+		code, _ = env.start(t, nil, verifiedAlice)
+		if _, err := env.callback(code, state); err == nil || !strings.Contains(err.Error(), "invalid or expired oauth state") {
+			t.Fatalf("reused state error = %v", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		code, state := env.start(t, nil, verifiedAlice)
+		env.states.states[env.usecase.tokenHashService.Hash(state)].ExpiresAt = time.Now().Add(-time.Second)
+
+		if _, err := env.callback(code, state); err == nil || !strings.Contains(err.Error(), "invalid or expired oauth state") {
+			t.Fatalf("expired state error = %v", err)
+		}
+		if len(env.users.users) != 0 {
+			t.Error("user created with expired state")
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		code, _ := env.start(t, nil, verifiedAlice)
+		if _, err := env.callback(code, "forged-state"); err == nil {
+			t.Fatal("callback with unknown state succeeded")
+		}
+	})
+
+	t.Run("other provider", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		code, state := env.start(t, nil, verifiedAlice)
+		env.states.states[env.usecase.tokenHashService.Hash(state)].Provider = "github"
+
+		if _, err := env.callback(code, state); err == nil || !strings.Contains(err.Error(), "invalid or expired oauth state") {
+			t.Fatalf("provider mismatch error = %v", err)
+		}
+	})
+}
+
+func TestOAuthCallbackRejectsTamperedPKCEVerifier(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	code, state := env.start(t, nil, verifiedAlice)
+	env.states.states[env.usecase.tokenHashService.Hash(state)].CodeVerifier = "attacker-verifier-attacker-verifier-0123456"
+
+	if _, err := env.callback(code, state); err == nil {
+		t.Fatal("callback with wrong PKCE verifier succeeded")
+	}
+	if len(env.users.users) != 0 {
+		t.Error("user created despite failed exchange")
+	}
+}
+
+func TestOAuthCallbackAccountLinking(t *testing.T) {
+	tests := []struct {
+		name          string
+		existing      bool // Đã có user cùng email
+		localVerified bool // User có sẵn đã xác thực email
+		providerUser  oauthtest.User
+		wantErr       string
+		wantLinked    bool // Identity được liên kết vào user có sẵn
+	}{
+		{
+			name:          "verified email on both sides links existing account",
+			existing:      true,
+			localVerified: true,
+			providerUser:  verifiedAlice,
+			wantLinked:    true,
+		},
+		{
+			name:          "email not verified by provider",
+			existing:      true,
+			localVerified: true,
+			providerUser:  oauthtest.User{Subject: "alice-sub", Email: "alice@example.com"},
+			wantErr:       "already exists",
+		},
+		{
+			name:         "local account email not verified",
+			existing:     true,
+			providerUser: verifiedAlice,
+			wantErr:      "already exists",
+		},
+		{
+			name:         "new user with unverified email",
+			providerUser: oauthtest.User{Subject: "alice-sub", Email: "alice@example.com"},
+			wantErr:      "not verified by the provider",
+		},
+		{
+			name:         "provider returns no email",
+			providerUser: oauthtest.User{Subject: "alice-sub"},
+			wantErr:      "did not return an email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOAuthTestEnv(t)
+			var existing *domain.User
+			if tt.existing {
+				existing = env.addUser("alice@example.com", tt.localVerified)
+			}
+
+			code, state := env.start(t, nil, tt.providerUser)
+			resp, err := env.callback(code, state)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if len(env.identities.identities) != 0 {
+					t.Errorf("identity linked despite error: %+v", env.identities.identities[0])
+				}
+				if !tt.existing && len(env.users.users) != 0 {
+					t.Error("user created despite error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("OAuthCallback: %v", err)
+			}
+			if tt.wantLinked {
+				if resp.User == nil || resp.User.ID != existing.ID {
+					t.Errorf("signed in as %+v, want existing user %d", resp.User, existing.ID)
+				}
+				if len(env.identities.identities) != 1 || env.identities.identities[0].UserID != existing.ID {
+					t.Errorf("identities = %+v, want 1 linked to existing user", env.identities.identities)
+				}
+			}
+		})
+	}
+}
+
+func TestOAuthCallbackUsesLinkedIdentity(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	user := env.addUser("bob@example.com", false)
+	_ = env.identities.Create(context.Background(), &domain.UserIdentity{UserID: user.ID, Provider: "test", Subject: "bob-sub"})
+
+	// Email ở provider đã đổi và chưa xác thực -> vẫn đăng nhập theo (provider, subject)
+	code, state := env.start(t, nil, oauthtest.User{Subject: "bob-sub", Email: "bob@new.example.com"})
+	resp, err := env.callback(code, state)
+	if err != nil {
+		t.Fatalf("OAuthCallback: %v", err)
+	}
+	if resp.User == nil || resp.User.ID != user.ID {
+		t.Fatalf("signed in as %+v, want user %d", resp.User, user.ID)
+	}
+	if len(env.users.users) != 1 {
+		t.Error("duplicate user created")
+	}
+}
+
+func TestOAuthCallbackBlocksInactiveAndMFAUsers(t *testing.T) {
+	t.Run("inactive", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		user := env.addUser("alice@example.com", true)
+		user.Status = domain.UserStatusSuspended
+
+		code, state := env.start(t, nil, verifiedAlice)
+		if _, err := env.callback(code, state); err == nil || !strings.Contains(err.Error(), "not active") {
+			t.Fatalf("error = %v, want inactive", err)
+		}
+		if len(env.tokens.refreshTokens) != 0 {
+			t.Error("tokens issued for inactive user")
+		}
+	})
+
+	t.Run("mfa", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		user := env.addUser("alice@example.com", true)
+		user.MFAEnabled = true
+
+		code, state := env.start(t, nil, verifiedAlice)
+		resp, err := env.callback(code, state)
+		if err != nil {
+			t.Fatalf("OAuthCallback: %v", err)
+		}
+		if resp.LoginResponse == nil || !resp.MFARequired || resp.MFAToken == "" || resp.AccessToken != "" {
+			t.Fatalf("response = %+v, want mfa challenge only", resp.LoginResponse)
+		}
+	})
+}
+
+func TestOAuthCallbackLinksToSignedInUser(t *testing.T) {
+	t.Run("links new identity", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		user := env.addUser("carol@example.com", true)
+
+		// Email ở provider khác email tài khoản vẫn liên kết được vì user đã đăng nhập
+		code, state := env.start(t, &user.ID, verifiedAlice)
+		resp, err := env.callback(code, state)
+		if err != nil {
+			t.Fatalf("OAuthCallback: %v", err)
+		}
+		if resp.LoginResponse != nil {
+			t.Error("link flow issued tokens")
+		}
+		if resp.LinkedIdentity == nil || resp.LinkedIdentity.UserID != user.ID || resp.LinkedIdentity.Subject != verifiedAlice.Subject {
+			t.Fatalf("linked identity = %+v", resp.LinkedIdentity)
+		}
+	})
+
+	t.Run("identity owned by another user", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		owner := env.addUser("alice@example.com", true)
+		other := env.addUser("carol@example.com", true)
+		_ = env.identities.Create(context.Background(), &domain.UserIdentity{UserID: owner.ID, Provider: "test", Subject: verifiedAlice.Subject})
+
+		code, state := env.start(t, &other.ID, verifiedAlice)
+		if _, err := env.callback(code, state); err == nil || !strings.Contains(err.Error(), "already linked to another user") {
+			t.Fatalf("error = %v, want already linked", err)
+		}
+		if len(env.identities.identities) != 1 {
+			t.Error("identity linked twice")
+		}
+	})
+}
+
+func mustQuery(t *testing.T, rawURL string) url.Values {
+	t.Helper()
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	return parsed.Query()
+}
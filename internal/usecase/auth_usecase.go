@@ -10,18 +10,49 @@ import (
 	"github.com/me/go-gin-auth/internal/domain"
 	"github.com/me/go-gin-auth/internal/repository"
 	"github.com/me/go-gin-auth/pkg/jwt"
+	"github.com/me/go-gin-auth/pkg/mailer"
+	"github.com/me/go-gin-auth/pkg/oauth"
 	"github.com/me/go-gin-auth/pkg/password"
+	"github.com/me/go-gin-auth/pkg/revocation"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+	"github.com/me/go-gin-auth/pkg/totp"
+	"go.uber.org/zap"
+)
+
+const (
+	emailVerificationTTL = 24 * time.Hour // Thời gian sống của token xác thực email
+	passwordResetTTL     = time.Hour      // Thời gian sống của token reset password
 )
 
 // authUsecase - implement AuthUsecase interface
 type authUsecase struct {
-	userRepo          repository.UserRepository
-	tokenRepo         repository.TokenRepository
-	passwordResetRepo repository.PasswordResetRepository
-	jwtService        jwt.Service
-	passwordService   password.Service
-	accessTokenTTL    time.Duration
-	refreshTokenTTL   time.Duration
+	userRepo                 repository.UserRepository
+	tokenRepo                repository.TokenRepository
+	passwordResetRepo        repository.PasswordResetRepository
+	emailVerificationRepo    repository.EmailVerificationRepository
+	mfaBackupCodeRepo        repository.MFABackupCodeRepository
+	roleRepo                 repository.RoleRepository
+	patRepo                  repository.PersonalAccessTokenRepository
+	orgRepo                  repository.OrganizationRepository
+	identityRepo             repository.UserIdentityRepository
+	oauthStateRepo           repository.OAuthStateRepository
+	audit                    *auditor
+	webhooks                 *webhookPublisher
+	loginThrottle            *loginThrottle
+	notifier                 *notifier
+	jwtService               jwt.Service
+	passwordService          password.Service
+	passwordPolicy           *password.Policy
+	passwordHistory          *passwordHistory
+	passwordReset            *passwordResetIssuer
+	revoker                  *accessTokenRevoker
+	tokenHashService         tokenhash.Service // Băm refresh/reset token trước khi lưu DB
+	totpService              totp.Service
+	oauthProviders           *oauth.Registry // Identity provider cho đăng nhập OAuth/OIDC
+	logger                   *zap.Logger
+	accessTokenTTL           time.Duration
+	refreshTokenTTL          time.Duration
+	requireEmailVerification bool // Chặn login khi email chưa xác thực
 }
 
 // NewAuthUsecase - tạo auth usecase mới
@@ -29,18 +60,60 @@ func NewAuthUsecase(
 	userRepo repository.UserRepository,
 	tokenRepo repository.TokenRepository,
 	passwordResetRepo repository.PasswordResetRepository,
+	emailVerificationRepo repository.EmailVerificationRepository,
+	mfaBackupCodeRepo repository.MFABackupCodeRepository,
+	loginAttemptRepo repository.LoginAttemptRepository,
+	passwordHistoryRepo repository.PasswordHistoryRepository,
+	roleRepo repository.RoleRepository,
+	patRepo repository.PersonalAccessTokenRepository,
+	orgRepo repository.OrganizationRepository,
+	auditRepo repository.AuditLogRepository,
+	webhookRepo repository.WebhookRepository,
+	identityRepo repository.UserIdentityRepository,
+	oauthStateRepo repository.OAuthStateRepository,
 	jwtService jwt.Service,
+	revocationStore revocation.Store,
 	passwordService password.Service,
+	passwordPolicy *password.Policy,
+	tokenHashService tokenhash.Service,
+	totpService totp.Service,
+	oauthProviders *oauth.Registry,
+	mailService mailer.Service,
+	logger *zap.Logger,
 	accessTokenTTL, refreshTokenTTL time.Duration,
+	requireEmailVerification bool,
+	lockoutPolicy LockoutPolicy,
+	passwordHistorySize int,
 ) AuthUsecase {
+	notifier := &notifier{mailer: mailService, logger: logger}
 	return &authUsecase{
-		userRepo:          userRepo,
-		tokenRepo:         tokenRepo,
-		passwordResetRepo: passwordResetRepo,
-		jwtService:        jwtService,
-		passwordService:   passwordService,
-		accessTokenTTL:    accessTokenTTL,
-		refreshTokenTTL:   refreshTokenTTL,
+		userRepo:                 userRepo,
+		tokenRepo:                tokenRepo,
+		passwordResetRepo:        passwordResetRepo,
+		emailVerificationRepo:    emailVerificationRepo,
+		mfaBackupCodeRepo:        mfaBackupCodeRepo,
+		roleRepo:                 roleRepo,
+		patRepo:                  patRepo,
+		orgRepo:                  orgRepo,
+		identityRepo:             identityRepo,
+		oauthStateRepo:           oauthStateRepo,
+		audit:                    &auditor{repo: auditRepo, logger: logger},
+		webhooks:                 &webhookPublisher{repo: webhookRepo, logger: logger},
+		loginThrottle:            &loginThrottle{repo: loginAttemptRepo, policy: lockoutPolicy},
+		notifier:                 notifier,
+		jwtService:               jwtService,
+		passwordService:          passwordService,
+		passwordPolicy:           passwordPolicy,
+		passwordHistory:          &passwordHistory{repo: passwordHistoryRepo, passwordService: passwordService, size: passwordHistorySize},
+		passwordReset:            &passwordResetIssuer{repo: passwordResetRepo, tokenHashService: tokenHashService, notifier: notifier},
+		revoker:                  &accessTokenRevoker{store: revocationStore, accessTTL: accessTokenTTL},
+		tokenHashService:         tokenHashService,
+		totpService:              totpService,
+		oauthProviders:           oauthProviders,
+		logger:                   logger,
+		accessTokenTTL:           accessTokenTTL,
+		refreshTokenTTL:          refreshTokenTTL,
+		requireEmailVerification: requireEmailVerification,
 	}
 }
 
@@ -55,13 +128,18 @@ func (u *authUsecase) Register(ctx context.Context, req *domain.RegisterRequest)
 		return nil, errors.New("user with this email already exists")
 	}
 
-	// 2. Hash password
+	// 2. Kiểm tra password policy
+	if err := u.passwordPolicy.Validate(req.Password, req.Email, req.FullName); err != nil {
+		return nil, err
+	}
+
+	// 3. Hash password
 	hashedPassword, err := u.passwordService.HashPassword(req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// 3. Tạo user mới
+	// 4. Tạo user mới
 	user := &domain.User{
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
@@ -70,74 +148,280 @@ func (u *authUsecase) Register(ctx context.Context, req *domain.RegisterRequest)
 		Status:       "active", // Mặc định active
 	}
 
-	// 4. Lưu vào database
-	if err := u.userRepo.Create(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+	// 5. Lưu user cùng token xác thực email (1 transaction), password đầu tiên cũng vào lịch sử
+	verificationToken, verification := u.newEmailVerification()
+	if err := u.userRepo.CreateWithVerification(ctx, user, verification); err != nil {
+		return nil, err
+	}
+	if err := u.passwordHistory.record(ctx, user.ID, user.PasswordHash); err != nil {
+		return nil, err
 	}
 
-	// 5. Trả về user response (không có password)
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &user.ID, TargetID: &user.ID, Action: domain.AuditActionRegister})
+	u.webhooks.publish(ctx, domain.WebhookEventUserRegistered, userEventData(user, nil))
+
+	// 6. Gửi email chứa link xác thực (bất đồng bộ)
+	u.notifier.emailVerification(ctx, user, verificationToken, emailVerificationTTL)
+
+	// 7. Trả về user response (không có password)
 	return user.ToResponse(), nil
 }
 
 // Login - đăng nhập
-func (u *authUsecase) Login(ctx context.Context, req *domain.LoginRequest) (string, string, *domain.UserResponse, error) {
-	// 1. Tìm user theo email
+func (u *authUsecase) Login(ctx context.Context, req *domain.LoginRequest, client *domain.ClientInfo) (*domain.LoginResponse, error) {
+	// 1. Kiểm tra email/IP có đang bị khóa do login sai nhiều lần không
+	if err := u.loginThrottle.check(ctx, req.Email, client.IPAddress); err != nil {
+		u.auditLoginFailure(ctx, domain.AuditActionLogin, req.Email, nil, "locked")
+		return nil, err
+	}
+
+	// 2. Tìm user theo email
 	user, err := u.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// 3. Kiểm tra password (email không tồn tại cũng tính là 1 lần thất bại)
+	if user == nil || !u.passwordService.CheckPassword(req.Password, user.PasswordHash) {
+		u.auditLoginFailure(ctx, domain.AuditActionLogin, req.Email, user, "invalid_credentials")
+		if err := u.loginThrottle.recordFailure(ctx, req.Email, client.IPAddress); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid credentials") // Không nói cụ thể để tránh enumerate attack
+	}
+
+	// 4. Kiểm tra user status và yêu cầu đặt lại password của admin
+	if !user.IsActive(time.Now()) {
+		u.auditLoginFailure(ctx, domain.AuditActionLogin, req.Email, user, "inactive")
+		return nil, errors.New("user account is not active")
+	}
+	if user.PasswordResetRequired {
+		u.auditLoginFailure(ctx, domain.AuditActionLogin, req.Email, user, "password_reset_required")
+		return nil, errors.New("password reset required, use the link sent to your email")
+	}
+
+	// 5. Hash lại password nếu đang dùng thuật toán/tham số cũ (chỉ lúc này mới có password gốc)
+	u.rehashPassword(ctx, user, req.Password)
+
+	// 6. Kiểm tra email đã xác thực chưa (nếu bật REQUIRE_EMAIL_VERIFICATION)
+	if u.requireEmailVerification && user.EmailVerifiedAt == nil {
+		u.auditLoginFailure(ctx, domain.AuditActionLogin, req.Email, user, "email_not_verified")
+		return nil, errors.New("email address is not verified")
+	}
+
+	// 7. Nếu bật MFA -> trả về challenge token thay vì access/refresh token
+	// Chưa reset bộ đếm thất bại cho đến khi qua bước MFA
+	if user.MFAEnabled {
+		mfaToken, err := u.jwtService.GenerateMFAToken(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate mfa token: %w", err)
+		}
+		return &domain.LoginResponse{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	// 8. Login thành công -> xóa bộ đếm thất bại
+	if err := u.loginThrottle.reset(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	// 9. Tạo access/refresh token
+	return u.issueTokensAudited(ctx, user, client, domain.AuditActionLogin)
+}
+
+// auditLoginFailure - ghi login / MFA thất bại (user = nil nếu email không tồn tại)
+func (u *authUsecase) auditLoginFailure(ctx context.Context, action, email string, user *domain.User, reason string) {
+	event := domain.AuditEvent{
+		Action:   action,
+		Outcome:  domain.AuditOutcomeFailure,
+		Metadata: map[string]interface{}{"email": email, "reason": reason},
+	}
+	if user != nil {
+		event.TargetID = &user.ID
+	}
+	u.audit.record(ctx, event)
+}
+
+// issueTokensAudited - tạo token và ghi audit login thành công
+func (u *authUsecase) issueTokensAudited(ctx context.Context, user *domain.User, client *domain.ClientInfo, action string) (*domain.LoginResponse, error) {
+	resp, err := u.issueTokens(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &user.ID, TargetID: &user.ID, Action: action})
+	return resp, nil
+}
+
+// rehashPassword - nâng cấp hash password lên thuật toán hiện tại (lỗi chỉ log, không chặn login)
+func (u *authUsecase) rehashPassword(ctx context.Context, user *domain.User, plainPassword string) {
+	if !u.passwordService.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hashedPassword, err := u.passwordService.HashPassword(plainPassword)
+	if err != nil {
+		u.logger.Warn("Failed to rehash password", zap.Uint("user_id", user.ID), zap.Error(err))
+		return
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		u.logger.Warn("Failed to save rehashed password", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+}
+
+// VerifyMFA - hoàn tất login bằng mã TOTP hoặc backup code
+func (u *authUsecase) VerifyMFA(ctx context.Context, mfaToken, code string, client *domain.ClientInfo) (*domain.LoginResponse, error) {
+	// 1. Validate MFA challenge token
+	claims, err := u.jwtService.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mfa token: %w", err)
+	}
+
+	// 2. Lấy user
+	user, err := u.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return "", "", nil, errors.New("invalid credentials") // Không nói cụ thể để tránh enumerate attack
+		return nil, errors.New("user not found")
 	}
 
-	// 2. Kiểm tra password
-	if !u.passwordService.CheckPassword(req.Password, user.PasswordHash) {
-		return "", "", nil, errors.New("invalid credentials")
+	// 3. Kiểm tra lại trạng thái (có thể đã thay đổi sau bước password)
+	if !user.IsActive(time.Now()) {
+		return nil, errors.New("user account is not active")
+	}
+	if !user.MFAEnabled {
+		return nil, errors.New("mfa is not enabled")
 	}
 
-	// 3. Kiểm tra user status
-	if user.Status != "active" {
-		return "", "", nil, errors.New("user account is not active")
+	// 4. Kiểm tra khóa login (sai mã MFA cũng tính vào bộ đếm)
+	if err := u.loginThrottle.check(ctx, user.Email, client.IPAddress); err != nil {
+		return nil, err
 	}
 
-	// 4. Tạo access token
-	accessToken, err := u.jwtService.GenerateAccessToken(user.ID, user.Role)
+	// 5. Kiểm tra mã OTP hoặc backup code
+	ok, err := verifyMFACode(ctx, u.userRepo, u.mfaBackupCodeRepo, u.totpService, user, code)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to generate access token: %w", err)
+		return nil, err
+	}
+	if !ok {
+		u.auditLoginFailure(ctx, domain.AuditActionMFAVerify, user.Email, user, "invalid_code")
+		if err := u.loginThrottle.recordFailure(ctx, user.Email, client.IPAddress); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("invalid mfa code")
+	}
+
+	// 6. Login thành công -> xóa bộ đếm thất bại
+	if err := u.loginThrottle.reset(ctx, user.Email); err != nil {
+		return nil, err
 	}
 
-	// 5. Tạo refresh token
+	// 7. Tạo access/refresh token
+	return u.issueTokensAudited(ctx, user, client, domain.AuditActionMFAVerify)
+}
+
+// issueTokens - tạo access/refresh token và lưu refresh token vào database (bắt đầu session mới)
+func (u *authUsecase) issueTokens(ctx context.Context, user *domain.User, client *domain.ClientInfo) (*domain.LoginResponse, error) {
+	// Mỗi lần login là 1 family mới, family ID cũng là session ID
+	sessionID := uuid.New().String()
+
+	// 1. Organization mặc định là organization tham gia sớm nhất
+	var orgID *uint
+	membership, err := u.orgRepo.FirstMembership(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if membership != nil {
+		orgID = &membership.OrganizationID
+	}
+
+	// 2. Tạo access token
+	accessToken, err := u.generateAccessToken(ctx, user, sessionID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Tạo refresh token
 	refreshToken, err := u.jwtService.GenerateRefreshToken(user.ID)
 	if err != nil {
-		return "", "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	// 6. Lưu refresh token vào database
+	// 4. Lưu digest của refresh token vào database
+	now := time.Now()
 	refreshTokenEntity := &domain.RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(u.refreshTokenTTL),
+		UserID:          user.ID,
+		Token:           u.tokenHashService.Hash(refreshToken),
+		FamilyID:        sessionID,
+		OrganizationID:  orgID,
+		ExpiresAt:       now.Add(u.refreshTokenTTL),
+		UserAgent:       client.UserAgent,
+		IPAddress:       client.IPAddress,
+		DeviceName:      client.DeviceName,
+		AuthenticatedAt: now,
+		LastUsedAt:      now,
 	}
 
 	if err := u.tokenRepo.CreateRefreshToken(ctx, refreshTokenEntity); err != nil {
-		return "", "", nil, fmt.Errorf("failed to save refresh token: %w", err)
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
 	}
 
-	return accessToken, refreshToken, user.ToResponse(), nil
+	return &domain.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User:         user.ToResponse(),
+	}, nil
 }
 
-// Logout - đăng xuất (vô hiệu hóa refresh token)
-func (u *authUsecase) Logout(ctx context.Context, userID uint, refreshToken string) error {
-	// Vô hiệu hóa refresh token
-	if err := u.tokenRepo.RevokeRefreshToken(ctx, refreshToken); err != nil {
+// Logout - đăng xuất (vô hiệu hóa refresh token và access token đang dùng)
+// accessTokenID, accessTokenExpiresAt là jti và hạn của access token đang dùng, bị thu hồi ngay
+func (u *authUsecase) Logout(ctx context.Context, userID uint, refreshToken, accessTokenID string, accessTokenExpiresAt time.Time) error {
+	// 1. Thu hồi access token đang dùng
+	if err := u.revoker.revokeToken(ctx, accessTokenID, accessTokenExpiresAt); err != nil {
+		return err
+	}
+
+	// 2. Tìm refresh token theo digest
+	tokenEntity, err := findRefreshToken(ctx, u.tokenRepo, u.tokenHashService, refreshToken)
+	if err != nil {
+		return err
+	}
+	if tokenEntity == nil {
+		return nil
+	}
+
+	// 3. Vô hiệu hóa refresh token
+	if err := u.tokenRepo.RevokeRefreshToken(ctx, tokenEntity.Token); err != nil {
 		return fmt.Errorf("failed to revoke refresh token: %w", err)
 	}
 
+	u.audit.record(ctx, domain.AuditEvent{
+		ActorID:  &userID,
+		TargetID: &tokenEntity.UserID,
+		Action:   domain.AuditActionLogout,
+		Metadata: map[string]interface{}{"session_id": tokenEntity.FamilyID},
+	})
 	return nil
 }
 
-// RefreshToken - làm mới token (token rotation)
-func (u *authUsecase) RefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+// findRefreshToken - tìm refresh token theo digest (gồm cả digest SHA-256 của row cũ)
+func findRefreshToken(ctx context.Context, tokenRepo repository.TokenRepository, tokenHashService tokenhash.Service, refreshToken string) (*domain.RefreshToken, error) {
+	for _, digest := range tokenHashService.Candidates(refreshToken) {
+		tokenEntity, err := tokenRepo.GetRefreshToken(ctx, digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get refresh token: %w", err)
+		}
+		if tokenEntity != nil {
+			return tokenEntity, nil
+		}
+	}
+	return nil, nil
+}
+
+// RefreshToken - làm mới token (token rotation + reuse detection)
+func (u *authUsecase) RefreshToken(ctx context.Context, refreshToken string, client *domain.ClientInfo) (string, string, error) {
 	// 1. Validate refresh token format
 	claims, err := u.jwtService.ValidateRefreshToken(refreshToken)
 	if err != nil {
@@ -145,25 +429,30 @@ func (u *authUsecase) RefreshToken(ctx context.Context, refreshToken string) (st
 	}
 
 	// 2. Kiểm tra token có trong database không
-	tokenEntity, err := u.tokenRepo.GetRefreshToken(ctx, refreshToken)
+	tokenEntity, err := findRefreshToken(ctx, u.tokenRepo, u.tokenHashService, refreshToken)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get refresh token: %w", err)
+		return "", "", err
 	}
 	if tokenEntity == nil {
 		return "", "", errors.New("refresh token not found")
 	}
 
-	// 3. Kiểm tra token đã bị revoke chưa
+	// 3. Token đã rotate mà bị dùng lại -> có thể đã bị đánh cắp, revoke cả family
+	if tokenEntity.RotatedAt != nil {
+		return "", "", u.handleRefreshTokenReuse(ctx, tokenEntity)
+	}
+
+	// 4. Kiểm tra token đã bị revoke chưa
 	if tokenEntity.Revoked {
 		return "", "", errors.New("refresh token is revoked")
 	}
 
-	// 4. Kiểm tra token đã hết hạn chưa
+	// 5. Kiểm tra token đã hết hạn chưa
 	if tokenEntity.ExpiresAt.Before(time.Now()) {
 		return "", "", errors.New("refresh token is expired")
 	}
 
-	// 5. Lấy user info
+	// 6. Lấy user info
 	user, err := u.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get user: %w", err)
@@ -171,16 +460,24 @@ func (u *authUsecase) RefreshToken(ctx context.Context, refreshToken string) (st
 	if user == nil {
 		return "", "", errors.New("user not found")
 	}
-
-	// 6. Vô hiệu hóa token cũ (Token Rotation Pattern)
-	if err := u.tokenRepo.RevokeRefreshToken(ctx, refreshToken); err != nil {
-		return "", "", fmt.Errorf("failed to revoke old refresh token: %w", err)
+	if !user.IsActive(time.Now()) {
+		return "", "", errors.New("user account is not active")
 	}
 
-	// 7. Tạo token mới
-	newAccessToken, err := u.jwtService.GenerateAccessToken(user.ID, user.Role)
+	// 7. Tạo token mới (permission, membership lấy lại để nhận thay đổi role)
+	orgID := tokenEntity.OrganizationID
+	if orgID != nil {
+		member, err := u.orgRepo.GetMember(ctx, *orgID, user.ID)
+		if err != nil {
+			return "", "", err
+		}
+		if member == nil {
+			orgID = nil // Đã bị xóa khỏi organization
+		}
+	}
+	newAccessToken, err := u.generateAccessToken(ctx, user, tokenEntity.FamilyID, orgID)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate new access token: %w", err)
+		return "", "", err
 	}
 
 	newRefreshToken, err := u.jwtService.GenerateRefreshToken(user.ID)
@@ -188,20 +485,130 @@ func (u *authUsecase) RefreshToken(ctx context.Context, refreshToken string) (st
 		return "", "", fmt.Errorf("failed to generate new refresh token: %w", err)
 	}
 
-	// 8. Lưu refresh token mới
+	// 8. Rotate: vô hiệu hóa token cũ và lưu token mới cùng family (Token Rotation Pattern)
+	// Giữ device name và thời điểm login, cập nhật IP/user agent mới nhất
+	now := time.Now()
 	newTokenEntity := &domain.RefreshToken{
-		UserID:    user.ID,
-		Token:     newRefreshToken,
-		ExpiresAt: time.Now().Add(u.refreshTokenTTL),
+		UserID:          user.ID,
+		Token:           u.tokenHashService.Hash(newRefreshToken),
+		FamilyID:        tokenEntity.FamilyID,
+		ParentID:        &tokenEntity.ID,
+		OrganizationID:  orgID,
+		ExpiresAt:       now.Add(u.refreshTokenTTL),
+		UserAgent:       client.UserAgent,
+		IPAddress:       client.IPAddress,
+		DeviceName:      tokenEntity.DeviceName,
+		AuthenticatedAt: tokenEntity.AuthenticatedAt,
+		LastUsedAt:      now,
+	}
+
+	rotated, err := u.tokenRepo.RotateRefreshToken(ctx, tokenEntity.ID, newTokenEntity)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to rotate refresh token: %w", err)
 	}
-
-	if err := u.tokenRepo.CreateRefreshToken(ctx, newTokenEntity); err != nil {
-		return "", "", fmt.Errorf("failed to save new refresh token: %w", err)
+	if !rotated {
+		// Request khác đã rotate token này trước -> xử lý như reuse
+		return "", "", u.handleRefreshTokenReuse(ctx, tokenEntity)
 	}
 
 	return newAccessToken, newRefreshToken, nil
 }
 
+// generateAccessToken - tạo access token kèm permission hiệu lực và organization đang làm việc
+func (u *authUsecase) generateAccessToken(ctx context.Context, user *domain.User, sessionID string, orgID *uint) (string, error) {
+	permissions, err := u.roleRepo.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.AccessClaims{
+		UserID:      user.ID,
+		Role:        user.Role,
+		SessionID:   sessionID,
+		Permissions: permissions,
+	}
+	if orgID != nil {
+		claims.OrganizationID = *orgID
+	}
+
+	accessToken, err := u.jwtService.GenerateAccessToken(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+	return accessToken, nil
+}
+
+// SwitchOrganization - đổi organization đang làm việc của session, trả về access token mới
+// Refresh token giữ nguyên, các lần refresh sau dùng organization mới
+func (u *authUsecase) SwitchOrganization(ctx context.Context, userID uint, sessionID string, orgID uint) (*domain.SwitchOrganizationResponse, error) {
+	if sessionID == "" {
+		return nil, errors.New("current session is unknown")
+	}
+
+	// 1. Phải là thành viên
+	member, err := u.orgRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.New("organization not found")
+	}
+
+	// 2. Lấy user
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// 3. Lưu vào session để refresh giữ organization
+	if err := u.tokenRepo.SetFamilyOrganization(ctx, sessionID, &orgID); err != nil {
+		return nil, err
+	}
+
+	// 4. Access token mới
+	accessToken, err := u.generateAccessToken(ctx, user, sessionID, &orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SwitchOrganizationResponse{AccessToken: accessToken, OrganizationID: orgID}, nil
+}
+
+// handleRefreshTokenReuse - revoke cả family khi phát hiện refresh token bị dùng lại
+// Theo OAuth 2.0 Security BCP: không phân biệt được client thật và kẻ tấn công nên revoke hết
+func (u *authUsecase) handleRefreshTokenReuse(ctx context.Context, tokenEntity *domain.RefreshToken) error {
+	if err := u.tokenRepo.RevokeFamily(ctx, tokenEntity.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	// Access token đã cấp từ family này (có thể đang nằm trong tay kẻ tấn công) cũng bị thu hồi
+	if err := u.revoker.revokeSession(ctx, tokenEntity.FamilyID); err != nil {
+		return err
+	}
+
+	u.logger.Warn("Security event: refresh token reuse detected",
+		zap.String("event", "refresh_token_reuse"),
+		zap.Uint("user_id", tokenEntity.UserID),
+		zap.String("family_id", tokenEntity.FamilyID),
+		zap.Uint("token_id", tokenEntity.ID),
+	)
+	u.audit.record(ctx, domain.AuditEvent{
+		TargetID: &tokenEntity.UserID,
+		Action:   domain.AuditActionRefreshTokenReuse,
+		Outcome:  domain.AuditOutcomeFailure,
+		Metadata: map[string]interface{}{"session_id": tokenEntity.FamilyID, "token_id": tokenEntity.ID},
+	})
+
+	// Báo cho chủ tài khoản (không fail nếu không lấy được user)
+	if user, err := u.userRepo.GetByID(ctx, tokenEntity.UserID); err == nil && user != nil {
+		u.notifier.securityAlert(ctx, user, alertRefreshTokenReused, nil)
+	}
+
+	return errors.New("refresh token reuse detected, all sessions in this family have been revoked")
+}
+
 // ForgotPassword - quên password (gửi reset token)
 func (u *authUsecase) ForgotPassword(ctx context.Context, email string) error {
 	// 1. Kiểm tra user có tồn tại không
@@ -211,38 +618,43 @@ func (u *authUsecase) ForgotPassword(ctx context.Context, email string) error {
 	}
 	if user == nil {
 		// Không nói email không tồn tại để tránh enumerate attack
+		u.audit.record(ctx, domain.AuditEvent{
+			Action:   domain.AuditActionPasswordResetRequest,
+			Outcome:  domain.AuditOutcomeFailure,
+			Metadata: map[string]interface{}{"email": email, "reason": "unknown_email"},
+		})
 		return nil
 	}
 
-	// 2. Tạo reset token
-	resetToken := uuid.New().String()
-
-	// 3. Tạo password reset record
-	passwordReset := &domain.PasswordReset{
-		Email:     email,
-		Token:     resetToken,
-		ExpiresAt: time.Now().Add(1 * time.Hour), // Hết hạn sau 1 giờ
-	}
-
-	if err := u.passwordResetRepo.Create(ctx, passwordReset); err != nil {
-		return fmt.Errorf("failed to create password reset: %w", err)
+	// 2. Tạo reset token và gửi email
+	if err := u.passwordReset.issue(ctx, user); err != nil {
+		return err
 	}
 
-	// 4. TODO: Gửi email chứa reset token
-	// Trong thực tế, bạn sẽ gửi email có link: https://yourapp.com/reset-password?token=resetToken
-	fmt.Printf("Password reset token for %s: %s\n", email, resetToken)
-
+	u.audit.record(ctx, domain.AuditEvent{TargetID: &user.ID, Action: domain.AuditActionPasswordResetRequest})
 	return nil
 }
 
 // ResetPassword - đặt lại password bằng reset token
 func (u *authUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
-	// 1. Lấy password reset record
-	passwordReset, err := u.passwordResetRepo.GetByToken(ctx, token)
-	if err != nil {
-		return fmt.Errorf("failed to get password reset: %w", err)
+	// 1. Lấy password reset record theo digest
+	var passwordReset *domain.PasswordReset
+	for _, digest := range u.tokenHashService.Candidates(token) {
+		found, err := u.passwordResetRepo.GetByToken(ctx, digest)
+		if err != nil {
+			return fmt.Errorf("failed to get password reset: %w", err)
+		}
+		if found != nil {
+			passwordReset = found
+			break
+		}
 	}
 	if passwordReset == nil {
+		u.audit.record(ctx, domain.AuditEvent{
+			Action:   domain.AuditActionPasswordReset,
+			Outcome:  domain.AuditOutcomeFailure,
+			Metadata: map[string]interface{}{"reason": "invalid_token"},
+		})
 		return errors.New("invalid reset token")
 	}
 
@@ -265,27 +677,165 @@ func (u *authUsecase) ResetPassword(ctx context.Context, token, newPassword stri
 		return errors.New("user not found")
 	}
 
-	// 5. Hash password mới
+	// 5. Kiểm tra password policy và lịch sử password
+	if err := u.passwordPolicy.Validate(newPassword, user.Email, user.FullName); err != nil {
+		return err
+	}
+	if err := u.passwordHistory.check(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	// 6. Hash password mới
 	hashedPassword, err := u.passwordService.HashPassword(newPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// 6. Update password
+	// 7. Đánh dấu token đã được sử dụng trước khi đổi password (chặn 2 request dùng cùng token)
+	consumed, err := u.passwordResetRepo.MarkAsUsed(ctx, passwordReset.Token)
+	if err != nil {
+		return fmt.Errorf("failed to mark reset token as used: %w", err)
+	}
+	if !consumed {
+		return errors.New("reset token is already used")
+	}
+
+	// 8. Update password (xóa yêu cầu reset của admin) và lưu vào lịch sử
 	user.PasswordHash = hashedPassword
+	user.PasswordResetRequired = false
 	if err := u.userRepo.Update(ctx, user); err != nil {
 		return fmt.Errorf("failed to update user password: %w", err)
 	}
-
-	// 7. Đánh dấu token đã được sử dụng
-	if err := u.passwordResetRepo.MarkAsUsed(ctx, token); err != nil {
-		return fmt.Errorf("failed to mark reset token as used: %w", err)
+	if err := u.passwordHistory.record(ctx, user.ID, hashedPassword); err != nil {
+		return err
 	}
 
-	// 8. Vô hiệu hóa tất cả refresh token của user (force re-login)
+	// 9. Vô hiệu hóa tất cả refresh token, access token và personal access token của user (force re-login)
 	if err := u.tokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
 		return fmt.Errorf("failed to revoke user tokens: %w", err)
 	}
+	if err := u.revoker.revokeUser(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := u.patRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// 10. Mở khóa login (chủ tài khoản đã chứng minh quyền sở hữu email)
+	if err := u.loginThrottle.reset(ctx, user.Email); err != nil {
+		return err
+	}
+
+	// 11. Cảnh báo chủ tài khoản
+	u.notifier.securityAlert(ctx, user, alertPasswordReset, nil)
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &user.ID, TargetID: &user.ID, Action: domain.AuditActionPasswordReset})
+
+	return nil
+}
+
+// VerifyEmail - xác thực email bằng verification token
+func (u *authUsecase) VerifyEmail(ctx context.Context, token string) error {
+	// 1. Lấy verification record theo digest
+	var verification *domain.EmailVerification
+	for _, digest := range u.tokenHashService.Candidates(token) {
+		found, err := u.emailVerificationRepo.GetByToken(ctx, digest)
+		if err != nil {
+			return fmt.Errorf("failed to get email verification: %w", err)
+		}
+		if found != nil {
+			verification = found
+			break
+		}
+	}
+	if verification == nil {
+		return errors.New("invalid verification token")
+	}
+
+	// 2. Kiểm tra token đã được dùng chưa
+	if verification.Used {
+		return errors.New("verification token is already used")
+	}
+
+	// 3. Kiểm tra token đã hết hạn chưa
+	if verification.ExpiresAt.Before(time.Now()) {
+		return errors.New("verification token is expired")
+	}
+
+	// 4. Lấy user
+	user, err := u.userRepo.GetByID(ctx, verification.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	// 5. Đánh dấu token đã được sử dụng (chặn 2 request dùng cùng token)
+	consumed, err := u.emailVerificationRepo.MarkAsUsed(ctx, verification.Token)
+	if err != nil {
+		return fmt.Errorf("failed to mark verification token as used: %w", err)
+	}
+	if !consumed {
+		return errors.New("verification token is already used")
+	}
+
+	// 6. Set email_verified_at (nếu chưa xác thực)
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := u.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		u.webhooks.publish(ctx, domain.WebhookEventUserEmailVerified, userEventData(user, nil))
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &user.ID, TargetID: &user.ID, Action: domain.AuditActionEmailVerify})
+	return nil
+}
+
+// ResendVerification - gửi lại email xác thực
+func (u *authUsecase) ResendVerification(ctx context.Context, email string) error {
+	// 1. Kiểm tra user có tồn tại không
+	user, err := u.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.EmailVerifiedAt != nil {
+		// Không nói email không tồn tại / đã xác thực để tránh enumerate attack
+		return nil
+	}
+
+	// 2. Vô hiệu hóa các token cũ
+	if err := u.emailVerificationRepo.InvalidateAllForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to invalidate old verification tokens: %w", err)
+	}
+
+	// 3. Tạo token mới
+	return u.issueEmailVerification(ctx, user)
+}
+
+// newEmailVerification - tạo verification token, record chỉ lưu digest (chưa gán user)
+func (u *authUsecase) newEmailVerification() (string, *domain.EmailVerification) {
+	verificationToken := uuid.New().String()
+	return verificationToken, &domain.EmailVerification{
+		Token:     u.tokenHashService.Hash(verificationToken),
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	}
+}
+
+// issueEmailVerification - tạo và gửi verification token cho user
+func (u *authUsecase) issueEmailVerification(ctx context.Context, user *domain.User) error {
+	// 1. Tạo verification token
+	verificationToken, verification := u.newEmailVerification()
+	verification.UserID = user.ID
+
+	// 2. Lưu vào database
+	if err := u.emailVerificationRepo.Create(ctx, verification); err != nil {
+		return fmt.Errorf("failed to create email verification: %w", err)
+	}
+
+	// 3. Gửi email chứa link xác thực (bất đồng bộ)
+	u.notifier.emailVerification(ctx, user, verificationToken, emailVerificationTTL)
 
 	return nil
 }
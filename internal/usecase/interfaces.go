@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/me/go-gin-auth/internal/domain"
 )
@@ -9,11 +11,21 @@ import (
 // AuthUsecase - interface cho authentication logic
 type AuthUsecase interface {
 	Register(ctx context.Context, req *domain.RegisterRequest) (*domain.UserResponse, error)
-	Login(ctx context.Context, req *domain.LoginRequest) (string, string, *domain.UserResponse, error) // accessToken, refreshToken, user, error
-	Logout(ctx context.Context, userID uint, refreshToken string) error
-	RefreshToken(ctx context.Context, refreshToken string) (string, string, error) // newAccessToken, newRefreshToken, error
+	Login(ctx context.Context, req *domain.LoginRequest, client *domain.ClientInfo) (*domain.LoginResponse, error) // tokens hoặc MFA challenge
+	VerifyMFA(ctx context.Context, mfaToken, code string, client *domain.ClientInfo) (*domain.LoginResponse, error)
+	Logout(ctx context.Context, userID uint, refreshToken, accessTokenID string, accessTokenExpiresAt time.Time) error
+	RefreshToken(ctx context.Context, refreshToken string, client *domain.ClientInfo) (string, string, error) // newAccessToken, newRefreshToken, error
 	ForgotPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	SwitchOrganization(ctx context.Context, userID uint, sessionID string, orgID uint) (*domain.SwitchOrganizationResponse, error)
+
+	// Đăng nhập / liên kết tài khoản qua identity provider (OAuth2 / OIDC)
+	StartOAuth(ctx context.Context, provider string, linkUserID *uint) (string, string, error) // authorizationURL, state, error
+	OAuthCallback(ctx context.Context, provider, code, state string, client *domain.ClientInfo) (*domain.OAuthCallbackResponse, error)
+	ListIdentities(ctx context.Context, userID uint) ([]domain.UserIdentity, error)
+	UnlinkIdentity(ctx context.Context, userID, identityID uint) error
 }
 
 // UserUsecase - interface cho user management logic
@@ -22,4 +34,106 @@ type UserUsecase interface {
 	UpdateProfile(ctx context.Context, userID uint, req *domain.UpdateProfileRequest) (*domain.UserResponse, error)
 	ChangePassword(ctx context.Context, userID uint, req *domain.ChangePasswordRequest) error
 	ListUsers(ctx context.Context, req *domain.ListUsersRequest) (*domain.PaginatedUsersResponse, error)
+	GetLockoutStatus(ctx context.Context, userID uint) (*domain.LockoutStatusResponse, error)
+	Unlock(ctx context.Context, userID uint, ip string) error
+
+	// Admin quản lý user
+	GetUser(ctx context.Context, userID uint) (*domain.AdminUserResponse, error)
+	AdminUpdateUser(ctx context.Context, userID uint, req *domain.AdminUpdateUserRequest) (*domain.AdminUserResponse, error)
+	DeleteUser(ctx context.Context, actorID, userID uint) error
+	ChangeRole(ctx context.Context, actorID, userID uint, req *domain.ChangeRoleRequest) (*domain.AdminUserResponse, error)
+	ChangeStatus(ctx context.Context, actorID, userID uint, req *domain.ChangeStatusRequest) (*domain.AdminUserResponse, error)
+	ForcePasswordReset(ctx context.Context, userID uint) error
+	RevokeSessions(ctx context.Context, userID uint) error
+}
+
+// MFAUsecase - interface cho two-factor authentication (TOTP)
+type MFAUsecase interface {
+	Enroll(ctx context.Context, userID uint) (*domain.MFAEnrollResponse, error)
+	Confirm(ctx context.Context, userID uint, code string) (*domain.MFABackupCodesResponse, error)
+	Disable(ctx context.Context, userID uint, req *domain.DisableMFARequest) error
+}
+
+// SessionUsecase - interface cho quản lý session đăng nhập
+type SessionUsecase interface {
+	ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]domain.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID uint, currentSessionID string) error
+}
+
+// RoleUsecase - interface cho quản lý role và permission (RBAC)
+type RoleUsecase interface {
+	ListRoles(ctx context.Context) ([]domain.Role, error)
+	ListPermissions(ctx context.Context) ([]domain.Permission, error)
+	CreateRole(ctx context.Context, req *domain.CreateRoleRequest) (*domain.Role, error)
+	UpdateRole(ctx context.Context, roleID uint, req *domain.UpdateRoleRequest) (*domain.Role, error)
+	DeleteRole(ctx context.Context, roleID uint) error
+	GetUserRoles(ctx context.Context, userID uint) (*domain.UserRolesResponse, error)
+	SetUserRoles(ctx context.Context, userID uint, req *domain.SetUserRolesRequest) (*domain.UserRolesResponse, error)
+}
+
+// OrganizationUsecase - interface cho organization (multi-tenant), thành viên và lời mời
+type OrganizationUsecase interface {
+	Create(ctx context.Context, userID uint, req *domain.CreateOrganizationRequest) (*domain.OrganizationResponse, error)
+	List(ctx context.Context, userID uint) ([]domain.OrganizationResponse, error)
+	Get(ctx context.Context, userID, orgID uint) (*domain.OrganizationResponse, error)
+	Update(ctx context.Context, userID, orgID uint, req *domain.UpdateOrganizationRequest) (*domain.OrganizationResponse, error)
+	Delete(ctx context.Context, userID, orgID uint) error
+	ListMembers(ctx context.Context, userID, orgID uint, req *domain.ListUsersRequest) (*domain.PaginatedMembersResponse, error)
+	UpdateMember(ctx context.Context, actorID, orgID, targetID uint, req *domain.UpdateMemberRequest) error
+	RemoveMember(ctx context.Context, actorID, orgID, targetID uint) error
+	CreateInvitation(ctx context.Context, actorID, orgID uint, req *domain.CreateInvitationRequest) (*domain.OrganizationInvitation, error)
+	ListInvitations(ctx context.Context, actorID, orgID uint) ([]domain.OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, actorID, orgID, invitationID uint) error
+	AcceptInvitation(ctx context.Context, userID uint, token string) (*domain.OrganizationResponse, error)
+}
+
+// PersonalAccessTokenUsecase - interface cho personal access token (script, CI)
+type PersonalAccessTokenUsecase interface {
+	Create(ctx context.Context, userID uint, req *domain.CreatePersonalAccessTokenRequest) (*domain.CreatePersonalAccessTokenResponse, error)
+	List(ctx context.Context, userID uint) ([]domain.PersonalAccessTokenResponse, error)
+	Revoke(ctx context.Context, userID, tokenID uint) error
+	Authenticate(ctx context.Context, token, ipAddress string) (*domain.PersonalAccessTokenAuth, error) // Dùng bởi AuthMiddleware
+}
+
+// OAuthClientUsecase - interface cho quản lý client của authorization server (admin)
+type OAuthClientUsecase interface {
+	ListClients(ctx context.Context) ([]domain.OAuthClientResponse, error)
+	CreateClient(ctx context.Context, actorID uint, req *domain.CreateOAuthClientRequest) (*domain.OAuthClientResponse, error)
+	GetClient(ctx context.Context, id uint) (*domain.OAuthClientResponse, error)
+	UpdateClient(ctx context.Context, actorID, id uint, req *domain.UpdateOAuthClientRequest) (*domain.OAuthClientResponse, error)
+	DeleteClient(ctx context.Context, id uint) error
+	RotateClientSecret(ctx context.Context, id uint) (*domain.OAuthClientResponse, error)
+}
+
+// OAuthServerUsecase - interface cho authorization server (OpenID Connect provider) của các app nội bộ
+type OAuthServerUsecase interface {
+	ValidateAuthorize(ctx context.Context, req *domain.AuthorizeRequest) error
+	GetConsent(ctx context.Context, userID uint, req *domain.AuthorizeRequest) (*domain.OAuthConsentInfoResponse, error)
+	Consent(ctx context.Context, userID uint, req *domain.OAuthConsentRequest) (*domain.OAuthConsentResponse, error)
+	Token(ctx context.Context, req *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error)
+	UserInfo(ctx context.Context, accessToken string) (map[string]interface{}, error)
+	Introspect(ctx context.Context, req *domain.OAuthTokenActionRequest) (*domain.IntrospectionResponse, error)
+	Revoke(ctx context.Context, req *domain.OAuthTokenActionRequest) error
+	Discovery() *domain.OpenIDConfiguration
+}
+
+// AuditUsecase - interface cho xem, export và kiểm tra audit log (admin)
+type AuditUsecase interface {
+	ListLogs(ctx context.Context, req *domain.ListAuditLogsRequest) (*domain.PaginatedAuditLogsResponse, error)
+	Export(ctx context.Context, req *domain.ListAuditLogsRequest, format string, w io.Writer) error
+	VerifyChain(ctx context.Context) (*domain.AuditChainVerification, error)
+}
+
+// WebhookUsecase - interface cho quản lý webhook endpoint và lịch sử gửi (admin)
+type WebhookUsecase interface {
+	ListEndpoints(ctx context.Context) ([]domain.WebhookEndpointResponse, error)
+	CreateEndpoint(ctx context.Context, req *domain.CreateWebhookEndpointRequest) (*domain.WebhookEndpointResponse, error)
+	GetEndpoint(ctx context.Context, endpointID uint) (*domain.WebhookEndpointResponse, error)
+	UpdateEndpoint(ctx context.Context, endpointID uint, req *domain.UpdateWebhookEndpointRequest) (*domain.WebhookEndpointResponse, error)
+	DeleteEndpoint(ctx context.Context, endpointID uint) error
+	RotateSecret(ctx context.Context, endpointID uint) (*domain.WebhookEndpointResponse, error)
+	ListDeliveries(ctx context.Context, endpointID uint, req *domain.ListWebhookDeliveriesRequest) (*domain.PaginatedWebhookDeliveriesResponse, error)
+	GetDelivery(ctx context.Context, deliveryID uint) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID uint) (*domain.WebhookDelivery, error)
 }
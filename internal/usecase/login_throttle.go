@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+)
+
+// LockoutPolicy - cấu hình khóa login khi thất bại nhiều lần
+type LockoutPolicy struct {
+	Threshold   int           // Số lần thất bại liên tiếp theo email trước khi khóa tạm thời (<= 0 là tắt)
+	IPThreshold int           // Số lần thất bại liên tiếp theo IP trước khi khóa tạm thời (<= 0 là tắt)
+	Duration    time.Duration // Thời gian khóa, đồng thời là khoảng thời gian đếm lại từ đầu
+	BackoffBase time.Duration // Delay sau lần thất bại đầu tiên, nhân đôi sau mỗi lần tiếp theo
+	BackoffMax  time.Duration // Delay tối đa giữa 2 lần thử
+}
+
+// LoginLockedError - lỗi khi email/IP đang bị khóa login
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+// Error - implement error interface
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// loginThrottle - theo dõi login thất bại, áp dụng back-off và khóa tạm thời
+type loginThrottle struct {
+	repo   repository.LoginAttemptRepository
+	policy LockoutPolicy
+}
+
+// check - trả về *LoginLockedError nếu email hoặc IP đang bị khóa
+func (t *loginThrottle) check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, s := range t.subjects(email, ip) {
+		attempt, err := t.repo.Get(ctx, s.subjectType, s.subject)
+		if err != nil {
+			return fmt.Errorf("failed to check login attempts: %w", err)
+		}
+		if attempt != nil && attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			if wait := attempt.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordFailure - ghi nhận 1 lần thất bại và tính thời điểm được thử lại
+func (t *loginThrottle) recordFailure(ctx context.Context, email, ip string) error {
+	now := time.Now()
+
+	for _, s := range t.subjects(email, ip) {
+		attempt, err := t.repo.RecordFailure(ctx, s.subjectType, s.subject, t.policy.Duration)
+		if err != nil {
+			return fmt.Errorf("failed to record login failure: %w", err)
+		}
+
+		// Đủ ngưỡng -> khóa tạm thời, chưa đủ -> back-off (chỉ áp dụng theo email)
+		var lockFor time.Duration
+		switch {
+		case attempt.FailedCount >= s.threshold:
+			lockFor = t.policy.Duration
+		case s.subjectType == domain.LoginAttemptSubjectEmail:
+			lockFor = t.backoff(attempt.FailedCount)
+		}
+
+		if lockFor > 0 {
+			if err := t.repo.SetLockedUntil(ctx, attempt.ID, now.Add(lockFor)); err != nil {
+				return fmt.Errorf("failed to lock login: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// reset - xóa trạng thái thất bại của email (login thành công, reset password)
+func (t *loginThrottle) reset(ctx context.Context, email string) error {
+	if err := t.repo.Reset(ctx, domain.LoginAttemptSubjectEmail, normalizeEmail(email)); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// backoff - delay theo cấp số nhân: base, 2*base, 4*base... tối đa BackoffMax
+func (t *loginThrottle) backoff(failedCount int) time.Duration {
+	if t.policy.BackoffBase <= 0 || failedCount <= 0 {
+		return 0
+	}
+
+	delay := t.policy.BackoffBase
+	for i := 1; i < failedCount; i++ {
+		delay *= 2
+		if t.policy.BackoffMax > 0 && delay >= t.policy.BackoffMax {
+			return t.policy.BackoffMax
+		}
+	}
+	return delay
+}
+
+// throttleSubject - 1 đối tượng bị theo dõi cùng ngưỡng khóa tương ứng
+type throttleSubject struct {
+	subjectType string
+	subject     string
+	threshold   int
+}
+
+// subjects - danh sách đối tượng đang bật theo dõi
+func (t *loginThrottle) subjects(email, ip string) []throttleSubject {
+	var subjects []throttleSubject
+	if t.policy.Threshold > 0 && email != "" {
+		subjects = append(subjects, throttleSubject{domain.LoginAttemptSubjectEmail, normalizeEmail(email), t.policy.Threshold})
+	}
+	if t.policy.IPThreshold > 0 && ip != "" {
+		subjects = append(subjects, throttleSubject{domain.LoginAttemptSubjectIP, ip, t.policy.IPThreshold})
+	}
+	return subjects
+}
+
+// normalizeEmail - chuẩn hóa email để đếm chung "User@x.com" và "user@x.com"
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
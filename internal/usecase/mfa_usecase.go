@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/mailer"
+	"github.com/me/go-gin-auth/pkg/password"
+	"github.com/me/go-gin-auth/pkg/totp"
+	"github.com/me/go-gin-auth/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// backupCodeCount - số lượng backup codes tạo ra mỗi lần
+const backupCodeCount = 10
+
+// mfaUsecase - implement MFAUsecase interface
+type mfaUsecase struct {
+	userRepo        repository.UserRepository
+	backupCodeRepo  repository.MFABackupCodeRepository
+	audit           *auditor
+	totpService     totp.Service
+	passwordService password.Service
+	notifier        *notifier
+}
+
+// NewMFAUsecase - tạo MFA usecase mới
+func NewMFAUsecase(
+	userRepo repository.UserRepository,
+	backupCodeRepo repository.MFABackupCodeRepository,
+	auditRepo repository.AuditLogRepository,
+	totpService totp.Service,
+	passwordService password.Service,
+	mailService mailer.Service,
+	logger *zap.Logger,
+) MFAUsecase {
+	return &mfaUsecase{
+		userRepo:        userRepo,
+		backupCodeRepo:  backupCodeRepo,
+		audit:           &auditor{repo: auditRepo, logger: logger},
+		totpService:     totpService,
+		passwordService: passwordService,
+		notifier:        &notifier{mailer: mailService, logger: logger},
+	}
+}
+
+// Enroll - bắt đầu đăng ký MFA (tạo secret, chưa bật)
+func (u *mfaUsecase) Enroll(ctx context.Context, userID uint) (*domain.MFAEnrollResponse, error) {
+	// 1. Lấy user
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// 2. Không cho enroll lại khi MFA đang bật
+	if user.MFAEnabled {
+		return nil, errors.New("mfa is already enabled")
+	}
+
+	// 3. Tạo secret mới
+	secret, err := u.totpService.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mfa secret: %w", err)
+	}
+
+	// 4. Lưu secret (MFA chỉ bật sau khi confirm)
+	user.MFASecret = secret
+	user.MFALastStep = 0
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return &domain.MFAEnrollResponse{
+		Secret: secret,
+		URI:    u.totpService.GenerateURI(secret, user.Email),
+	}, nil
+}
+
+// Confirm - xác nhận mã OTP đầu tiên, bật MFA và tạo backup codes
+func (u *mfaUsecase) Confirm(ctx context.Context, userID uint, code string) (*domain.MFABackupCodesResponse, error) {
+	// 1. Lấy user
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// 2. Kiểm tra trạng thái enroll
+	if user.MFAEnabled {
+		return nil, errors.New("mfa is already enabled")
+	}
+	if user.MFASecret == "" {
+		return nil, errors.New("mfa enrollment not started")
+	}
+
+	// 3. Kiểm tra mã OTP
+	step, ok := u.totpService.Validate(user.MFASecret, code, user.MFALastStep)
+	if !ok {
+		return nil, errors.New("invalid mfa code")
+	}
+	consumed, err := u.userRepo.ConsumeMFAStep(ctx, user.ID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, errors.New("invalid mfa code")
+	}
+
+	// 4. Bật MFA
+	user.MFAEnabled = true
+	user.MFALastStep = step
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// 5. Tạo backup codes
+	codes, err := u.generateBackupCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 6. Cảnh báo chủ tài khoản
+	u.notifier.securityAlert(ctx, user, alertMFAEnabled, nil)
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &user.ID, TargetID: &user.ID, Action: domain.AuditActionMFAEnable})
+
+	return &domain.MFABackupCodesResponse{BackupCodes: codes}, nil
+}
+
+// Disable - tắt MFA (yêu cầu password và mã OTP/backup code)
+func (u *mfaUsecase) Disable(ctx context.Context, userID uint, req *domain.DisableMFARequest) error {
+	// 1. Lấy user
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if !user.MFAEnabled {
+		return errors.New("mfa is not enabled")
+	}
+
+	// 2. Kiểm tra password
+	if !u.passwordService.CheckPassword(req.Password, user.PasswordHash) {
+		return errors.New("invalid password")
+	}
+
+	// 3. Kiểm tra mã OTP hoặc backup code
+	ok, err := verifyMFACode(ctx, u.userRepo, u.backupCodeRepo, u.totpService, user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid mfa code")
+	}
+
+	// 4. Tắt MFA và xóa secret
+	user.MFAEnabled = false
+	user.MFASecret = ""
+	user.MFALastStep = 0
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// 5. Xóa backup codes
+	if err := u.backupCodeRepo.DeleteForUser(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete backup codes: %w", err)
+	}
+
+	// 6. Cảnh báo chủ tài khoản
+	u.notifier.securityAlert(ctx, user, alertMFADisabled, nil)
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &user.ID, TargetID: &user.ID, Action: domain.AuditActionMFADisable})
+
+	return nil
+}
+
+// generateBackupCodes - tạo bộ backup codes mới, chỉ lưu hash
+func (u *mfaUsecase) generateBackupCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, backupCodeCount)
+	hashes := make([]string, backupCodeCount)
+
+	for i := range codes {
+		code, err := utils.GenerateRandomString(10)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate backup code: %w", err)
+		}
+		codes[i] = code
+		hashes[i] = hashBackupCode(code)
+	}
+
+	if err := u.backupCodeRepo.ReplaceForUser(ctx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to save backup codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// verifyMFACode - kiểm tra mã TOTP hoặc backup code của user
+// Dùng chung cho login challenge và các thao tác cần xác thực lại MFA
+func verifyMFACode(
+	ctx context.Context,
+	userRepo repository.UserRepository,
+	backupCodeRepo repository.MFABackupCodeRepository,
+	totpService totp.Service,
+	user *domain.User,
+	code string,
+) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	// 1. Thử mã TOTP trước
+	if step, ok := totpService.Validate(user.MFASecret, code, user.MFALastStep); ok {
+		// Lưu step đã dùng để mã này không dùng lại được (request khác đã dùng trước -> từ chối)
+		consumed, err := userRepo.ConsumeMFAStep(ctx, user.ID, step)
+		if err != nil {
+			return false, err
+		}
+		if consumed {
+			user.MFALastStep = step
+		}
+		return consumed, nil
+	}
+
+	// 2. Thử backup code (mỗi code chỉ dùng 1 lần)
+	ok, err := backupCodeRepo.Consume(ctx, user.ID, hashBackupCode(code))
+	if err != nil {
+		return false, fmt.Errorf("failed to check backup code: %w", err)
+	}
+
+	return ok, nil
+}
+
+// hashBackupCode - hash backup code bằng SHA-256 (code đã đủ entropy, không cần bcrypt)
+func hashBackupCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
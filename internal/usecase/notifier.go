@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+// Mô tả các sự kiện bảo mật trong email cảnh báo
+const (
+	alertPasswordChanged    = "Your password was changed."
+	alertPasswordReset      = "Your password was reset using a password reset link. All sessions have been signed out."
+	alertMFAEnabled         = "Two-factor authentication was enabled on your account."
+	alertMFADisabled        = "Two-factor authentication was disabled on your account."
+	alertRefreshTokenReused = "A previously used sign-in token was presented again. We signed out the affected session as a precaution."
+)
+
+// notifier - gửi email cho user (reset password, xác thực email, cảnh báo bảo mật)
+// Lỗi gửi email chỉ được log, không làm fail request
+type notifier struct {
+	mailer mailer.Service
+	logger *zap.Logger
+}
+
+// passwordReset - gửi link reset password
+func (n *notifier) passwordReset(ctx context.Context, user *domain.User, token string, ttl time.Duration) {
+	n.send(ctx, user.Email, mailer.TemplatePasswordReset, mailer.PasswordResetData{
+		Name:      user.FullName,
+		Token:     token,
+		ExpiresIn: ttl,
+	})
+}
+
+// emailVerification - gửi link xác thực email
+func (n *notifier) emailVerification(ctx context.Context, user *domain.User, token string, ttl time.Duration) {
+	n.send(ctx, user.Email, mailer.TemplateEmailVerification, mailer.EmailVerificationData{
+		Name:      user.FullName,
+		Token:     token,
+		ExpiresIn: ttl,
+	})
+}
+
+// organizationInvitation - gửi lời mời tham gia organization (người nhận có thể chưa có tài khoản)
+func (n *notifier) organizationInvitation(ctx context.Context, email string, org *domain.Organization, inviter *domain.User, role, token string, ttl time.Duration) {
+	n.send(ctx, email, mailer.TemplateOrganizationInvitation, mailer.OrganizationInvitationData{
+		OrganizationName: org.Name,
+		InviterName:      inviter.FullName,
+		Role:             role,
+		Token:            token,
+		ExpiresIn:        ttl,
+	})
+}
+
+// securityAlert - gửi cảnh báo bảo mật (client có thể nil)
+func (n *notifier) securityAlert(ctx context.Context, user *domain.User, event string, client *domain.ClientInfo) {
+	data := mailer.SecurityAlertData{
+		Name:  user.FullName,
+		Event: event,
+		Time:  time.Now(),
+	}
+	if client != nil {
+		data.IPAddress = client.IPAddress
+		data.UserAgent = client.UserAgent
+	}
+	n.send(ctx, user.Email, mailer.TemplateSecurityAlert, data)
+}
+
+// send - render và đưa email vào hàng đợi
+func (n *notifier) send(ctx context.Context, to, templateName string, data interface{}) {
+	if err := n.mailer.Send(ctx, to, templateName, data); err != nil {
+		n.logger.Error("Failed to queue email",
+			zap.String("template", templateName),
+			zap.String("to", to),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,402 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/oauth"
+	"github.com/me/go-gin-auth/pkg/revocation"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+	"github.com/me/go-gin-auth/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// oauthClientSecretPrefix - prefix của client secret (dễ nhận biết khi lộ)
+const oauthClientSecretPrefix = "cs_"
+
+// oauthClientUsecase - implement OAuthClientUsecase interface
+type oauthClientUsecase struct {
+	clientRepo       repository.OAuthClientRepository
+	roleRepo         repository.RoleRepository
+	tokenHashService tokenhash.Service
+	audit            *auditor
+	revoker          *accessTokenRevoker
+}
+
+// NewOAuthClientUsecase - tạo oauth client usecase mới
+func NewOAuthClientUsecase(
+	clientRepo repository.OAuthClientRepository,
+	roleRepo repository.RoleRepository,
+	auditRepo repository.AuditLogRepository,
+	tokenHashService tokenhash.Service,
+	revocationStore revocation.Store,
+	logger *zap.Logger,
+	accessTokenTTL time.Duration,
+) OAuthClientUsecase {
+	return &oauthClientUsecase{
+		clientRepo:       clientRepo,
+		roleRepo:         roleRepo,
+		tokenHashService: tokenHashService,
+		audit:            &auditor{repo: auditRepo, logger: logger},
+		revoker:          &accessTokenRevoker{store: revocationStore, accessTTL: accessTokenTTL},
+	}
+}
+
+// ListClients - danh sách client (admin only)
+func (u *oauthClientUsecase) ListClients(ctx context.Context) ([]domain.OAuthClientResponse, error) {
+	clients, err := u.clientRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.OAuthClientResponse, len(clients))
+	for i := range clients {
+		responses[i] = *clients[i].ToResponse()
+	}
+	return responses, nil
+}
+
+// CreateClient - đăng ký client, secret chỉ trả về 1 lần (admin only)
+func (u *oauthClientUsecase) CreateClient(ctx context.Context, actorID uint, req *domain.CreateOAuthClientRequest) (*domain.OAuthClientResponse, error) {
+	// 1. Kiểm tra redirect URI
+	redirectURIs, err := normalizeRedirectURIs(req.RedirectURIs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Mặc định: app đăng nhập user với tất cả scope OIDC
+	grantTypes := req.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{domain.GrantTypeAuthorizationCode}
+	}
+	scopes := req.Scopes
+	if len(scopes) == 0 && utils.Contains(grantTypes, domain.GrantTypeAuthorizationCode) {
+		scopes = domain.OIDCScopes
+	}
+
+	// 3. Sinh client_id
+	clientID, err := oauth.RandomString(16)
+	if err != nil {
+		return nil, err
+	}
+	client := &domain.OAuthClient{
+		ClientID:     clientID,
+		Name:         strings.TrimSpace(req.Name),
+		RedirectURIs: strings.Join(redirectURIs, "\n"),
+		Scopes:       joinFields(scopes),
+		GrantTypes:   joinFields(grantTypes),
+		Public:       req.Public,
+	}
+	if err := u.validateClient(ctx, client); err != nil {
+		return nil, err
+	}
+	granted, err := u.checkGrantedScopes(ctx, actorID, client, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Sinh secret (public client không có secret)
+	var secret string
+	if !client.Public {
+		if secret, err = generateClientSecret(); err != nil {
+			return nil, err
+		}
+		client.SecretHash = u.tokenHashService.Hash(secret)
+	}
+
+	// 5. Lưu
+	if err := u.clientRepo.Create(ctx, client); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionOAuthClientCreate,
+		Metadata: map[string]interface{}{"client_id": client.ClientID, "name": client.Name, "public": client.Public},
+	})
+	u.recordGrant(ctx, client, granted)
+
+	resp := client.ToResponse()
+	resp.ClientSecret = secret
+	return resp, nil
+}
+
+// GetClient - chi tiết client (admin only)
+func (u *oauthClientUsecase) GetClient(ctx context.Context, id uint) (*domain.OAuthClientResponse, error) {
+	client, err := u.getClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return client.ToResponse(), nil
+}
+
+// UpdateClient - đổi tên, redirect URI hoặc scope được phép (admin only)
+func (u *oauthClientUsecase) UpdateClient(ctx context.Context, actorID, id uint, req *domain.UpdateOAuthClientRequest) (*domain.OAuthClientResponse, error) {
+	// 1. Lấy client
+	client, err := u.getClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previousScopes := client.ScopeList()
+
+	// 2. Cập nhật các field được gửi
+	if req.Name != nil {
+		client.Name = strings.TrimSpace(*req.Name)
+	}
+	if len(req.RedirectURIs) > 0 {
+		redirectURIs, err := normalizeRedirectURIs(req.RedirectURIs)
+		if err != nil {
+			return nil, err
+		}
+		client.RedirectURIs = strings.Join(redirectURIs, "\n")
+	}
+	if len(req.Scopes) > 0 {
+		client.Scopes = joinFields(req.Scopes)
+	}
+	if len(req.GrantTypes) > 0 {
+		client.GrantTypes = joinFields(req.GrantTypes)
+	}
+	if err := u.validateClient(ctx, client); err != nil {
+		return nil, err
+	}
+	granted, err := u.checkGrantedScopes(ctx, actorID, client, previousScopes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Lưu
+	if err := u.clientRepo.Update(ctx, client); err != nil {
+		return nil, err
+	}
+
+	// 4. Thu hẹp scope -> access token đã cấp mang scope cũ bị thu hồi
+	for _, scope := range previousScopes {
+		if !utils.Contains(client.ScopeList(), scope) {
+			if err := u.revoker.revokeClient(ctx, client.ClientID); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionOAuthClientUpdate,
+		Metadata: map[string]interface{}{"client_id": client.ClientID},
+	})
+	u.recordGrant(ctx, client, granted)
+	return client.ToResponse(), nil
+}
+
+// DeleteClient - xóa client, code và consent của client (admin only)
+// Access token đã cấp cho client bị thu hồi
+func (u *oauthClientUsecase) DeleteClient(ctx context.Context, id uint) error {
+	client, err := u.getClient(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := u.clientRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("oauth client not found")
+	}
+	if err := u.revoker.revokeClient(ctx, client.ClientID); err != nil {
+		return err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionOAuthClientDelete,
+		Metadata: map[string]interface{}{"client_id": client.ClientID},
+	})
+	return nil
+}
+
+// RotateClientSecret - tạo secret mới, secret cũ và access token đã cấp hết hiệu lực ngay (admin only)
+func (u *oauthClientUsecase) RotateClientSecret(ctx context.Context, id uint) (*domain.OAuthClientResponse, error) {
+	client, err := u.getClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if client.Public {
+		return nil, errors.New("public clients do not have a secret")
+	}
+
+	secret, err := generateClientSecret()
+	if err != nil {
+		return nil, err
+	}
+	client.SecretHash = u.tokenHashService.Hash(secret)
+	if err := u.clientRepo.Update(ctx, client); err != nil {
+		return nil, err
+	}
+	if err := u.revoker.revokeClient(ctx, client.ClientID); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionOAuthClientRotate,
+		Metadata: map[string]interface{}{"client_id": client.ClientID},
+	})
+
+	resp := client.ToResponse()
+	resp.ClientSecret = secret
+	return resp, nil
+}
+
+// getClient - lấy client, không có -> lỗi
+func (u *oauthClientUsecase) getClient(ctx context.Context, id uint) (*domain.OAuthClient, error) {
+	client, err := u.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.New("oauth client not found")
+	}
+	return client, nil
+}
+
+// validateClient - kiểm tra grant type, redirect URI và scope khớp nhau
+//   - client_credentials chỉ dành cho confidential client, scope là tên permission
+//   - authorization_code cần redirect URI, scope là scope OIDC
+func (u *oauthClientUsecase) validateClient(ctx context.Context, client *domain.OAuthClient) error {
+	authorizationCode := client.AllowsGrant(domain.GrantTypeAuthorizationCode)
+	clientCredentials := client.AllowsGrant(domain.GrantTypeClientCredentials)
+
+	// 1. Grant type
+	if clientCredentials && client.Public {
+		return errors.New("public clients cannot use the client_credentials grant")
+	}
+	if authorizationCode && client.RedirectURIs == "" {
+		return errors.New("redirect_uris are required for the authorization_code grant")
+	}
+
+	// 2. Scope OIDC cần authorization_code, còn lại phải là permission và cần client_credentials
+	var permissions []string
+	for _, scope := range client.ScopeList() {
+		if utils.Contains(domain.OIDCScopes, scope) {
+			if !authorizationCode {
+				return fmt.Errorf("scope %s requires the authorization_code grant", scope)
+			}
+			continue
+		}
+		if !clientCredentials {
+			return fmt.Errorf("scope %s requires the client_credentials grant", scope)
+		}
+		permissions = append(permissions, scope)
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	found, err := u.roleRepo.GetPermissionsByNames(ctx, permissions)
+	if err != nil {
+		return err
+	}
+	if len(found) != len(permissions) {
+		return errors.New("scopes contain unknown permissions")
+	}
+	return nil
+}
+
+// checkGrantedScopes - permission scope mới cấp cho client_credentials phải nằm trong quyền của admin đang thao tác
+// Tránh admin chỉ có roles:write / oauth_clients:write tự tạo client mang quyền cao hơn mình
+// Scope client đã có từ trước (previous) không kiểm tra lại, trả về các scope mới được cấp
+func (u *oauthClientUsecase) checkGrantedScopes(ctx context.Context, actorID uint, client *domain.OAuthClient, previous []string) ([]string, error) {
+	// 1. Scope permission mới so với trước
+	var granted []string
+	for _, scope := range client.ScopeList() {
+		if utils.Contains(domain.OIDCScopes, scope) || utils.Contains(previous, scope) {
+			continue
+		}
+		granted = append(granted, scope)
+	}
+	if len(granted) == 0 {
+		return nil, nil
+	}
+
+	// 2. Admin phải đang có các permission đó
+	held, err := u.roleRepo.GetUserPermissions(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range granted {
+		if !utils.Contains(held, scope) {
+			return nil, fmt.Errorf("cannot grant scope %s that you do not hold", scope)
+		}
+	}
+	return granted, nil
+}
+
+// recordGrant - ghi audit cho scope permission mới cấp cho client
+func (u *oauthClientUsecase) recordGrant(ctx context.Context, client *domain.OAuthClient, granted []string) {
+	if len(granted) == 0 {
+		return
+	}
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionOAuthClientGrant,
+		Metadata: map[string]interface{}{"client_id": client.ClientID, "scopes": granted},
+	})
+}
+
+// normalizeRedirectURIs - redirect URI phải là URL tuyệt đối, không có fragment (RFC 6749 3.1.2)
+// http chỉ cho phép với loopback (app chạy trên máy dev)
+func normalizeRedirectURIs(uris []string) ([]string, error) {
+	var result []string
+	for _, raw := range uris {
+		raw = strings.TrimSpace(raw)
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Host == "" || parsed.Fragment != "" || strings.ContainsAny(raw, "\n\r") {
+			return nil, errors.New("redirect uri must be an absolute url without fragment")
+		}
+		switch parsed.Scheme {
+		case "https":
+		case "http":
+			if !isLoopbackHost(parsed.Hostname()) {
+				return nil, errors.New("redirect uri must use https (http is only allowed for localhost)")
+			}
+		default:
+			return nil, errors.New("redirect uri must use https")
+		}
+		if !utils.Contains(result, raw) {
+			result = append(result, raw)
+		}
+	}
+	return result, nil
+}
+
+// isLoopbackHost - localhost, 127.0.0.0/8 hoặc ::1
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// joinFields - lưu danh sách scope / grant type (bỏ trùng, phân cách bằng khoảng trắng)
+func joinFields(values []string) string {
+	var unique []string
+	for _, value := range values {
+		if !utils.Contains(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+	return strings.Join(unique, " ")
+}
+
+// generateClientSecret - secret ngẫu nhiên 32 bytes
+func generateClientSecret() (string, error) {
+	secret, err := oauth.RandomString(32)
+	if err != nil {
+		return "", err
+	}
+	return oauthClientSecretPrefix + secret, nil
+}
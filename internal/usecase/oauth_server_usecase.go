@@ -0,0 +1,850 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/jwt"
+	"github.com/me/go-gin-auth/pkg/oauth"
+	"github.com/me/go-gin-auth/pkg/revocation"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+	"github.com/me/go-gin-auth/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Mã lỗi OAuth 2.0 (RFC 6749 4.1.2.1, 5.2 và RFC 6750 3.1)
+const (
+	OAuthErrInvalidRequest          = "invalid_request"
+	OAuthErrInvalidClient           = "invalid_client"
+	OAuthErrInvalidGrant            = "invalid_grant"
+	OAuthErrUnauthorizedClient      = "unauthorized_client"
+	OAuthErrUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrInvalidScope            = "invalid_scope"
+	OAuthErrAccessDenied            = "access_denied"
+	OAuthErrServerError             = "server_error"
+	OAuthErrInvalidToken            = "invalid_token"
+	OAuthErrInsufficientScope       = "insufficient_scope"
+	OAuthErrUnsupportedTokenType    = "unsupported_token_type"
+)
+
+// Độ dài PKCE code verifier hợp lệ (RFC 7636 4.1)
+const (
+	pkceVerifierMinLen = 43
+	pkceVerifierMaxLen = 128
+)
+
+// OAuthError - lỗi theo chuẩn OAuth, handler trả nguyên code/description cho client
+type OAuthError struct {
+	Code        string
+	Description string
+	Status      int    // HTTP status khi trả JSON
+	RedirectTo  string // Khác rỗng -> chuyển trình duyệt về client kèm lỗi thay vì hiển thị
+}
+
+// Error - implement error interface
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// oauthServerUsecase - implement OAuthServerUsecase interface
+type oauthServerUsecase struct {
+	clientRepo       repository.OAuthClientRepository
+	codeRepo         repository.OAuthCodeRepository
+	userRepo         repository.UserRepository
+	tokenRepo        repository.TokenRepository
+	jwtService       jwt.Service
+	tokenHashService tokenhash.Service
+	revoker          *accessTokenRevoker
+	audit            *auditor
+	logger           *zap.Logger
+	issuer           string
+	codeTTL          time.Duration
+	accessTTL        time.Duration
+}
+
+// NewOAuthServerUsecase - tạo authorization server usecase mới
+// issuer là URL gốc của service, dùng cho claim iss và các endpoint trong discovery
+func NewOAuthServerUsecase(
+	clientRepo repository.OAuthClientRepository,
+	codeRepo repository.OAuthCodeRepository,
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	auditRepo repository.AuditLogRepository,
+	jwtService jwt.Service,
+	revocationStore revocation.Store,
+	tokenHashService tokenhash.Service,
+	logger *zap.Logger,
+	issuer string,
+	codeTTL time.Duration,
+	accessTTL time.Duration,
+) OAuthServerUsecase {
+	return &oauthServerUsecase{
+		clientRepo:       clientRepo,
+		codeRepo:         codeRepo,
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		jwtService:       jwtService,
+		tokenHashService: tokenHashService,
+		revoker:          &accessTokenRevoker{store: revocationStore, accessTTL: accessTTL},
+		audit:            &auditor{repo: auditRepo, logger: logger},
+		logger:           logger,
+		issuer:           strings.TrimSuffix(issuer, "/"),
+		codeTTL:          codeTTL,
+		accessTTL:        accessTTL,
+	}
+}
+
+// ValidateAuthorize - kiểm tra authorization request trước khi chuyển user sang trang consent
+func (u *oauthServerUsecase) ValidateAuthorize(ctx context.Context, req *domain.AuthorizeRequest) error {
+	_, _, err := u.validateAuthorize(ctx, req)
+	return err
+}
+
+// GetConsent - thông tin client và scope để hiển thị trang consent
+func (u *oauthServerUsecase) GetConsent(ctx context.Context, userID uint, req *domain.AuthorizeRequest) (*domain.OAuthConsentInfoResponse, error) {
+	// 1. Kiểm tra request
+	client, scopes, err := u.validateAuthorize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. User đã đồng ý các scope này trước đó chưa
+	consent, err := u.clientRepo.GetConsent(ctx, userID, client.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OAuthConsentInfoResponse{
+		ClientID:     client.ClientID,
+		ClientName:   client.Name,
+		Scopes:       scopes,
+		ConsentGiven: consent != nil && consent.Covers(scopes),
+	}, nil
+}
+
+// Consent - user đồng ý (cấp authorization code) hoặc từ chối, trả về URL chuyển trình duyệt về client
+func (u *oauthServerUsecase) Consent(ctx context.Context, userID uint, req *domain.OAuthConsentRequest) (*domain.OAuthConsentResponse, error) {
+	// 1. Kiểm tra request
+	client, scopes, err := u.validateAuthorize(ctx, &req.AuthorizeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. User từ chối
+	if !req.Approve {
+		u.audit.record(ctx, domain.AuditEvent{
+			TargetID: &userID,
+			Action:   domain.AuditActionOAuthConsent,
+			Outcome:  domain.AuditOutcomeFailure,
+			Metadata: map[string]interface{}{"client_id": client.ClientID, "scope": strings.Join(scopes, " "), "reason": "denied"},
+		})
+		return &domain.OAuthConsentResponse{
+			RedirectTo: u.redirectURL(req.RedirectURI, url.Values{
+				"error":             {OAuthErrAccessDenied},
+				"error_description": {"the user denied the request"},
+				"state":             {req.State},
+				"iss":               {u.issuer},
+			}),
+		}, nil
+	}
+
+	// 3. User phải còn hoạt động
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	now := time.Now()
+	if user == nil || !user.IsActive(now) {
+		return nil, errors.New("user account is not active")
+	}
+
+	// 4. Lưu consent (gộp với scope đã đồng ý trước đó)
+	granted := scopes
+	existing, err := u.clientRepo.GetConsent(ctx, userID, client.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		granted = mergeScopes(strings.Fields(existing.Scope), scopes)
+	}
+	if err := u.clientRepo.SaveConsent(ctx, &domain.OAuthConsent{
+		UserID:   userID,
+		ClientID: client.ClientID,
+		Scope:    strings.Join(granted, " "),
+	}); err != nil {
+		return nil, err
+	}
+
+	// 5. Tạo authorization code, chỉ lưu digest
+	code, err := oauth.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.codeRepo.Create(ctx, &domain.OAuthAuthorizationCode{
+		CodeHash:      u.tokenHashService.Hash(code),
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scope:         strings.Join(scopes, " "),
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		AuthTime:      now,
+		ExpiresAt:     now.Add(u.codeTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		TargetID: &userID,
+		Action:   domain.AuditActionOAuthConsent,
+		Metadata: map[string]interface{}{"client_id": client.ClientID, "scope": strings.Join(scopes, " ")},
+	})
+
+	// 6. Chuyển về client kèm code, state và issuer (RFC 9207 chống mix-up)
+	return &domain.OAuthConsentResponse{
+		RedirectTo: u.redirectURL(req.RedirectURI, url.Values{
+			"code":  {code},
+			"state": {req.State},
+			"iss":   {u.issuer},
+		}),
+	}, nil
+}
+
+// Token - token endpoint: xác thực client rồi xử lý grant
+func (u *oauthServerUsecase) Token(ctx context.Context, req *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	// 1. Xác thực client
+	client, err := u.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Xử lý theo grant type
+	switch req.GrantType {
+	case domain.GrantTypeAuthorizationCode, domain.GrantTypeClientCredentials:
+		if !client.AllowsGrant(req.GrantType) {
+			return nil, &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "client is not allowed to use this grant type", Status: http.StatusBadRequest}
+		}
+		if req.GrantType == domain.GrantTypeClientCredentials {
+			return u.clientCredentials(ctx, client, req)
+		}
+		return u.exchangeAuthorizationCode(ctx, client, req)
+	case "":
+		return nil, &OAuthError{Code: OAuthErrInvalidRequest, Description: "grant_type is required", Status: http.StatusBadRequest}
+	default:
+		return nil, &OAuthError{Code: OAuthErrUnsupportedGrantType, Status: http.StatusBadRequest}
+	}
+}
+
+// UserInfo - claims của user sở hữu access token (OpenID Connect Core 5.3)
+func (u *oauthServerUsecase) UserInfo(ctx context.Context, accessToken string) (map[string]interface{}, error) {
+	// 1. Verify access token, chỉ nhận token user cấp cho OAuth client
+	claims, err := u.jwtService.ValidateAccessToken(accessToken)
+	if err != nil || claims.ClientID == "" || claims.IsService() {
+		return nil, &OAuthError{Code: OAuthErrInvalidToken, Description: "invalid or expired access token", Status: http.StatusUnauthorized}
+	}
+	scopes := strings.Fields(claims.Scope)
+	if !utils.Contains(scopes, domain.OIDCScopeOpenID) {
+		return nil, &OAuthError{Code: OAuthErrInsufficientScope, Description: "the openid scope is required", Status: http.StatusForbidden}
+	}
+
+	// 2. Token chưa bị thu hồi
+	revoked, err := u.revoker.isRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, &OAuthError{Code: OAuthErrInvalidToken, Description: "access token has been revoked", Status: http.StatusUnauthorized}
+	}
+
+	// 3. Lấy user
+	user, err := u.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || !user.IsActive(time.Now()) {
+		return nil, &OAuthError{Code: OAuthErrInvalidToken, Description: "user account is not active", Status: http.StatusUnauthorized}
+	}
+
+	// 4. Claims theo scope
+	info := map[string]interface{}{"sub": strconv.FormatUint(uint64(user.ID), 10)}
+	if utils.Contains(scopes, domain.OIDCScopeProfile) {
+		info["name"] = user.FullName
+	}
+	if utils.Contains(scopes, domain.OIDCScopeEmail) {
+		info["email"] = user.Email
+		info["email_verified"] = user.EmailVerifiedAt != nil
+	}
+	return info, nil
+}
+
+// Introspect - trạng thái của access / refresh token (RFC 7662)
+// Client chỉ xem được token cấp cho chính nó, token khác cần scope tokens:introspect
+func (u *oauthServerUsecase) Introspect(ctx context.Context, req *domain.OAuthTokenActionRequest) (*domain.IntrospectionResponse, error) {
+	// 1. Xác thực client
+	client, err := u.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if req.Token == "" {
+		return nil, &OAuthError{Code: OAuthErrInvalidRequest, Description: "token is required", Status: http.StatusBadRequest}
+	}
+
+	// 2. Tìm token theo thứ tự gợi ý
+	access, refresh, err := u.lookupToken(ctx, req.Token, req.TokenTypeHint)
+	if err != nil {
+		return nil, err
+	}
+	inactive := &domain.IntrospectionResponse{Active: false}
+
+	// 3. Access token
+	if access != nil {
+		if !u.canAccessToken(client, access.ClientID, domain.PermissionTokensIntrospect) {
+			return inactive, nil
+		}
+		active, user, err := u.accessTokenActive(ctx, access)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			return inactive, nil
+		}
+
+		resp := &domain.IntrospectionResponse{
+			Active:      true,
+			Scope:       access.Scope,
+			ClientID:    access.ClientID,
+			TokenType:   "Bearer",
+			Iss:         access.Issuer,
+			Aud:         access.Audience,
+			Jti:         access.ID,
+			SubjectType: jwt.SubjectTypeUser,
+			SessionID:   access.SessionID,
+		}
+		if access.ExpiresAt != nil {
+			resp.Exp = access.ExpiresAt.Unix()
+		}
+		if access.IssuedAt != nil {
+			resp.Iat = access.IssuedAt.Unix()
+		}
+		if access.NotBefore != nil {
+			resp.Nbf = access.NotBefore.Unix()
+		}
+		if access.IsService() {
+			resp.SubjectType = jwt.SubjectTypeService
+		} else {
+			resp.Sub = strconv.FormatUint(uint64(user.ID), 10)
+			resp.Username = user.Email
+		}
+		return resp, nil
+	}
+
+	// 4. Refresh token (session của app, không cấp cho OAuth client nào)
+	if refresh != nil {
+		if !u.canAccessToken(client, "", domain.PermissionTokensIntrospect) {
+			return inactive, nil
+		}
+		now := time.Now()
+		if refresh.Revoked || refresh.RotatedAt != nil || refresh.ExpiresAt.Before(now) {
+			return inactive, nil
+		}
+		user, err := u.userRepo.GetByID(ctx, refresh.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil || !user.IsActive(now) {
+			return inactive, nil
+		}
+
+		return &domain.IntrospectionResponse{
+			Active:      true,
+			Username:    user.Email,
+			Exp:         refresh.ExpiresAt.Unix(),
+			Iat:         refresh.CreatedAt.Unix(),
+			Sub:         strconv.FormatUint(uint64(user.ID), 10),
+			SubjectType: jwt.SubjectTypeUser,
+			SessionID:   refresh.FamilyID,
+		}, nil
+	}
+
+	return inactive, nil
+}
+
+// Revoke - vô hiệu hóa token (RFC 7009), token không hợp lệ hoặc đã hết hạn vẫn trả về thành công
+// Access token bị thu hồi ngay theo jti; refresh token hoặc access token gắn session -> revoke cả session (refresh token family)
+func (u *oauthServerUsecase) Revoke(ctx context.Context, req *domain.OAuthTokenActionRequest) error {
+	// 1. Xác thực client
+	client, err := u.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return err
+	}
+	if req.Token == "" {
+		return &OAuthError{Code: OAuthErrInvalidRequest, Description: "token is required", Status: http.StatusBadRequest}
+	}
+
+	// 2. Tìm token theo thứ tự gợi ý
+	access, refresh, err := u.lookupToken(ctx, req.Token, req.TokenTypeHint)
+	if err != nil {
+		return err
+	}
+
+	// 3. Xác định session cần revoke
+	var (
+		userID    uint
+		sessionID string
+		tokenType string
+	)
+	switch {
+	case access != nil:
+		if !u.canAccessToken(client, access.ClientID, domain.PermissionTokensRevoke) {
+			return &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "client is not allowed to revoke this token", Status: http.StatusBadRequest}
+		}
+		if access.ID == "" && access.SessionID == "" {
+			// Token cấp trước khi có jti, không gắn session -> chỉ hết hiệu lực khi hết hạn
+			return &OAuthError{Code: OAuthErrUnsupportedTokenType, Description: "this access token cannot be revoked, it expires on its own", Status: http.StatusBadRequest}
+		}
+		if access.ExpiresAt != nil {
+			if err := u.revoker.revokeToken(ctx, access.ID, access.ExpiresAt.Time); err != nil {
+				return err
+			}
+		}
+		userID, sessionID, tokenType = access.UserID, access.SessionID, domain.TokenTypeHintAccessToken
+	case refresh != nil:
+		if !u.canAccessToken(client, "", domain.PermissionTokensRevoke) {
+			return &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "client is not allowed to revoke this token", Status: http.StatusBadRequest}
+		}
+		userID, sessionID, tokenType = refresh.UserID, refresh.FamilyID, domain.TokenTypeHintRefreshToken
+	default:
+		return nil
+	}
+
+	// 4. Revoke cả family và access token đã cấp cho session (RFC 7009 2.1)
+	if sessionID != "" {
+		if err := u.tokenRepo.RevokeFamily(ctx, sessionID); err != nil {
+			return err
+		}
+		if err := u.revoker.revokeSession(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+
+	metadata := map[string]interface{}{"client_id": client.ClientID, "session_id": sessionID, "token_type": tokenType}
+	if access != nil {
+		metadata["jti"] = access.ID
+	}
+	event := domain.AuditEvent{Action: domain.AuditActionTokenRevoke, Metadata: metadata}
+	if userID != 0 {
+		event.TargetID = &userID
+	}
+	u.audit.record(ctx, event)
+	return nil
+}
+
+// Discovery - metadata ở /.well-known/openid-configuration
+// Key HS256 không ký được ID token nên không quảng bá scope openid và thuật toán ID token
+func (u *oauthServerUsecase) Discovery() *domain.OpenIDConfiguration {
+	scopes := domain.OIDCScopes
+	idTokenAlgorithms := []string{}
+	if u.canIssueIDTokens() {
+		idTokenAlgorithms = []string{u.jwtService.SigningAlgorithm()}
+	} else {
+		scopes = nil
+		for _, scope := range domain.OIDCScopes {
+			if scope != domain.OIDCScopeOpenID {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	return &domain.OpenIDConfiguration{
+		Issuer:                            u.issuer,
+		AuthorizationEndpoint:             u.issuer + "/oauth/authorize",
+		TokenEndpoint:                     u.issuer + "/oauth/token",
+		UserInfoEndpoint:                  u.issuer + "/oauth/userinfo",
+		IntrospectionEndpoint:             u.issuer + "/oauth/introspect",
+		RevocationEndpoint:                u.issuer + "/oauth/revoke",
+		JWKSURI:                           u.issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   scopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{domain.GrantTypeAuthorizationCode, domain.GrantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  idTokenAlgorithms,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "name", "email", "email_verified"},
+	}
+}
+
+// canIssueIDTokens - ID token phải verify được qua JWKS nên cần key bất đối xứng
+func (u *oauthServerUsecase) canIssueIDTokens() bool {
+	return u.jwtService.SigningAlgorithm() != jwt.AlgorithmHS256
+}
+
+// validateAuthorize - kiểm tra client, redirect URI, response type, PKCE và scope
+// Client hoặc redirect URI sai -> lỗi hiển thị cho user (không được redirect tới URI chưa đăng ký),
+// các lỗi còn lại chuyển về client qua redirect URI
+func (u *oauthServerUsecase) validateAuthorize(ctx context.Context, req *domain.AuthorizeRequest) (*domain.OAuthClient, []string, error) {
+	// 1. Client
+	client, err := u.clientRepo.GetByClientID(ctx, req.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if client == nil {
+		return nil, nil, &OAuthError{Code: OAuthErrInvalidRequest, Description: "unknown client_id", Status: http.StatusBadRequest}
+	}
+
+	// 2. Redirect URI phải khớp chính xác 1 URI đã đăng ký
+	if req.RedirectURI == "" || !utils.Contains(client.RedirectURIList(), req.RedirectURI) {
+		return nil, nil, &OAuthError{Code: OAuthErrInvalidRequest, Description: "redirect_uri is not registered for this client", Status: http.StatusBadRequest}
+	}
+
+	// 3. Chỉ hỗ trợ authorization code
+	if req.ResponseType != "code" {
+		return nil, nil, u.redirectError(req, OAuthErrUnsupportedResponseType, "only response_type=code is supported")
+	}
+	if !client.AllowsGrant(domain.GrantTypeAuthorizationCode) {
+		return nil, nil, u.redirectError(req, OAuthErrUnauthorizedClient, "client is not allowed to use the authorization_code grant")
+	}
+
+	// 4. PKCE S256 bắt buộc với mọi client
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != "S256" {
+		return nil, nil, u.redirectError(req, OAuthErrInvalidRequest, "code_challenge with code_challenge_method=S256 is required")
+	}
+	if len(req.CodeChallenge) != 43 {
+		return nil, nil, u.redirectError(req, OAuthErrInvalidRequest, "invalid code_challenge")
+	}
+
+	// 5. Scope phải là scope OIDC client được phép (scope permission chỉ dùng cho client_credentials)
+	scopes := mergeScopes(nil, strings.Fields(req.Scope))
+	if len(scopes) == 0 {
+		return nil, nil, u.redirectError(req, OAuthErrInvalidScope, "scope is required")
+	}
+	allowed := client.ScopeList()
+	for _, scope := range scopes {
+		if !utils.Contains(allowed, scope) || !utils.Contains(domain.OIDCScopes, scope) {
+			return nil, nil, u.redirectError(req, OAuthErrInvalidScope, "scope "+scope+" is not allowed for this client")
+		}
+	}
+
+	// 6. Key HS256 không ký được ID token -> từ chối openid ngay, tránh user đồng ý xong mới lỗi ở bước đổi code
+	if utils.Contains(scopes, domain.OIDCScopeOpenID) && !u.canIssueIDTokens() {
+		return nil, nil, u.redirectError(req, OAuthErrInvalidScope, "the openid scope is unavailable: the server signs tokens with a symmetric key")
+	}
+
+	return client, scopes, nil
+}
+
+// authenticateClient - client_secret_basic / client_secret_post, public client chỉ gửi client_id
+func (u *oauthServerUsecase) authenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	invalidClient := &OAuthError{Code: OAuthErrInvalidClient, Description: "client authentication failed", Status: http.StatusUnauthorized}
+
+	if clientID == "" {
+		return nil, invalidClient
+	}
+	client, err := u.clientRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, invalidClient
+	}
+
+	if !client.Public {
+		if clientSecret == "" || subtle.ConstantTimeCompare([]byte(u.tokenHashService.Hash(clientSecret)), []byte(client.SecretHash)) != 1 {
+			return nil, invalidClient
+		}
+	}
+	return client, nil
+}
+
+// exchangeAuthorizationCode - đổi authorization code lấy access token (+ ID token nếu có scope openid)
+func (u *oauthServerUsecase) exchangeAuthorizationCode(ctx context.Context, client *domain.OAuthClient, req *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	invalidGrant := func(description string) error {
+		return &OAuthError{Code: OAuthErrInvalidGrant, Description: description, Status: http.StatusBadRequest}
+	}
+
+	if req.Code == "" || req.CodeVerifier == "" {
+		return nil, &OAuthError{Code: OAuthErrInvalidRequest, Description: "code and code_verifier are required", Status: http.StatusBadRequest}
+	}
+
+	// 1. Lấy code (chỉ đánh dấu đã dùng sau khi tạo xong token, lỗi ở giữa không làm mất code)
+	code, err := u.codeRepo.GetByHash(ctx, u.tokenHashService.Hash(req.Code))
+	if err != nil {
+		return nil, err
+	}
+	if code == nil {
+		return nil, invalidGrant("invalid authorization code")
+	}
+	if code.Used {
+		u.logger.Warn("Authorization code replayed", zap.String("client_id", client.ClientID), zap.Uint("user_id", code.UserID))
+		return nil, invalidGrant("authorization code has already been used")
+	}
+
+	// 2. Code phải còn hạn, cấp cho đúng client và redirect URI
+	now := time.Now()
+	if code.ExpiresAt.Before(now) {
+		return nil, invalidGrant("authorization code has expired")
+	}
+	if code.ClientID != client.ClientID {
+		return nil, invalidGrant("authorization code was issued to another client")
+	}
+	if req.RedirectURI != code.RedirectURI {
+		return nil, invalidGrant("redirect_uri does not match the authorization request")
+	}
+
+	// 3. PKCE: BASE64URL(SHA256(code_verifier)) phải khớp code_challenge
+	if len(req.CodeVerifier) < pkceVerifierMinLen || len(req.CodeVerifier) > pkceVerifierMaxLen ||
+		subtle.ConstantTimeCompare([]byte(oauth.CodeChallengeS256(req.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, invalidGrant("invalid code_verifier")
+	}
+
+	// 4. User phải còn hoạt động
+	user, err := u.userRepo.GetByID(ctx, code.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || !user.IsActive(now) {
+		return nil, invalidGrant("user account is not active")
+	}
+
+	// 5. Access token cấp cho client (aud = client_id)
+	accessClaims := jwt.AccessClaims{
+		UserID:   user.ID,
+		Role:     user.Role,
+		ClientID: client.ClientID,
+		Scope:    code.Scope,
+	}
+	accessClaims.Issuer = u.issuer
+	accessClaims.Audience = []string{client.ClientID}
+	accessToken, err := u.jwtService.GenerateAccessToken(accessClaims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	resp := &domain.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(u.accessTTL.Seconds()),
+		Scope:       code.Scope,
+	}
+
+	// 6. ID token khi client yêu cầu scope openid
+	scopes := strings.Fields(code.Scope)
+	if utils.Contains(scopes, domain.OIDCScopeOpenID) {
+		claims := jwt.IDTokenClaims{Nonce: code.Nonce, AuthTime: code.AuthTime.Unix()}
+		claims.Issuer = u.issuer
+		claims.Subject = strconv.FormatUint(uint64(user.ID), 10)
+		claims.Audience = []string{client.ClientID}
+		if utils.Contains(scopes, domain.OIDCScopeProfile) {
+			claims.Name = user.FullName
+		}
+		if utils.Contains(scopes, domain.OIDCScopeEmail) {
+			verified := user.EmailVerifiedAt != nil
+			claims.Email = user.Email
+			claims.EmailVerified = &verified
+		}
+		if resp.IDToken, err = u.jwtService.GenerateIDToken(claims); err != nil {
+			return nil, fmt.Errorf("failed to generate id token: %w", err)
+		}
+	}
+
+	// 7. Đánh dấu code đã dùng, request khác đổi cùng code trước -> bỏ token vừa tạo
+	consumed, err := u.codeRepo.MarkUsed(ctx, code.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		u.logger.Warn("Authorization code replayed", zap.String("client_id", client.ClientID), zap.Uint("user_id", code.UserID))
+		return nil, invalidGrant("authorization code has already been used")
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		ActorID:  &user.ID,
+		TargetID: &user.ID,
+		Action:   domain.AuditActionOAuthCodeExchange,
+		Metadata: map[string]interface{}{"client_id": client.ClientID, "scope": code.Scope},
+	})
+	return resp, nil
+}
+
+// clientCredentials - access token cho service (RFC 6749 4.4), scope là permission của service
+// Không có refresh token: hết hạn thì service gọi lại token endpoint
+func (u *oauthServerUsecase) clientCredentials(ctx context.Context, client *domain.OAuthClient, req *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	// 1. Chỉ confidential client (public client không chứng minh được danh tính)
+	if client.Public {
+		return nil, &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "public clients cannot use the client_credentials grant", Status: http.StatusBadRequest}
+	}
+
+	// 2. Scope yêu cầu phải là permission client được phép, rỗng -> tất cả
+	var allowed []string
+	for _, scope := range client.ScopeList() {
+		if !utils.Contains(domain.OIDCScopes, scope) {
+			allowed = append(allowed, scope)
+		}
+	}
+	scopes := allowed
+	if requested := mergeScopes(nil, strings.Fields(req.Scope)); len(requested) > 0 {
+		for _, scope := range requested {
+			if !utils.Contains(allowed, scope) {
+				return nil, &OAuthError{Code: OAuthErrInvalidScope, Description: "scope " + scope + " is not allowed for this client", Status: http.StatusBadRequest}
+			}
+		}
+		scopes = requested
+	}
+	scope := strings.Join(scopes, " ")
+
+	// 3. Access token với chủ thể là service
+	claims := jwt.AccessClaims{
+		SubjectType: jwt.SubjectTypeService,
+		ClientID:    client.ClientID,
+		Scope:       scope,
+	}
+	claims.Issuer = u.issuer
+	accessToken, err := u.jwtService.GenerateAccessToken(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionClientCredentials,
+		Metadata: map[string]interface{}{"client_id": client.ClientID, "scope": scope},
+	})
+
+	return &domain.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(u.accessTTL.Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+// lookupToken - tìm token là access token (JWT hợp lệ) hay refresh token (có trong database)
+// Không thấy -> cả 2 đều nil; hint chỉ quyết định thứ tự thử (RFC 7009 2.1)
+func (u *oauthServerUsecase) lookupToken(ctx context.Context, token, hint string) (*jwt.AccessClaims, *domain.RefreshToken, error) {
+	findAccess := func() *jwt.AccessClaims {
+		claims, err := u.jwtService.ValidateAccessToken(token)
+		if err != nil {
+			return nil
+		}
+		return claims
+	}
+	findRefresh := func() (*domain.RefreshToken, error) {
+		if _, err := u.jwtService.ValidateRefreshToken(token); err != nil {
+			return nil, nil
+		}
+		return findRefreshToken(ctx, u.tokenRepo, u.tokenHashService, token)
+	}
+
+	if hint == domain.TokenTypeHintRefreshToken {
+		refresh, err := findRefresh()
+		if err != nil || refresh != nil {
+			return nil, refresh, err
+		}
+		return findAccess(), nil, nil
+	}
+
+	if access := findAccess(); access != nil {
+		return access, nil, nil
+	}
+	refresh, err := findRefresh()
+	return nil, refresh, err
+}
+
+// canAccessToken - client được introspect / revoke token: token cấp cho chính nó hoặc có scope permission
+func (u *oauthServerUsecase) canAccessToken(client *domain.OAuthClient, tokenClientID, permission string) bool {
+	if tokenClientID != "" && tokenClientID == client.ClientID {
+		return true
+	}
+	return utils.Contains(client.ScopeList(), permission)
+}
+
+// accessTokenActive - access token còn hiệu lực: chưa bị thu hồi, session chưa bị revoke, user còn hoạt động, client service còn tồn tại
+// Trả về user sở hữu token (nil với token của service)
+func (u *oauthServerUsecase) accessTokenActive(ctx context.Context, claims *jwt.AccessClaims) (bool, *domain.User, error) {
+	// 1. Thu hồi theo jti / mốc thu hồi của user
+	revoked, err := u.revoker.isRevoked(ctx, claims)
+	if err != nil || revoked {
+		return false, nil, err
+	}
+
+	// 2. Token của service: client chưa bị xóa
+	if claims.IsService() {
+		client, err := u.clientRepo.GetByClientID(ctx, claims.ClientID)
+		if err != nil {
+			return false, nil, err
+		}
+		return client != nil, nil, nil
+	}
+
+	// 3. Token gắn session: session chưa logout / bị revoke
+	if claims.SessionID != "" {
+		active, err := u.tokenRepo.IsFamilyActive(ctx, claims.SessionID)
+		if err != nil || !active {
+			return false, nil, err
+		}
+	}
+
+	// 4. User còn hoạt động
+	user, err := u.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || !user.IsActive(time.Now()) {
+		return false, nil, nil
+	}
+	return true, user, nil
+}
+
+// redirectError - lỗi chuyển về client qua redirect URI đã kiểm tra
+func (u *oauthServerUsecase) redirectError(req *domain.AuthorizeRequest, code, description string) error {
+	return &OAuthError{
+		Code:        code,
+		Description: description,
+		Status:      http.StatusBadRequest,
+		RedirectTo: u.redirectURL(req.RedirectURI, url.Values{
+			"error":             {code},
+			"error_description": {description},
+			"state":             {req.State},
+			"iss":               {u.issuer},
+		}),
+	}
+}
+
+// redirectURL - thêm tham số vào redirect URI (giữ query có sẵn, bỏ tham số rỗng)
+func (u *oauthServerUsecase) redirectURL(redirectURI string, params url.Values) string {
+	parsed, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+	query := parsed.Query()
+	for key, values := range params {
+		if len(values) > 0 && values[0] != "" {
+			query.Set(key, values[0])
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// mergeScopes - gộp scope, bỏ trùng, giữ thứ tự
+func mergeScopes(base, extra []string) []string {
+	merged := append([]string(nil), base...)
+	for _, scope := range extra {
+		if !utils.Contains(merged, scope) {
+			merged = append(merged, scope)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,466 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/mailer"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+	"github.com/me/go-gin-auth/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// invitationTTL - thời gian sống của lời mời tham gia organization
+const invitationTTL = 7 * 24 * time.Hour
+
+var (
+	slugPattern     = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	slugInvalidChar = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// organizationUsecase - implement OrganizationUsecase interface
+type organizationUsecase struct {
+	orgRepo          repository.OrganizationRepository
+	userRepo         repository.UserRepository
+	tokenHashService tokenhash.Service
+	notifier         *notifier
+}
+
+// NewOrganizationUsecase - tạo organization usecase mới
+func NewOrganizationUsecase(
+	orgRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	tokenHashService tokenhash.Service,
+	mailService mailer.Service,
+	logger *zap.Logger,
+) OrganizationUsecase {
+	return &organizationUsecase{
+		orgRepo:          orgRepo,
+		userRepo:         userRepo,
+		tokenHashService: tokenHashService,
+		notifier:         &notifier{mailer: mailService, logger: logger},
+	}
+}
+
+// Create - tạo organization, người tạo là owner
+func (u *organizationUsecase) Create(ctx context.Context, userID uint, req *domain.CreateOrganizationRequest) (*domain.OrganizationResponse, error) {
+	// 1. Slug do user chọn phải hợp lệ và chưa dùng, slug tự sinh thì thêm hậu tố nếu trùng
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if slug != "" {
+		if !slugPattern.MatchString(slug) {
+			return nil, errors.New("slug may only contain lowercase letters, digits and '-'")
+		}
+		existing, err := u.orgRepo.GetBySlug(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, errors.New("organization with this slug already exists")
+		}
+	} else {
+		var err error
+		if slug, err = u.generateSlug(ctx, req.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. Lưu organization + owner
+	org := &domain.Organization{
+		Name: strings.TrimSpace(req.Name),
+		Slug: slug,
+	}
+	if err := u.orgRepo.Create(ctx, org, userID); err != nil {
+		return nil, err
+	}
+
+	membership := domain.OrganizationMembership{Organization: *org, Role: domain.OrgRoleOwner}
+	return membership.ToResponse(), nil
+}
+
+// generateSlug - tạo slug từ tên organization
+func (u *organizationUsecase) generateSlug(ctx context.Context, name string) (string, error) {
+	base := strings.Trim(slugInvalidChar.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(base) > 40 {
+		base = strings.TrimRight(base[:40], "-")
+	}
+	if base == "" {
+		base = "org"
+	}
+
+	slug := base
+	for i := 0; i < 5; i++ {
+		existing, err := u.orgRepo.GetBySlug(ctx, slug)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return slug, nil
+		}
+
+		suffix, err := utils.GenerateRandomString(3)
+		if err != nil {
+			return "", err
+		}
+		slug = base + "-" + suffix
+	}
+
+	return "", errors.New("failed to generate a unique slug, please choose one")
+}
+
+// List - các organization của user
+func (u *organizationUsecase) List(ctx context.Context, userID uint) ([]domain.OrganizationResponse, error) {
+	memberships, err := u.orgRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.OrganizationResponse, len(memberships))
+	for i := range memberships {
+		result[i] = *memberships[i].ToResponse()
+	}
+	return result, nil
+}
+
+// Get - chi tiết organization (chỉ thành viên)
+func (u *organizationUsecase) Get(ctx context.Context, userID, orgID uint) (*domain.OrganizationResponse, error) {
+	member, err := u.requireMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := u.getOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	membership := domain.OrganizationMembership{Organization: *org, Role: member.Role}
+	return membership.ToResponse(), nil
+}
+
+// Update - đổi tên organization (owner/admin)
+func (u *organizationUsecase) Update(ctx context.Context, userID, orgID uint, req *domain.UpdateOrganizationRequest) (*domain.OrganizationResponse, error) {
+	// 1. Kiểm tra quyền
+	member, err := u.requireManager(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Cập nhật
+	org, err := u.getOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	org.Name = strings.TrimSpace(req.Name)
+	if err := u.orgRepo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+
+	membership := domain.OrganizationMembership{Organization: *org, Role: member.Role}
+	return membership.ToResponse(), nil
+}
+
+// Delete - xóa organization (chỉ owner)
+func (u *organizationUsecase) Delete(ctx context.Context, userID, orgID uint) error {
+	member, err := u.requireMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role != domain.OrgRoleOwner {
+		return errors.New("only owners can delete the organization")
+	}
+
+	return u.orgRepo.Delete(ctx, orgID)
+}
+
+// ListMembers - danh sách user trong organization (owner/admin), giống ListUsers nhưng giới hạn trong organization
+func (u *organizationUsecase) ListMembers(ctx context.Context, userID, orgID uint, req *domain.ListUsersRequest) (*domain.PaginatedMembersResponse, error) {
+	// 1. Kiểm tra quyền
+	if _, err := u.requireManager(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	// 2. Set defaults
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	// 3. Lấy danh sách từ repository
+	offset := (req.Page - 1) * req.Limit
+	users, total, err := u.orgRepo.ListMembers(ctx, orgID, req.Limit, offset, req.Search, req.Role, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Convert sang response format
+	members := make([]domain.MemberResponse, len(users))
+	for i := range users {
+		members[i] = *users[i].ToMemberResponse()
+	}
+
+	return &domain.PaginatedMembersResponse{
+		Members: members,
+		Pagination: domain.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+		},
+	}, nil
+}
+
+// UpdateMember - đổi role thành viên (owner/admin, chỉ owner được cấp/gỡ quyền owner)
+func (u *organizationUsecase) UpdateMember(ctx context.Context, actorID, orgID, targetID uint, req *domain.UpdateMemberRequest) error {
+	// 1. Kiểm tra quyền
+	actor, err := u.requireManager(ctx, orgID, actorID)
+	if err != nil {
+		return err
+	}
+	target, err := u.orgRepo.GetMember(ctx, orgID, targetID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return errors.New("member not found")
+	}
+	if (target.Role == domain.OrgRoleOwner || req.Role == domain.OrgRoleOwner) && actor.Role != domain.OrgRoleOwner {
+		return errors.New("only owners can grant or change the owner role")
+	}
+
+	// 2. Luôn còn ít nhất 1 owner
+	if target.Role == domain.OrgRoleOwner && req.Role != domain.OrgRoleOwner {
+		if err := u.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	return u.orgRepo.UpdateMemberRole(ctx, orgID, targetID, req.Role)
+}
+
+// RemoveMember - xóa thành viên (owner/admin) hoặc tự rời organization
+func (u *organizationUsecase) RemoveMember(ctx context.Context, actorID, orgID, targetID uint) error {
+	// 1. Kiểm tra quyền
+	actor, err := u.requireMember(ctx, orgID, actorID)
+	if err != nil {
+		return err
+	}
+	target, err := u.orgRepo.GetMember(ctx, orgID, targetID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return errors.New("member not found")
+	}
+	if actorID != targetID {
+		if !actor.IsManager() {
+			return errors.New("only owners and admins can remove members")
+		}
+		if target.Role == domain.OrgRoleOwner && actor.Role != domain.OrgRoleOwner {
+			return errors.New("only owners can remove an owner")
+		}
+	}
+
+	// 2. Luôn còn ít nhất 1 owner
+	if target.Role == domain.OrgRoleOwner {
+		if err := u.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	return u.orgRepo.RemoveMember(ctx, orgID, targetID)
+}
+
+// CreateInvitation - mời user qua email (owner/admin)
+func (u *organizationUsecase) CreateInvitation(ctx context.Context, actorID, orgID uint, req *domain.CreateInvitationRequest) (*domain.OrganizationInvitation, error) {
+	// 1. Kiểm tra quyền
+	actor, err := u.requireManager(ctx, orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Role == domain.OrgRoleOwner && actor.Role != domain.OrgRoleOwner {
+		return nil, errors.New("only owners can invite owners")
+	}
+
+	// 2. Email đã là thành viên thì không mời lại
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	invitee, err := u.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if invitee != nil {
+		existing, err := u.orgRepo.GetMember(ctx, orgID, invitee.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, errors.New("user is already a member of this organization")
+		}
+	}
+
+	// 3. Tạo lời mời (chỉ lưu digest của token)
+	token, err := utils.GenerateRandomString(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	invitation := &domain.OrganizationInvitation{
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           req.Role,
+		Token:          u.tokenHashService.Hash(token),
+		InvitedBy:      actorID,
+		ExpiresAt:      time.Now().Add(invitationTTL),
+	}
+	if err := u.orgRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	// 4. Gửi email mời (bất đồng bộ)
+	org, err := u.getOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	inviter, err := u.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if inviter != nil {
+		u.notifier.organizationInvitation(ctx, email, org, inviter, req.Role, token, invitationTTL)
+	}
+
+	return invitation, nil
+}
+
+// ListInvitations - lời mời đang chờ (owner/admin)
+func (u *organizationUsecase) ListInvitations(ctx context.Context, actorID, orgID uint) ([]domain.OrganizationInvitation, error) {
+	if _, err := u.requireManager(ctx, orgID, actorID); err != nil {
+		return nil, err
+	}
+	return u.orgRepo.ListPendingInvitations(ctx, orgID)
+}
+
+// RevokeInvitation - thu hồi lời mời (owner/admin)
+func (u *organizationUsecase) RevokeInvitation(ctx context.Context, actorID, orgID, invitationID uint) error {
+	if _, err := u.requireManager(ctx, orgID, actorID); err != nil {
+		return err
+	}
+
+	deleted, err := u.orgRepo.DeleteInvitation(ctx, orgID, invitationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("invitation not found")
+	}
+	return nil
+}
+
+// AcceptInvitation - user đang đăng nhập chấp nhận lời mời gửi tới email của mình
+func (u *organizationUsecase) AcceptInvitation(ctx context.Context, userID uint, token string) (*domain.OrganizationResponse, error) {
+	// 1. Tìm lời mời theo digest
+	var invitation *domain.OrganizationInvitation
+	for _, candidate := range u.tokenHashService.Candidates(token) {
+		found, err := u.orgRepo.GetInvitationByToken(ctx, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			invitation = found
+			break
+		}
+	}
+	if invitation == nil {
+		return nil, errors.New("invalid invitation token")
+	}
+
+	// 2. Kiểm tra trạng thái
+	if invitation.AcceptedAt != nil {
+		return nil, errors.New("invitation is already used")
+	}
+	if invitation.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("invitation is expired")
+	}
+
+	// 3. Lời mời chỉ dùng được bởi đúng email được mời
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, errors.New("this invitation was sent to a different email address")
+	}
+
+	// 4. Thêm thành viên
+	accepted, err := u.orgRepo.AcceptInvitation(ctx, invitation.ID, &domain.OrganizationMember{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         userID,
+		Role:           invitation.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, errors.New("invitation is already used")
+	}
+
+	return u.Get(ctx, userID, invitation.OrganizationID)
+}
+
+// requireMember - user phải là thành viên (không tiết lộ organization tồn tại với người ngoài)
+func (u *organizationUsecase) requireMember(ctx context.Context, orgID, userID uint) (*domain.OrganizationMember, error) {
+	member, err := u.orgRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.New("organization not found")
+	}
+	return member, nil
+}
+
+// requireManager - user phải là owner/admin
+func (u *organizationUsecase) requireManager(ctx context.Context, orgID, userID uint) (*domain.OrganizationMember, error) {
+	member, err := u.requireMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.IsManager() {
+		return nil, errors.New("only owners and admins can manage this organization")
+	}
+	return member, nil
+}
+
+// getOrganization - lấy organization, lỗi nếu không tồn tại
+func (u *organizationUsecase) getOrganization(ctx context.Context, orgID uint) (*domain.Organization, error) {
+	org, err := u.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, errors.New("organization not found")
+	}
+	return org, nil
+}
+
+// ensureAnotherOwner - không cho gỡ owner cuối cùng
+func (u *organizationUsecase) ensureAnotherOwner(ctx context.Context, orgID uint) error {
+	count, err := u.orgRepo.CountOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return errors.New("organization must have at least one owner")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/password"
+)
+
+// passwordHistory - chặn dùng lại password gần đây khi đổi/reset password
+type passwordHistory struct {
+	repo            repository.PasswordHistoryRepository
+	passwordService password.Service
+	size            int // Số password gần nhất không được dùng lại (<= 0 là tắt)
+}
+
+// check - trả về *password.PolicyError nếu newPassword trùng password hiện tại hoặc N password gần nhất
+func (h *passwordHistory) check(ctx context.Context, user *domain.User, newPassword string) error {
+	if h.size <= 0 {
+		return nil
+	}
+
+	// 1. Password hiện tại (user cũ có thể chưa có lịch sử)
+	if h.passwordService.CheckPassword(newPassword, user.PasswordHash) {
+		return h.reuseError()
+	}
+
+	// 2. Các password gần nhất
+	history, err := h.repo.ListRecent(ctx, user.ID, h.size)
+	if err != nil {
+		return err
+	}
+	for _, entry := range history {
+		if h.passwordService.CheckPassword(newPassword, entry.PasswordHash) {
+			return h.reuseError()
+		}
+	}
+
+	return nil
+}
+
+// record - lưu hash password vừa đặt vào lịch sử
+func (h *passwordHistory) record(ctx context.Context, userID uint, passwordHash string) error {
+	if h.size <= 0 {
+		return nil
+	}
+	return h.repo.Add(ctx, userID, passwordHash, h.size)
+}
+
+func (h *passwordHistory) reuseError() error {
+	return &password.PolicyError{Violations: []password.PolicyViolation{{
+		Rule:    password.RuleReused,
+		Message: fmt.Sprintf("password must not match any of your last %d passwords", h.size),
+	}}}
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+)
+
+// passwordResetIssuer - tạo token reset password và gửi email (dùng cho forgot password và admin yêu cầu reset)
+type passwordResetIssuer struct {
+	repo             repository.PasswordResetRepository
+	tokenHashService tokenhash.Service
+	notifier         *notifier
+}
+
+// issue - tạo token reset password cho user và gửi link qua email
+func (p *passwordResetIssuer) issue(ctx context.Context, user *domain.User) error {
+	// 1. Tạo reset token
+	resetToken := uuid.New().String()
+
+	// 2. Tạo password reset record (chỉ lưu digest)
+	passwordReset := &domain.PasswordReset{
+		Email:     user.Email,
+		Token:     p.tokenHashService.Hash(resetToken),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+
+	if err := p.repo.Create(ctx, passwordReset); err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	// 3. Gửi email chứa link reset (bất đồng bộ)
+	p.notifier.passwordReset(ctx, user, resetToken, passwordResetTTL)
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+	"github.com/me/go-gin-auth/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	personalAccessTokenBytes      = 32          // Entropy của token (trước khi encode)
+	personalAccessTokenPrefixLen  = 8           // Số ký tự ngẫu nhiên giữ lại để hiển thị
+	personalAccessTokenTouchEvery = time.Minute // Không ghi last_used mỗi request
+	maxPersonalAccessTokens       = 50          // Số token tối đa mỗi user
+)
+
+// personalAccessTokenUsecase - implement PersonalAccessTokenUsecase interface
+type personalAccessTokenUsecase struct {
+	patRepo          repository.PersonalAccessTokenRepository
+	userRepo         repository.UserRepository
+	roleRepo         repository.RoleRepository
+	audit            *auditor
+	tokenHashService tokenhash.Service
+	logger           *zap.Logger
+}
+
+// NewPersonalAccessTokenUsecase - tạo personal access token usecase mới
+func NewPersonalAccessTokenUsecase(
+	patRepo repository.PersonalAccessTokenRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	auditRepo repository.AuditLogRepository,
+	tokenHashService tokenhash.Service,
+	logger *zap.Logger,
+) PersonalAccessTokenUsecase {
+	return &personalAccessTokenUsecase{
+		patRepo:          patRepo,
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		audit:            &auditor{repo: auditRepo, logger: logger},
+		tokenHashService: tokenHashService,
+		logger:           logger,
+	}
+}
+
+// Create - tạo token mới, token gốc chỉ trả về 1 lần
+func (u *personalAccessTokenUsecase) Create(ctx context.Context, userID uint, req *domain.CreatePersonalAccessTokenRequest) (*domain.CreatePersonalAccessTokenResponse, error) {
+	// 1. Giới hạn số token
+	existing, err := u.patRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxPersonalAccessTokens {
+		return nil, fmt.Errorf("token limit reached (%d), revoke unused tokens first", maxPersonalAccessTokens)
+	}
+
+	// 2. Sinh token ngẫu nhiên có prefix
+	buf := make([]byte, personalAccessTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	raw := domain.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	// 3. Lưu digest + scope (bỏ trùng)
+	var scopes []string
+	for _, scope := range req.Scopes {
+		if !utils.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	token := &domain.PersonalAccessToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: u.tokenHashService.Hash(raw),
+		Prefix:    raw[:len(domain.PersonalAccessTokenPrefix)+personalAccessTokenPrefixLen],
+		Scopes:    strings.Join(scopes, ","),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := u.patRepo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		ActorID:  &userID,
+		TargetID: &userID,
+		Action:   domain.AuditActionPersonalTokenCreate,
+		Metadata: map[string]interface{}{"token_id": token.ID, "prefix": token.Prefix, "scopes": scopes},
+	})
+
+	return &domain.CreatePersonalAccessTokenResponse{
+		Token:                       raw,
+		PersonalAccessTokenResponse: token.ToResponse(),
+	}, nil
+}
+
+// List - lấy danh sách token của user
+func (u *personalAccessTokenUsecase) List(ctx context.Context, userID uint) ([]domain.PersonalAccessTokenResponse, error) {
+	tokens, err := u.patRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.PersonalAccessTokenResponse, len(tokens))
+	for i := range tokens {
+		result[i] = *tokens[i].ToResponse()
+	}
+	return result, nil
+}
+
+// Revoke - vô hiệu hóa token của user
+func (u *personalAccessTokenUsecase) Revoke(ctx context.Context, userID, tokenID uint) error {
+	revoked, err := u.patRepo.Revoke(ctx, userID, tokenID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("token not found")
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		ActorID:  &userID,
+		TargetID: &userID,
+		Action:   domain.AuditActionPersonalTokenRevoke,
+		Metadata: map[string]interface{}{"token_id": tokenID},
+	})
+	return nil
+}
+
+// Authenticate - xác thực token gốc, trả về token, user sở hữu và permission của user
+func (u *personalAccessTokenUsecase) Authenticate(ctx context.Context, raw, ipAddress string) (*domain.PersonalAccessTokenAuth, error) {
+	// 1. Tìm theo digest (thử cả digest trước khi đổi pepper)
+	var token *domain.PersonalAccessToken
+	for _, candidate := range u.tokenHashService.Candidates(raw) {
+		found, err := u.patRepo.GetByHash(ctx, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			token = found
+			break
+		}
+	}
+	if token == nil {
+		return nil, errors.New("invalid token")
+	}
+
+	// 2. Kiểm tra revoke / hết hạn
+	now := time.Now()
+	if !token.IsActive(now) {
+		return nil, errors.New("token is revoked or expired")
+	}
+
+	// 3. User phải còn active
+	user, err := u.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive(now) {
+		return nil, errors.New("user account is not active")
+	}
+	if user.PasswordResetRequired {
+		return nil, errors.New("password reset required")
+	}
+
+	// 4. Permission lấy theo role hiện tại (token không lưu permission)
+	permissions, err := u.roleRepo.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Ghi nhận lần dùng (tối đa 1 lần/phút, lỗi không chặn request)
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= personalAccessTokenTouchEvery || token.LastUsedIP != ipAddress {
+		if err := u.patRepo.TouchLastUsed(ctx, token.ID, ipAddress, now); err != nil {
+			u.logger.Warn("Failed to record personal access token usage", zap.Uint("token_id", token.ID), zap.Error(err))
+		}
+	}
+
+	return &domain.PersonalAccessTokenAuth{Token: token, User: user, Permissions: permissions}, nil
+}
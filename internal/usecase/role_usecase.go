@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/revocation"
+	"github.com/me/go-gin-auth/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// adminRoleName - role có sẵn luôn giữ quyền quản lý role (tránh khóa toàn bộ admin)
+const adminRoleName = "admin"
+
+// roleNamePattern - tên role: chữ thường, số, "_" và "-"
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// roleUsecase - implement RoleUsecase interface
+type roleUsecase struct {
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+	audit    *auditor
+	revoker  *accessTokenRevoker
+}
+
+// NewRoleUsecase - tạo role usecase mới
+func NewRoleUsecase(
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	revocationStore revocation.Store,
+	logger *zap.Logger,
+	accessTokenTTL time.Duration,
+) RoleUsecase {
+	return &roleUsecase{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		audit:    &auditor{repo: auditRepo, logger: logger},
+		revoker:  &accessTokenRevoker{store: revocationStore, accessTTL: accessTokenTTL},
+	}
+}
+
+// ListRoles - lấy tất cả role kèm permission
+func (u *roleUsecase) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	return u.roleRepo.List(ctx)
+}
+
+// ListPermissions - lấy tất cả permission
+func (u *roleUsecase) ListPermissions(ctx context.Context) ([]domain.Permission, error) {
+	return u.roleRepo.ListPermissions(ctx)
+}
+
+// CreateRole - tạo role mới
+func (u *roleUsecase) CreateRole(ctx context.Context, req *domain.CreateRoleRequest) (*domain.Role, error) {
+	// 1. Kiểm tra tên role
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if !roleNamePattern.MatchString(name) {
+		return nil, errors.New("role name may only contain lowercase letters, digits, '_' and '-'")
+	}
+	existing, err := u.roleRepo.GetByNames(ctx, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errors.New("role with this name already exists")
+	}
+
+	// 2. Permission phải tồn tại
+	permissions, err := u.resolvePermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Lưu role
+	role := &domain.Role{
+		Name:        name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	if err := u.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionRoleCreate,
+		Metadata: map[string]interface{}{"role": role.Name, "permissions": req.Permissions},
+	})
+
+	return role, nil
+}
+
+// UpdateRole - cập nhật mô tả và thay toàn bộ permission của role
+func (u *roleUsecase) UpdateRole(ctx context.Context, roleID uint, req *domain.UpdateRoleRequest) (*domain.Role, error) {
+	// 1. Lấy role
+	role, err := u.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, errors.New("role not found")
+	}
+
+	// 2. Permission phải tồn tại
+	permissions, err := u.resolvePermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Role admin phải giữ quyền quản lý role
+	if role.Name == adminRoleName && !utils.Contains(req.Permissions, domain.PermissionRolesWrite) {
+		return nil, fmt.Errorf("the %s role must keep the %s permission", adminRoleName, domain.PermissionRolesWrite)
+	}
+
+	// 4. Lưu thay đổi
+	role.Description = req.Description
+	role.Permissions = permissions
+	if err := u.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	// 5. Access token đã cấp mang permission cũ -> thu hồi của mọi user có role
+	userIDs, err := u.roleRepo.ListUserIDs(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.revokeUsers(ctx, userIDs); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionRoleUpdate,
+		Metadata: map[string]interface{}{"role": role.Name, "permissions": req.Permissions},
+	})
+	return role, nil
+}
+
+// DeleteRole - xóa role (không xóa được role có sẵn hoặc đang là role chính của user)
+func (u *roleUsecase) DeleteRole(ctx context.Context, roleID uint) error {
+	// 1. Lấy role
+	role, err := u.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return errors.New("role not found")
+	}
+	if role.IsSystem {
+		return errors.New("system roles cannot be deleted")
+	}
+
+	// 2. Không để user có role chính không tồn tại
+	count, err := u.roleRepo.CountPrimaryUsers(ctx, role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("role is the primary role of %d user(s), reassign them first", count)
+	}
+
+	// 3. Lấy user được gán thêm role trước khi xóa
+	userIDs, err := u.roleRepo.ListUserIDs(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	// 4. Xóa role (gỡ khỏi các user được gán thêm) và thu hồi access token mang permission cũ
+	if err := u.roleRepo.Delete(ctx, roleID); err != nil {
+		return err
+	}
+	if err := u.revokeUsers(ctx, userIDs); err != nil {
+		return err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionRoleDelete,
+		Metadata: map[string]interface{}{"role": role.Name},
+	})
+	return nil
+}
+
+// GetUserRoles - role và permission hiệu lực của user
+func (u *roleUsecase) GetUserRoles(ctx context.Context, userID uint) (*domain.UserRolesResponse, error) {
+	// 1. Lấy user
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// 2. Lấy role gán thêm
+	roles, err := u.roleRepo.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+
+	// 3. Permission gộp từ tất cả role
+	permissions, err := u.roleRepo.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserRolesResponse{
+		UserID:      user.ID,
+		PrimaryRole: user.Role,
+		Roles:       names,
+		Permissions: permissions,
+	}, nil
+}
+
+// SetUserRoles - thay toàn bộ role gán thêm của user
+// Access token đã cấp bị thu hồi, personal access token có hiệu lực ngay
+func (u *roleUsecase) SetUserRoles(ctx context.Context, userID uint, req *domain.SetUserRolesRequest) (*domain.UserRolesResponse, error) {
+	// 1. Kiểm tra user tồn tại
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// 2. Role phải tồn tại
+	roles, err := u.roleRepo.GetByNames(ctx, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make([]uint, len(roles))
+	known := make([]string, len(roles))
+	for i, role := range roles {
+		roleIDs[i] = role.ID
+		known[i] = role.Name
+	}
+	for _, name := range req.Roles {
+		if !utils.Contains(known, name) {
+			return nil, fmt.Errorf("unknown role: %s", name)
+		}
+	}
+
+	// 3. Lưu
+	if err := u.roleRepo.SetUserRoles(ctx, userID, roleIDs); err != nil {
+		return nil, err
+	}
+	if err := u.revoker.revokeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		TargetID: &userID,
+		Action:   domain.AuditActionUserRolesSet,
+		Metadata: map[string]interface{}{"roles": req.Roles},
+	})
+
+	return u.GetUserRoles(ctx, userID)
+}
+
+// revokeUsers - thu hồi access token đã cấp của các user
+func (u *roleUsecase) revokeUsers(ctx context.Context, userIDs []uint) error {
+	for _, userID := range userIDs {
+		if err := u.revoker.revokeUser(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolvePermissions - đổi tên permission sang entity, báo lỗi nếu có tên không tồn tại
+func (u *roleUsecase) resolvePermissions(ctx context.Context, names []string) ([]domain.Permission, error) {
+	permissions, err := u.roleRepo.GetPermissionsByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make([]string, len(permissions))
+	for i, permission := range permissions {
+		known[i] = permission.Name
+	}
+	for _, name := range names {
+		if !utils.Contains(known, name) {
+			return nil, fmt.Errorf("unknown permission: %s", name)
+		}
+	}
+
+	return permissions, nil
+}
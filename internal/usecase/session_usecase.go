@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/revocation"
+)
+
+// sessionUsecase - implement SessionUsecase interface
+type sessionUsecase struct {
+	tokenRepo repository.TokenRepository
+	revoker   *accessTokenRevoker
+}
+
+// NewSessionUsecase - tạo session usecase mới
+func NewSessionUsecase(tokenRepo repository.TokenRepository, revocationStore revocation.Store, accessTokenTTL time.Duration) SessionUsecase {
+	return &sessionUsecase{
+		tokenRepo: tokenRepo,
+		revoker:   &accessTokenRevoker{store: revocationStore, accessTTL: accessTokenTTL},
+	}
+}
+
+// ListSessions - lấy danh sách session đang đăng nhập của user
+func (u *sessionUsecase) ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]domain.SessionResponse, error) {
+	// 1. Lấy các refresh token còn hiệu lực (mỗi family 1 token)
+	tokens, err := u.tokenRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	// 2. Convert sang response format
+	sessions := make([]domain.SessionResponse, len(tokens))
+	for i, token := range tokens {
+		sessions[i] = *token.ToSessionResponse(currentSessionID)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession - đăng xuất 1 session (thiết bị) cụ thể
+func (u *sessionUsecase) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	revoked, err := u.tokenRepo.RevokeFamilyForUser(ctx, userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !revoked {
+		return errors.New("session not found")
+	}
+
+	// Access token của session cũng hết hiệu lực ngay
+	return u.revoker.revokeSession(ctx, sessionID)
+}
+
+// RevokeOtherSessions - đăng xuất tất cả session trừ session hiện tại
+func (u *sessionUsecase) RevokeOtherSessions(ctx context.Context, userID uint, currentSessionID string) error {
+	if currentSessionID == "" {
+		return errors.New("current session is unknown")
+	}
+
+	// 1. Các session đang active (để thu hồi access token của từng session sau khi revoke)
+	sessions, err := u.tokenRepo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	// 2. Revoke refresh token
+	if err := u.tokenRepo.RevokeAllForUserExcept(ctx, userID, currentSessionID); err != nil {
+		return fmt.Errorf("failed to revoke other sessions: %w", err)
+	}
+
+	// 3. Thu hồi access token theo session (mốc của user sẽ thu hồi cả session hiện tại)
+	for _, session := range sessions {
+		if session.FamilyID == currentSessionID {
+			continue
+		}
+		if err := u.revoker.revokeSession(ctx, session.FamilyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
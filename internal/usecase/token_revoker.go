@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/me/go-gin-auth/pkg/jwt"
+	"github.com/me/go-gin-auth/pkg/revocation"
+)
+
+// accessTokenRevoker - thu hồi access token ngay lập tức thay vì chờ hết JWT_ACCESS_TTL
+// Refresh token vẫn thu hồi trong database, revoker chỉ lo access token đã phát hành
+type accessTokenRevoker struct {
+	store     revocation.Store
+	accessTTL time.Duration // Mốc thu hồi của user chỉ cần giữ bằng thời gian sống của access token
+}
+
+// revokeToken - thu hồi 1 access token theo jti (token cũ chưa có jti thì bỏ qua)
+func (r *accessTokenRevoker) revokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	if tokenID == "" {
+		return nil
+	}
+	if err := r.store.RevokeToken(ctx, tokenID, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+// revokeUser - thu hồi mọi access token của user cấp trước thời điểm hiện tại
+func (r *accessTokenRevoker) revokeUser(ctx context.Context, userID uint) error {
+	if err := r.store.RevokeUserTokens(ctx, userID, time.Now(), r.accessTTL); err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+	return nil
+}
+
+// revokeSession - thu hồi mọi access token của 1 session (refresh token family) cấp trước thời điểm hiện tại
+func (r *accessTokenRevoker) revokeSession(ctx context.Context, sessionID string) error {
+	if err := r.store.RevokeSessionTokens(ctx, sessionID, time.Now(), r.accessTTL); err != nil {
+		return fmt.Errorf("failed to revoke session access tokens: %w", err)
+	}
+	return nil
+}
+
+// revokeClient - thu hồi mọi access token cấp cho 1 OAuth client trước thời điểm hiện tại
+func (r *accessTokenRevoker) revokeClient(ctx context.Context, clientID string) error {
+	if err := r.store.RevokeClientTokens(ctx, clientID, time.Now(), r.accessTTL); err != nil {
+		return fmt.Errorf("failed to revoke client access tokens: %w", err)
+	}
+	return nil
+}
+
+// isRevoked - access token đã bị thu hồi (theo jti hoặc mốc thu hồi của client / user / session)
+func (r *accessTokenRevoker) isRevoked(ctx context.Context, claims *jwt.AccessClaims) (bool, error) {
+	revoked, err := revocation.IsAccessTokenRevoked(ctx, r.store, claims)
+	if err != nil {
+		return revoked, fmt.Errorf("failed to check access token revocation: %w", err)
+	}
+	return revoked, nil
+}
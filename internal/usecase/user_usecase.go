@@ -5,26 +5,71 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/me/go-gin-auth/internal/domain"
 	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/mailer"
 	"github.com/me/go-gin-auth/pkg/password"
+	"github.com/me/go-gin-auth/pkg/revocation"
+	"github.com/me/go-gin-auth/pkg/tokenhash"
+	"github.com/me/go-gin-auth/pkg/utils"
+	"go.uber.org/zap"
 )
 
 // userUsecase - implement UserUsecase interface
 type userUsecase struct {
-	userRepo        repository.UserRepository
-	passwordService password.Service
+	userRepo         repository.UserRepository
+	tokenRepo        repository.TokenRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	roleRepo         repository.RoleRepository
+	patRepo          repository.PersonalAccessTokenRepository
+	audit            *auditor
+	webhooks         *webhookPublisher
+	passwordService  password.Service
+	passwordPolicy   *password.Policy
+	passwordHistory  *passwordHistory
+	passwordReset    *passwordResetIssuer
+	revoker          *accessTokenRevoker
+	notifier         *notifier
 }
 
 // NewUserUsecase - tạo user usecase mới
 func NewUserUsecase(
 	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	passwordResetRepo repository.PasswordResetRepository,
+	loginAttemptRepo repository.LoginAttemptRepository,
+	passwordHistoryRepo repository.PasswordHistoryRepository,
+	roleRepo repository.RoleRepository,
+	patRepo repository.PersonalAccessTokenRepository,
+	auditRepo repository.AuditLogRepository,
+	webhookRepo repository.WebhookRepository,
 	passwordService password.Service,
+	passwordPolicy *password.Policy,
+	tokenHashService tokenhash.Service,
+	revocationStore revocation.Store,
+	mailService mailer.Service,
+	logger *zap.Logger,
+	accessTokenTTL time.Duration,
+	passwordHistorySize int,
 ) UserUsecase {
+	notifier := &notifier{mailer: mailService, logger: logger}
 	return &userUsecase{
-		userRepo:        userRepo,
-		passwordService: passwordService,
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		roleRepo:         roleRepo,
+		patRepo:          patRepo,
+		audit:            &auditor{repo: auditRepo, logger: logger},
+		webhooks:         &webhookPublisher{repo: webhookRepo, logger: logger},
+		passwordService:  passwordService,
+		passwordPolicy:   passwordPolicy,
+		passwordHistory:  &passwordHistory{repo: passwordHistoryRepo, passwordService: passwordService, size: passwordHistorySize},
+		passwordReset:    &passwordResetIssuer{repo: passwordResetRepo, tokenHashService: tokenHashService, notifier: notifier},
+		revoker:          &accessTokenRevoker{store: revocationStore, accessTTL: accessTokenTTL},
+		notifier:         notifier,
 	}
 }
 
@@ -60,6 +105,7 @@ func (u *userUsecase) UpdateProfile(ctx context.Context, userID uint, req *domai
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &userID, TargetID: &userID, Action: domain.AuditActionProfileUpdate})
 	return user.ToResponse(), nil
 }
 
@@ -76,21 +122,43 @@ func (u *userUsecase) ChangePassword(ctx context.Context, userID uint, req *doma
 
 	// 2. Kiểm tra old password
 	if !u.passwordService.CheckPassword(req.OldPassword, user.PasswordHash) {
+		u.audit.record(ctx, domain.AuditEvent{
+			ActorID:  &userID,
+			TargetID: &userID,
+			Action:   domain.AuditActionPasswordChange,
+			Outcome:  domain.AuditOutcomeFailure,
+			Metadata: map[string]interface{}{"reason": "invalid_old_password"},
+		})
 		return errors.New("invalid old password")
 	}
 
-	// 3. Hash password mới
+	// 3. Kiểm tra password policy và lịch sử password
+	if err := u.passwordPolicy.Validate(req.NewPassword, user.Email, user.FullName); err != nil {
+		return err
+	}
+	if err := u.passwordHistory.check(ctx, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	// 4. Hash password mới
 	hashedPassword, err := u.passwordService.HashPassword(req.NewPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// 4. Update password
+	// 5. Update password
 	user.PasswordHash = hashedPassword
 
 	if err := u.userRepo.Update(ctx, user); err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
+	if err := u.passwordHistory.record(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+
+	// 6. Cảnh báo chủ tài khoản
+	u.notifier.securityAlert(ctx, user, alertPasswordChanged, nil)
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &userID, TargetID: &userID, Action: domain.AuditActionPasswordChange})
 
 	return nil
 }
@@ -134,3 +202,325 @@ func (u *userUsecase) ListUsers(ctx context.Context, req *domain.ListUsersReques
 		},
 	}, nil
 }
+
+// GetLockoutStatus - xem trạng thái khóa login của user (admin only)
+func (u *userUsecase) GetLockoutStatus(ctx context.Context, userID uint) (*domain.LockoutStatusResponse, error) {
+	// 1. Lấy user
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// 2. Lấy bộ đếm thất bại theo email
+	attempt, err := u.loginAttemptRepo.Get(ctx, domain.LoginAttemptSubjectEmail, normalizeEmail(user.Email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+
+	status := &domain.LockoutStatusResponse{UserID: user.ID}
+	if attempt == nil {
+		return status, nil
+	}
+
+	// 3. Tạo response
+	status.FailedAttempts = attempt.FailedCount
+	status.LastFailedAt = &attempt.LastFailedAt
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(time.Now()) {
+		status.Locked = true
+		status.LockedUntil = attempt.LockedUntil
+	}
+
+	return status, nil
+}
+
+// Unlock - mở khóa login cho user (admin only)
+// Khóa theo IP dùng chung cho mọi tài khoản từ địa chỉ đó, không gắn với user,
+// nên chỉ xóa khi admin chỉ rõ IP (vd. user bị khóa vì chung IP với kẻ tấn công)
+func (u *userUsecase) Unlock(ctx context.Context, userID uint, ip string) error {
+	// 1. Lấy user
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	// 2. Xóa bộ đếm thất bại theo email
+	if err := u.loginAttemptRepo.Reset(ctx, domain.LoginAttemptSubjectEmail, normalizeEmail(user.Email)); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+
+	// 3. Xóa khóa theo IP nếu admin chỉ định
+	event := domain.AuditEvent{TargetID: &userID, Action: domain.AuditActionUnlock}
+	if ip != "" {
+		if err := u.loginAttemptRepo.Reset(ctx, domain.LoginAttemptSubjectIP, ip); err != nil {
+			return fmt.Errorf("failed to reset login attempts: %w", err)
+		}
+		event.Metadata = map[string]interface{}{"ip": ip}
+	}
+
+	u.audit.record(ctx, event)
+	return nil
+}
+
+// GetUser - xem chi tiết user (admin only)
+func (u *userUsecase) GetUser(ctx context.Context, userID uint) (*domain.AdminUserResponse, error) {
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.ToAdminResponse(), nil
+}
+
+// AdminUpdateUser - cập nhật họ tên, email, trạng thái xác thực email (admin only)
+func (u *userUsecase) AdminUpdateUser(ctx context.Context, userID uint, req *domain.AdminUpdateUserRequest) (*domain.AdminUserResponse, error) {
+	// 1. Lấy user
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{}
+
+	// 2. Đổi email -> không trùng user khác, phải xác thực lại (trừ khi admin đánh dấu đã xác thực)
+	if req.Email != nil && !strings.EqualFold(*req.Email, user.Email) {
+		existing, err := u.userRepo.GetByEmail(ctx, *req.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing user: %w", err)
+		}
+		if existing != nil {
+			return nil, errors.New("user with this email already exists")
+		}
+		changes["email"] = map[string]string{"from": user.Email, "to": *req.Email}
+		user.Email = *req.Email
+		user.EmailVerifiedAt = nil
+	}
+
+	// 3. Các field còn lại
+	if req.FullName != nil {
+		changes["full_name"] = *req.FullName
+		user.FullName = *req.FullName
+	}
+	if req.EmailVerified != nil {
+		changes["email_verified"] = *req.EmailVerified
+		if !*req.EmailVerified {
+			user.EmailVerifiedAt = nil
+		} else if user.EmailVerifiedAt == nil {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+		}
+	}
+
+	// 4. Lưu vào database
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{TargetID: &userID, Action: domain.AuditActionUserUpdate, Metadata: changes})
+	return user.ToAdminResponse(), nil
+}
+
+// DeleteUser - xóa user (admin only, không tự xóa chính mình)
+func (u *userUsecase) DeleteUser(ctx context.Context, actorID, userID uint) error {
+	if actorID == userID {
+		return errors.New("you cannot delete your own account")
+	}
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.userRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+	if err := u.revoker.revokeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	u.webhooks.publish(ctx, domain.WebhookEventUserDeleted, userEventData(user, nil))
+
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &actorID, TargetID: &userID, Action: domain.AuditActionUserDelete})
+	return nil
+}
+
+// ChangeRole - đổi role chính của user (admin only)
+// Admin chỉ gán được role mà mình có đủ permission, access token đã cấp của user bị thu hồi
+func (u *userUsecase) ChangeRole(ctx context.Context, actorID, userID uint, req *domain.ChangeRoleRequest) (*domain.AdminUserResponse, error) {
+	// 1. Không tự đổi role của mình (tránh tự khóa quyền admin)
+	if actorID == userID {
+		return nil, errors.New("you cannot change your own role")
+	}
+
+	// 2. Role phải tồn tại
+	roles, err := u.roleRepo.GetByNames(ctx, []string{req.Role})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("unknown role: %s", req.Role)
+	}
+
+	// 3. Admin phải đang có mọi permission của role (tránh tự nâng quyền qua tài khoản khác)
+	role, err := u.roleRepo.GetByID(ctx, roles[0].ID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, fmt.Errorf("unknown role: %s", req.Role)
+	}
+	held, err := u.roleRepo.GetUserPermissions(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range role.Permissions {
+		if !utils.Contains(held, permission.Name) {
+			return nil, fmt.Errorf("cannot assign role %s with permission %s that you do not hold", role.Name, permission.Name)
+		}
+	}
+
+	// 4. Lưu
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	previousRole := user.Role
+	user.Role = req.Role
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// 5. Access token đã cấp mang role cũ -> thu hồi
+	if err := u.revoker.revokeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		ActorID:  &actorID,
+		TargetID: &userID,
+		Action:   domain.AuditActionRoleChange,
+		Metadata: map[string]interface{}{"from": previousRole, "to": req.Role},
+	})
+	u.webhooks.publish(ctx, domain.WebhookEventUserRoleChanged, userEventData(user, map[string]interface{}{"previous_role": previousRole}))
+
+	return user.ToAdminResponse(), nil
+}
+
+// ChangeStatus - khóa / tạm khóa có thời hạn / mở lại tài khoản (admin only)
+// Khóa tài khoản đăng xuất mọi session
+func (u *userUsecase) ChangeStatus(ctx context.Context, actorID, userID uint, req *domain.ChangeStatusRequest) (*domain.AdminUserResponse, error) {
+	// 1. Kiểm tra request
+	if actorID == userID {
+		return nil, errors.New("you cannot change your own status")
+	}
+	if req.SuspendedUntil != nil {
+		if req.Status != domain.UserStatusSuspended {
+			return nil, errors.New("suspended_until can only be set when suspending")
+		}
+		if !req.SuspendedUntil.After(time.Now()) {
+			return nil, errors.New("suspended_until must be in the future")
+		}
+	}
+
+	// 2. Lưu trạng thái mới
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	previousStatus := user.Status
+	user.Status = req.Status
+	user.StatusReason = req.Reason
+	user.SuspendedUntil = req.SuspendedUntil
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// 3. Không còn active -> vô hiệu hóa tất cả refresh token và access token đã cấp
+	if req.Status != domain.UserStatusActive {
+		if err := u.tokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+			return nil, fmt.Errorf("failed to revoke user tokens: %w", err)
+		}
+		if err := u.revoker.revokeUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	metadata := map[string]interface{}{"from": previousStatus, "to": req.Status, "reason": req.Reason}
+	if req.SuspendedUntil != nil {
+		metadata["suspended_until"] = req.SuspendedUntil.UTC()
+	}
+	u.audit.record(ctx, domain.AuditEvent{ActorID: &actorID, TargetID: &userID, Action: domain.AuditActionStatusChange, Metadata: metadata})
+	u.webhooks.publish(ctx, domain.WebhookEventUserStatusChanged, userEventData(user, map[string]interface{}{
+		"previous_status": previousStatus,
+		"reason":          req.Reason,
+		"suspended_until": req.SuspendedUntil,
+	}))
+
+	return user.ToAdminResponse(), nil
+}
+
+// ForcePasswordReset - bắt user đặt lại password qua email trước khi login tiếp (admin only)
+func (u *userUsecase) ForcePasswordReset(ctx context.Context, userID uint) error {
+	// 1. Đánh dấu phải reset
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	user.PasswordResetRequired = true
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// 2. Đăng xuất mọi session, vô hiệu hóa personal access token
+	if err := u.revokeAllTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	// 3. Gửi link reset password
+	if err := u.passwordReset.issue(ctx, user); err != nil {
+		return err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{TargetID: &userID, Action: domain.AuditActionForcePasswordReset})
+	return nil
+}
+
+// RevokeSessions - đăng xuất user khỏi mọi thiết bị (admin only)
+func (u *userUsecase) RevokeSessions(ctx context.Context, userID uint) error {
+	if _, err := u.getUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := u.revokeAllTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{TargetID: &userID, Action: domain.AuditActionRevokeSessions})
+	return nil
+}
+
+// revokeAllTokens - thu hồi refresh token, access token và personal access token của user
+func (u *userUsecase) revokeAllTokens(ctx context.Context, userID uint) error {
+	if err := u.tokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	if err := u.revoker.revokeUser(ctx, userID); err != nil {
+		return err
+	}
+	return u.patRepo.RevokeAllForUser(ctx, userID)
+}
+
+// getUser - lấy user, lỗi nếu không tồn tại
+func (u *userUsecase) getUser(ctx context.Context, userID uint) (*domain.User, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"go.uber.org/zap"
+)
+
+// webhookPublisher - đưa sự kiện vào hàng đợi gửi cho các endpoint đã đăng ký
+// Chỉ ghi vào database, WebhookWorker gửi nền; lỗi chỉ được log, không làm fail request
+type webhookPublisher struct {
+	repo   repository.WebhookRepository
+	logger *zap.Logger
+}
+
+// publish - tạo 1 delivery cho mỗi endpoint đăng ký eventType
+func (p *webhookPublisher) publish(ctx context.Context, eventType string, data interface{}) {
+	ctx = context.WithoutCancel(ctx)
+
+	// 1. Endpoint đăng ký sự kiện
+	endpoints, err := p.repo.ListSubscribed(ctx, eventType)
+	if err != nil {
+		p.logger.Error("Failed to load webhook endpoints", zap.String("event", eventType), zap.Error(err))
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	// 2. Payload giống nhau cho mọi endpoint (cùng event ID)
+	now := time.Now()
+	eventID := uuid.New().String()
+	payload, err := json.Marshal(domain.WebhookPayload{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: now.UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		p.logger.Error("Failed to encode webhook payload", zap.String("event", eventType), zap.Error(err))
+		return
+	}
+
+	// 3. Đưa vào hàng đợi
+	deliveries := make([]domain.WebhookDelivery, len(endpoints))
+	for i, endpoint := range endpoints {
+		deliveries[i] = domain.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		}
+	}
+	if err := p.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		p.logger.Error("Failed to enqueue webhook deliveries", zap.String("event", eventType), zap.Error(err))
+	}
+}
+
+// userEventData - data của các sự kiện user (kèm thông tin thêm nếu có)
+func userEventData(user *domain.User, extra map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{"user": user.ToResponse()}
+	for k, v := range extra {
+		data[k] = v
+	}
+	return data
+}
@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/webhook"
+	"go.uber.org/zap"
+)
+
+// webhookSecretPrefix - prefix của secret ký webhook (dễ nhận biết khi lộ)
+const webhookSecretPrefix = "whsec_"
+
+// webhookUsecase - implement WebhookUsecase interface
+type webhookUsecase struct {
+	webhookRepo repository.WebhookRepository
+	audit       *auditor
+}
+
+// NewWebhookUsecase - tạo webhook usecase mới
+func NewWebhookUsecase(webhookRepo repository.WebhookRepository, auditRepo repository.AuditLogRepository, logger *zap.Logger) WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepo: webhookRepo,
+		audit:       &auditor{repo: auditRepo, logger: logger},
+	}
+}
+
+// ListEndpoints - danh sách endpoint (admin only)
+func (u *webhookUsecase) ListEndpoints(ctx context.Context) ([]domain.WebhookEndpointResponse, error) {
+	endpoints, err := u.webhookRepo.ListEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.WebhookEndpointResponse, len(endpoints))
+	for i := range endpoints {
+		responses[i] = *endpoints[i].ToResponse()
+	}
+	return responses, nil
+}
+
+// CreateEndpoint - tạo endpoint, secret chỉ trả về 1 lần (admin only)
+func (u *webhookUsecase) CreateEndpoint(ctx context.Context, req *domain.CreateWebhookEndpointRequest) (*domain.WebhookEndpointResponse, error) {
+	// 1. Kiểm tra URL
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	// 2. Tạo secret ký HMAC
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Lưu
+	endpoint := &domain.WebhookEndpoint{
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      secret,
+		Events:      joinEvents(req.Events),
+		Active:      true,
+	}
+	if err := u.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionWebhookCreate,
+		Metadata: map[string]interface{}{"endpoint_id": endpoint.ID, "url": endpoint.URL, "events": endpoint.Events},
+	})
+
+	resp := endpoint.ToResponse()
+	resp.Secret = secret
+	return resp, nil
+}
+
+// GetEndpoint - chi tiết endpoint (admin only)
+func (u *webhookUsecase) GetEndpoint(ctx context.Context, endpointID uint) (*domain.WebhookEndpointResponse, error) {
+	endpoint, err := u.getEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.ToResponse(), nil
+}
+
+// UpdateEndpoint - đổi URL, mô tả, sự kiện đăng ký hoặc bật/tắt (admin only)
+func (u *webhookUsecase) UpdateEndpoint(ctx context.Context, endpointID uint, req *domain.UpdateWebhookEndpointRequest) (*domain.WebhookEndpointResponse, error) {
+	// 1. Lấy endpoint
+	endpoint, err := u.getEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Cập nhật các field được gửi
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		endpoint.URL = *req.URL
+	}
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+	}
+	if len(req.Events) > 0 {
+		endpoint.Events = joinEvents(req.Events)
+	}
+	if req.Active != nil {
+		endpoint.Active = *req.Active
+	}
+
+	// 3. Lưu
+	if err := u.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionWebhookUpdate,
+		Metadata: map[string]interface{}{"endpoint_id": endpoint.ID, "url": endpoint.URL, "events": endpoint.Events, "active": endpoint.Active},
+	})
+	return endpoint.ToResponse(), nil
+}
+
+// DeleteEndpoint - xóa endpoint và lịch sử gửi (admin only)
+func (u *webhookUsecase) DeleteEndpoint(ctx context.Context, endpointID uint) error {
+	endpoint, err := u.getEndpoint(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := u.webhookRepo.DeleteEndpoint(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("webhook endpoint not found")
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionWebhookDelete,
+		Metadata: map[string]interface{}{"endpoint_id": endpoint.ID, "url": endpoint.URL},
+	})
+	return nil
+}
+
+// RotateSecret - tạo secret mới, secret cũ hết hiệu lực ngay (admin only)
+func (u *webhookUsecase) RotateSecret(ctx context.Context, endpointID uint) (*domain.WebhookEndpointResponse, error) {
+	endpoint, err := u.getEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = secret
+	if err := u.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	u.audit.record(ctx, domain.AuditEvent{
+		Action:   domain.AuditActionWebhookRotate,
+		Metadata: map[string]interface{}{"endpoint_id": endpoint.ID},
+	})
+
+	resp := endpoint.ToResponse()
+	resp.Secret = secret
+	return resp, nil
+}
+
+// ListDeliveries - lịch sử gửi của endpoint (admin only)
+func (u *webhookUsecase) ListDeliveries(ctx context.Context, endpointID uint, req *domain.ListWebhookDeliveriesRequest) (*domain.PaginatedWebhookDeliveriesResponse, error) {
+	// 1. Endpoint phải tồn tại
+	if _, err := u.getEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+
+	// 2. Set defaults
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	// 3. Lấy danh sách
+	deliveries, total, err := u.webhookRepo.ListDeliveries(ctx, endpointID, req.Status, req.Limit, (req.Page-1)*req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PaginatedWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Pagination: domain.Pagination{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+		},
+	}, nil
+}
+
+// GetDelivery - chi tiết delivery kèm log từng lần gửi (admin only)
+func (u *webhookUsecase) GetDelivery(ctx context.Context, deliveryID uint) (*domain.WebhookDelivery, error) {
+	delivery, err := u.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, errors.New("webhook delivery not found")
+	}
+	return delivery, nil
+}
+
+// Redeliver - gửi lại sự kiện (tạo delivery mới cùng event ID và payload) (admin only)
+func (u *webhookUsecase) Redeliver(ctx context.Context, deliveryID uint) (*domain.WebhookDelivery, error) {
+	// 1. Lấy delivery gốc
+	original, err := u.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Đưa bản sao vào hàng đợi, gửi ngay ở lần quét tới
+	deliveries := []domain.WebhookDelivery{{
+		EndpointID:    original.EndpointID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+	}}
+	if err := u.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return nil, err
+	}
+
+	return &deliveries[0], nil
+}
+
+// getEndpoint - lấy endpoint, lỗi nếu không tồn tại
+func (u *webhookUsecase) getEndpoint(ctx context.Context, endpointID uint) (*domain.WebhookEndpoint, error) {
+	endpoint, err := u.webhookRepo.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, errors.New("webhook endpoint not found")
+	}
+	return endpoint, nil
+}
+
+// validateWebhookURL - chỉ nhận URL http(s) tuyệt đối, host không phải localhost / IP nội bộ
+// Hostname vẫn có thể resolve ra IP nội bộ, webhook client kiểm tra lại IP thật lúc kết nối
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return errors.New("webhook url must be an absolute http or https url")
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.New("webhook url must not point to localhost")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !webhook.IsPublicAddress(addr) {
+		return errors.New("webhook url must not point to a private, loopback or link-local address")
+	}
+	return nil
+}
+
+// generateWebhookSecret - secret ngẫu nhiên 32 bytes
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// joinEvents - lưu danh sách sự kiện (bỏ trùng, giữ thứ tự)
+func joinEvents(events []string) string {
+	seen := make(map[string]bool, len(events))
+	unique := make([]string, 0, len(events))
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return strings.Join(unique, ",")
+}
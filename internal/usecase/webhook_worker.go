@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/me/go-gin-auth/internal/domain"
+	"github.com/me/go-gin-auth/internal/repository"
+	"github.com/me/go-gin-auth/pkg/utils"
+	"github.com/me/go-gin-auth/pkg/webhook"
+	"go.uber.org/zap"
+)
+
+// WebhookWorkerConfig - cấu hình gửi webhook nền
+type WebhookWorkerConfig struct {
+	PollInterval    time.Duration // Chu kỳ quét hàng đợi
+	BatchSize       int           // Số delivery lấy mỗi lần (gửi song song)
+	MaxAttempts     int           // Số lần gửi tối đa trước khi đánh dấu failed
+	RetryBackoff    time.Duration // Chờ trước lần retry đầu, nhân đôi mỗi lần
+	RetryBackoffMax time.Duration // Thời gian chờ tối đa giữa 2 lần retry
+	SendTimeout     time.Duration // Timeout mỗi lần gửi (để tính thời gian giữ chỗ)
+}
+
+// WebhookWorker - quét bảng webhook_deliveries và gửi các delivery đến hạn
+// Hàng đợi nằm trong database nên không mất sự kiện khi restart, chạy nhiều instance cũng không gửi trùng
+type WebhookWorker struct {
+	repo   repository.WebhookRepository
+	client webhook.Client
+	logger *zap.Logger
+	cfg    WebhookWorkerConfig
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewWebhookWorker - tạo webhook worker (chưa chạy, gọi Start)
+func NewWebhookWorker(repo repository.WebhookRepository, client webhook.Client, logger *zap.Logger, cfg WebhookWorkerConfig) *WebhookWorker {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 20
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 30 * time.Second
+	}
+	if cfg.RetryBackoffMax <= 0 {
+		cfg.RetryBackoffMax = 6 * time.Hour
+	}
+	if cfg.SendTimeout <= 0 {
+		cfg.SendTimeout = 10 * time.Second
+	}
+
+	return &WebhookWorker{
+		repo:   repo,
+		client: client,
+		logger: logger,
+		cfg:    cfg,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start - chạy vòng lặp gửi trong goroutine
+func (w *WebhookWorker) Start() {
+	go w.run()
+}
+
+// Close - dừng worker, chờ batch đang gửi xong (hoặc ctx hết hạn)
+func (w *WebhookWorker) Close(ctx context.Context) error {
+	w.once.Do(func() { close(w.stop) })
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *WebhookWorker) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Gửi liên tục khi hàng đợi còn đầy, nghỉ khi đã hết việc
+		for w.processBatch() == w.cfg.BatchSize {
+			select {
+			case <-w.stop:
+				return
+			default:
+			}
+		}
+
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processBatch - gửi 1 batch, trả về số delivery đã nhận
+func (w *WebhookWorker) processBatch() int {
+	ctx := context.Background()
+
+	// Giữ chỗ đủ lâu để gửi xong cả batch, quá thời gian này delivery được nhận lại
+	lease := w.cfg.SendTimeout + time.Minute
+	deliveries, err := w.repo.ClaimDue(ctx, w.cfg.BatchSize, lease)
+	if err != nil {
+		w.logger.Error("Failed to claim webhook deliveries", zap.Error(err))
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *domain.WebhookDelivery) {
+			defer wg.Done()
+			w.deliver(ctx, delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+// deliver - gửi 1 delivery và lưu kết quả
+func (w *WebhookWorker) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	now := time.Now()
+
+	// 1. Endpoint đã tắt -> dừng gửi, không tính là 1 lần thử
+	if delivery.Endpoint == nil || !delivery.Endpoint.Active {
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.LastError = "endpoint is disabled"
+		if err := w.repo.RecordAttempt(ctx, delivery, nil); err != nil {
+			w.logger.Error("Failed to save webhook delivery", zap.Uint("delivery_id", delivery.ID), zap.Error(err))
+		}
+		return
+	}
+
+	// 2. Gửi
+	sendCtx, cancel := context.WithTimeout(ctx, w.cfg.SendTimeout)
+	result := w.client.Send(sendCtx, &webhook.Request{
+		URL:        delivery.Endpoint.URL,
+		Secret:     delivery.Endpoint.Secret,
+		EventID:    delivery.EventID,
+		EventType:  delivery.EventType,
+		DeliveryID: strconv.FormatUint(uint64(delivery.ID), 10),
+		Body:       []byte(delivery.Payload),
+	})
+	cancel()
+
+	// 3. Cập nhật trạng thái: thành công / retry với backoff / hết lượt
+	delivery.AttemptCount++
+	delivery.LastAttemptAt = &now
+	delivery.LastStatusCode = result.StatusCode
+	delivery.LastError = ""
+
+	attempt := &domain.WebhookDeliveryAttempt{
+		DeliveryID:    delivery.ID,
+		AttemptNumber: delivery.AttemptCount,
+		StatusCode:    result.StatusCode,
+		ResponseBody:  result.ResponseBody,
+		DurationMs:    result.Duration.Milliseconds(),
+	}
+
+	switch {
+	case result.OK():
+		delivery.Status = domain.WebhookDeliverySucceeded
+	case delivery.AttemptCount >= w.cfg.MaxAttempts:
+		delivery.Status = domain.WebhookDeliveryFailed
+	default:
+		delivery.Status = domain.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(w.retryDelay(delivery.AttemptCount))
+	}
+	if result.Err != nil {
+		delivery.LastError = utils.Truncate(result.Err.Error(), 1024)
+		attempt.Error = delivery.LastError
+		w.logger.Warn("Webhook delivery failed",
+			zap.Uint("delivery_id", delivery.ID),
+			zap.Uint("endpoint_id", delivery.EndpointID),
+			zap.String("event", delivery.EventType),
+			zap.Int("attempt", delivery.AttemptCount),
+			zap.Error(result.Err),
+		)
+	}
+
+	if err := w.repo.RecordAttempt(ctx, delivery, attempt); err != nil {
+		w.logger.Error("Failed to save webhook attempt", zap.Uint("delivery_id", delivery.ID), zap.Error(err))
+	}
+}
+
+// retryDelay - RetryBackoff * 2^(attempt-1), tối đa RetryBackoffMax
+func (w *WebhookWorker) retryDelay(attempt int) time.Duration {
+	delay := w.cfg.RetryBackoff
+	for i := 1; i < attempt && delay < w.cfg.RetryBackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > w.cfg.RetryBackoffMax {
+		delay = w.cfg.RetryBackoffMax
+	}
+	return delay
+}
@@ -1,32 +1,74 @@
 package jwt
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // Service - interface cho JWT operations
 type Service interface {
-	GenerateAccessToken(userID uint, role string) (string, error)
+	GenerateAccessToken(claims AccessClaims) (string, error) // Service tự set exp/iat/nbf
 	GenerateRefreshToken(userID uint) (string, error)
 	ValidateAccessToken(tokenString string) (*AccessClaims, error)
 	ValidateRefreshToken(tokenString string) (*RefreshClaims, error)
+	GenerateMFAToken(userID uint) (string, error)
+	ValidateMFAToken(tokenString string) (*MFAClaims, error)
+	GenerateIDToken(claims IDTokenClaims) (string, error) // OpenID Connect ID token, cần key bất đối xứng
+	JWKS() *JWKS                                          // Public keys để service khác verify access token
+	SigningAlgorithm() string                             // Thuật toán của key đang ký
 }
 
+// ErrSymmetricSigningKey - ID token phải verify được bằng JWKS nên không ký bằng HS256
+var ErrSymmetricSigningKey = errors.New("id tokens require an asymmetric signing key (RS256, ES256 or EdDSA)")
+
+// Loại chủ thể của access token (claim sub_type)
+const (
+	SubjectTypeUser    = "user"    // Người dùng, sub là user ID
+	SubjectTypeService = "service" // Service xác thực bằng client credentials, không có sub, danh tính là client_id
+)
+
+// mfaTokenTTL - thời gian sống của MFA challenge token
+const mfaTokenTTL = 5 * time.Minute
+
 // jwtService - implementation của Service interface
 type jwtService struct {
-	accessSecret  string
+	keyring       *Keyring // Key ký access token + các key cũ còn verify được
 	refreshSecret string
 	accessTTL     time.Duration
 	refreshTTL    time.Duration
+	mfaSecret     []byte // Key riêng cho MFA challenge, tránh dùng nhầm làm access/refresh token
 }
 
 // AccessClaims - dữ liệu trong access token
 type AccessClaims struct {
-	UserID uint   `json:"sub"`
-	Role   string `json:"role"`
+	UserID         uint     `json:"sub,omitempty"`
+	SubjectType    string   `json:"sub_type,omitempty"` // user | service (token cũ không có -> user)
+	Role           string   `json:"role,omitempty"`
+	SessionID      string   `json:"sid,omitempty"`       // Session (refresh token family) sinh ra token này
+	Permissions    []string `json:"perms,omitempty"`     // Permission hiệu lực lúc cấp token (cập nhật khi refresh)
+	OrganizationID uint     `json:"org,omitempty"`       // Organization đang làm việc (0 = chưa chọn)
+	ClientID       string   `json:"client_id,omitempty"` // OAuth client nhận token hoặc service sở hữu token (rỗng = session của chính app này)
+	Scope          string   `json:"scope,omitempty"`     // Scope OAuth, phân cách bằng khoảng trắng
+	jwt.RegisteredClaims
+}
+
+// IsService - token của service (client credentials), không gắn với user nào
+func (c *AccessClaims) IsService() bool {
+	return c.SubjectType == SubjectTypeService
+}
+
+// IDTokenClaims - dữ liệu trong OpenID Connect ID token (iss, sub, aud đặt ở RegisteredClaims)
+type IDTokenClaims struct {
+	Nonce         string `json:"nonce,omitempty"`
+	AuthTime      int64  `json:"auth_time,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -36,31 +78,47 @@ type RefreshClaims struct {
 	jwt.RegisteredClaims
 }
 
+// MFAClaims - dữ liệu trong MFA challenge token
+type MFAClaims struct {
+	UserID uint `json:"sub"`
+	jwt.RegisteredClaims
+}
+
 // NewJWTService - tạo JWT service mới
-func NewJWTService(accessSecret, refreshSecret string, accessTTL, refreshTTL time.Duration) Service {
+// Refresh token và MFA token chỉ dùng nội bộ nên luôn ký HS256 bằng refreshSecret
+func NewJWTService(keyring *Keyring, refreshSecret string, accessTTL, refreshTTL time.Duration) Service {
 	return &jwtService{
-		accessSecret:  accessSecret,
+		keyring:       keyring,
 		refreshSecret: refreshSecret,
 		accessTTL:     accessTTL,
 		refreshTTL:    refreshTTL,
+		mfaSecret:     deriveKey(refreshSecret, "mfa-challenge"),
 	}
 }
 
-// GenerateAccessToken - tạo access token
-func (s *jwtService) GenerateAccessToken(userID uint, role string) (string, error) {
-	now := time.Now()
-	claims := &AccessClaims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-		},
+// deriveKey - tạo key con từ secret gốc bằng HMAC-SHA256
+func deriveKey(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// GenerateAccessToken - tạo access token (giữ iss/aud nếu caller đã set)
+func (s *jwtService) GenerateAccessToken(claims AccessClaims) (string, error) {
+	if claims.SubjectType == "" {
+		claims.SubjectType = SubjectTypeUser
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.accessSecret))
+	now := time.Now()
+	claims.ID = uuid.New().String() // jti - để thu hồi từng token trước khi hết hạn (revocation.Store)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(s.accessTTL))
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.NotBefore = jwt.NewNumericDate(now)
+
+	key := s.keyring.Active()
+	token := jwt.NewWithClaims(key.method, &claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
 }
 
 // GenerateRefreshToken - tạo refresh token
@@ -69,6 +127,7 @@ func (s *jwtService) GenerateRefreshToken(userID uint) (string, error) {
 	claims := &RefreshClaims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(), // jti - đảm bảo mỗi refresh token là duy nhất
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.refreshTTL)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
@@ -81,12 +140,7 @@ func (s *jwtService) GenerateRefreshToken(userID uint) (string, error) {
 
 // ValidateAccessToken - validate access token
 func (s *jwtService) ValidateAccessToken(tokenString string) (*AccessClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return []byte(s.accessSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, s.accessKeyFunc)
 
 	if err != nil {
 		return nil, err
@@ -118,3 +172,98 @@ func (s *jwtService) ValidateRefreshToken(tokenString string) (*RefreshClaims, e
 
 	return nil, errors.New("invalid token")
 }
+
+// GenerateMFAToken - tạo MFA challenge token (sau khi đã đúng password)
+func (s *jwtService) GenerateMFAToken(userID uint) (string, error) {
+	now := time.Now()
+	claims := &MFAClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(mfaTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.mfaSecret)
+}
+
+// ValidateMFAToken - validate MFA challenge token
+func (s *jwtService) ValidateMFAToken(tokenString string) (*MFAClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MFAClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return s.mfaSecret, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*MFAClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+// GenerateIDToken - tạo ID token bằng key đang ký access token, sống bằng access token
+func (s *jwtService) GenerateIDToken(claims IDTokenClaims) (string, error) {
+	key := s.keyring.Active()
+	if key.IsSymmetric() {
+		return "", ErrSymmetricSigningKey
+	}
+
+	now := time.Now()
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(s.accessTTL))
+	claims.IssuedAt = jwt.NewNumericDate(now)
+
+	token := jwt.NewWithClaims(key.method, &claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// accessKeyFunc - chọn key verify theo kid trong header
+func (s *jwtService) accessKeyFunc(token *jwt.Token) (interface{}, error) {
+	// 1. Có kid -> dùng đúng key đó, thuật toán phải khớp (chống alg confusion)
+	if kid, ok := token.Header["kid"].(string); ok {
+		key, found := s.keyring.Get(kid)
+		if !found {
+			return nil, errors.New("unknown key id")
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("invalid signing method")
+		}
+		return key.public, nil
+	}
+
+	// 2. Token phát hành trước khi có kid -> thử tất cả key cùng thuật toán
+	set := jwt.VerificationKeySet{}
+	for _, key := range s.keyring.Keys() {
+		if key.method.Alg() == token.Method.Alg() {
+			set.Keys = append(set.Keys, key.public)
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, errors.New("invalid signing method")
+	}
+	return set, nil
+}
+
+// JWKS - public key của tất cả key bất đối xứng (cả key đã nghỉ, để token cũ vẫn verify được)
+func (s *jwtService) JWKS() *JWKS {
+	jwks := &JWKS{Keys: []JWK{}}
+	for _, key := range s.keyring.Keys() {
+		if jwk := key.PublicJWK(); jwk != nil {
+			jwks.Keys = append(jwks.Keys, *jwk)
+		}
+	}
+	return jwks
+}
+
+// SigningAlgorithm - thuật toán của key đang ký (quảng bá trong OpenID discovery)
+func (s *jwtService) SigningAlgorithm() string {
+	return s.keyring.Active().Algorithm
+}
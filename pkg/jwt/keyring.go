@@ -0,0 +1,311 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Quy ước thư mục keyring:
+//
+//	<kid>.pem    - private key bất đối xứng (RSA / EC P-256 / Ed25519)
+//	<kid>.secret - shared secret cho HS256
+//	active       - chứa kid của key đang dùng để ký
+//
+// Các key còn lại là key đã nghỉ, chỉ dùng để verify token cũ.
+const (
+	activeKeyFile   = "active"
+	privateKeyExt   = ".pem"
+	secretKeyExt    = ".secret"
+	keyringFileMode = 0600
+)
+
+// Keyring - 1 key đang ký + các key cũ chỉ để verify (chọn theo kid)
+type Keyring struct {
+	mu     sync.RWMutex
+	active *SigningKey
+	keys   map[string]*SigningKey
+	dir    string // Rỗng nếu keyring tạo từ config (không reload được)
+}
+
+// NewKeyring - tạo keyring từ key đang dùng và các key đã nghỉ
+func NewKeyring(active *SigningKey, retired ...*SigningKey) (*Keyring, error) {
+	k := &Keyring{}
+	if err := k.set(active, retired); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// LoadKeyringDir - đọc keyring từ thư mục (xem quy ước ở trên)
+func LoadKeyringDir(dir string) (*Keyring, error) {
+	k := &Keyring{dir: dir}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload - đọc lại thư mục keyring (dùng khi promote key mới, không cần restart)
+func (k *Keyring) Reload() error {
+	if k.dir == "" {
+		return errors.New("keyring is not backed by a directory")
+	}
+
+	keys, activeID, err := ReadKeyDir(k.dir)
+	if err != nil {
+		return err
+	}
+
+	var active *SigningKey
+	var retired []*SigningKey
+	for _, key := range keys {
+		if key.ID == activeID {
+			active = key
+		} else {
+			retired = append(retired, key)
+		}
+	}
+	if active == nil {
+		return fmt.Errorf("active key %q not found in %s", activeID, k.dir)
+	}
+
+	return k.set(active, retired)
+}
+
+// Active - key đang dùng để ký token mới
+func (k *Keyring) Active() *SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Get - lấy key theo kid
+func (k *Keyring) Get(id string) (*SigningKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	return key, ok
+}
+
+// Keys - tất cả key (active trước, sau đó theo kid)
+func (k *Keyring) Keys() []*SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := []*SigningKey{k.active}
+	var ids []string
+	for id := range k.keys {
+		if id != k.active.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		keys = append(keys, k.keys[id])
+	}
+	return keys
+}
+
+// set - thay toàn bộ key (atomic với các request đang verify)
+func (k *Keyring) set(active *SigningKey, retired []*SigningKey) error {
+	if active == nil {
+		return errors.New("keyring requires an active key")
+	}
+
+	keys := map[string]*SigningKey{active.ID: active}
+	for _, key := range retired {
+		if _, exists := keys[key.ID]; exists {
+			return fmt.Errorf("duplicate key id: %s", key.ID)
+		}
+		keys[key.ID] = key
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.active = active
+	k.keys = keys
+	return nil
+}
+
+// ReadKeyDir - đọc tất cả key và kid đang active (rỗng nếu chưa có) trong thư mục keyring
+func ReadKeyDir(dir string) ([]*SigningKey, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read keyring dir: %w", err)
+	}
+
+	var keys []*SigningKey
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		id := strings.TrimSuffix(name, ext)
+		if ext != privateKeyExt && ext != secretKeyExt {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read key %s: %w", name, err)
+		}
+
+		var key *SigningKey
+		if ext == secretKeyExt {
+			key = NewHMACKey(id, strings.TrimSpace(string(raw)))
+		} else {
+			algorithm, err := DetectAlgorithm(raw)
+			if err != nil {
+				return nil, "", fmt.Errorf("key %s: %w", name, err)
+			}
+			if key, err = ParsePrivateKeyPEM(algorithm, raw, id); err != nil {
+				return nil, "", fmt.Errorf("key %s: %w", name, err)
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	// Thư mục mới chưa promote key nào -> activeID rỗng
+	activeID, err := os.ReadFile(filepath.Join(dir, activeKeyFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, "", fmt.Errorf("failed to read active key id: %w", err)
+	}
+
+	return keys, strings.TrimSpace(string(activeID)), nil
+}
+
+// WriteKeyFile - lưu key mới vào thư mục keyring (chưa active)
+func WriteKeyFile(dir, id string, data []byte, symmetric bool) error {
+	ext := privateKeyExt
+	if symmetric {
+		ext = secretKeyExt
+	}
+
+	path := filepath.Join(dir, id+ext)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("key %s already exists", id)
+	}
+	return os.WriteFile(path, data, keyringFileMode)
+}
+
+// SetActiveKey - đánh dấu kid là key dùng để ký (ghi atomic bằng rename)
+func SetActiveKey(dir, id string) error {
+	tmp := filepath.Join(dir, activeKeyFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(id+"\n"), keyringFileMode); err != nil {
+		return fmt.Errorf("failed to write active key id: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(dir, activeKeyFile))
+}
+
+// RemoveKeyFile - xóa key đã nghỉ khỏi thư mục keyring
+func RemoveKeyFile(dir, id string) error {
+	for _, ext := range []string{privateKeyExt, secretKeyExt} {
+		path := filepath.Join(dir, id+ext)
+		if _, err := os.Stat(path); err == nil {
+			return os.Remove(path)
+		}
+	}
+	return fmt.Errorf("key %s not found", id)
+}
+
+// DetectAlgorithm - suy ra thuật toán ký từ loại private key trong PEM
+func DetectAlgorithm(pemBytes []byte) (string, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return "", errors.New("invalid PEM data")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return AlgorithmRS256, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return "", errors.New("only P-256 EC keys are supported")
+		}
+		return AlgorithmES256, nil
+	case ed25519.PrivateKey:
+		return AlgorithmEdDSA, nil
+	default:
+		return "", errors.New("unsupported private key type")
+	}
+}
+
+// KeyringConfig - cấu hình keyring khi không dùng thư mục
+type KeyringConfig struct {
+	Algorithm        string   // Thuật toán của key đang ký
+	Secret           string   // Secret HS256 đang dùng
+	PrivateKeyPath   string   // Private key đang dùng (RS256/ES256/EdDSA)
+	KeyID            string   // kid của key đang dùng, rỗng -> tự tính
+	PreviousSecrets  []string // Secret HS256 cũ, chỉ để verify
+	PreviousKeyPaths []string // Private key cũ, chỉ để verify
+	KeysDir          string   // Nếu có -> đọc keyring từ thư mục, bỏ qua các field trên
+}
+
+// LoadKeyring - tạo keyring từ thư mục hoặc từ config
+func LoadKeyring(cfg KeyringConfig) (*Keyring, error) {
+	if cfg.KeysDir != "" {
+		return LoadKeyringDir(cfg.KeysDir)
+	}
+
+	// 1. Key đang ký
+	active, err := LoadSigningKey(cfg.Algorithm, cfg.Secret, cfg.PrivateKeyPath, cfg.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Các key cũ (bỏ qua nếu trùng key đang dùng)
+	var retired []*SigningKey
+	for _, secret := range cfg.PreviousSecrets {
+		if secret = strings.TrimSpace(secret); secret == "" || secret == cfg.Secret {
+			continue
+		}
+		retired = append(retired, NewHMACKey(HMACKeyID(secret), secret))
+	}
+	for _, path := range cfg.PreviousKeyPaths {
+		if path = strings.TrimSpace(path); path == "" || path == cfg.PrivateKeyPath {
+			continue
+		}
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read previous JWT key: %w", err)
+		}
+		algorithm, err := DetectAlgorithm(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("previous JWT key %s: %w", path, err)
+		}
+		key, err := ParsePrivateKeyPEM(algorithm, pemBytes, "")
+		if err != nil {
+			return nil, fmt.Errorf("previous JWT key %s: %w", path, err)
+		}
+		retired = append(retired, key)
+	}
+
+	return NewKeyring(active, retired...)
+}
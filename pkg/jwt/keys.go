@@ -0,0 +1,217 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Các thuật toán ký access token được hỗ trợ
+const (
+	AlgorithmHS256 = "HS256" // Shared secret (mặc định, tương thích ngược)
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey - 1 key dùng để ký/verify access token
+type SigningKey struct {
+	ID        string // kid trong JWT header
+	Algorithm string
+	method    jwt.SigningMethod
+	private   interface{} // []byte (HMAC), *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey
+	public    interface{} // []byte (HMAC), *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey
+}
+
+// JWK - public key theo định dạng RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // EC / OKP curve
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS - JSON Web Key Set trả về ở /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewHMACKey - tạo key HS256 từ shared secret
+func NewHMACKey(id, secret string) *SigningKey {
+	return &SigningKey{
+		ID:        id,
+		Algorithm: AlgorithmHS256,
+		method:    jwt.SigningMethodHS256,
+		private:   []byte(secret),
+		public:    []byte(secret),
+	}
+}
+
+// ParsePrivateKeyPEM - tạo key bất đối xứng từ private key PEM
+// kid rỗng -> dùng JWK thumbprint (RFC 7638) của public key
+func ParsePrivateKeyPEM(algorithm string, pemBytes []byte, id string) (*SigningKey, error) {
+	key := &SigningKey{ID: id, Algorithm: algorithm}
+
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		if privateKey.N.BitLen() < 2048 {
+			return nil, errors.New("RSA key must be at least 2048 bits")
+		}
+		key.method, key.private, key.public = jwt.SigningMethodRS256, privateKey, &privateKey.PublicKey
+	case AlgorithmES256:
+		privateKey, err := jwt.ParseECPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EC private key: %w", err)
+		}
+		if privateKey.Curve != elliptic.P256() {
+			return nil, errors.New("ES256 requires a P-256 key")
+		}
+		key.method, key.private, key.public = jwt.SigningMethodES256, privateKey, &privateKey.PublicKey
+	case AlgorithmEdDSA:
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Ed25519 private key: %w", err)
+		}
+		edKey, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("EdDSA requires an Ed25519 key")
+		}
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, edKey, edKey.Public()
+	default:
+		return nil, fmt.Errorf("unsupported asymmetric algorithm: %s", algorithm)
+	}
+
+	if key.ID == "" {
+		thumbprint, err := key.Thumbprint()
+		if err != nil {
+			return nil, err
+		}
+		key.ID = thumbprint
+	}
+
+	return key, nil
+}
+
+// LoadSigningKey - tạo signing key theo cấu hình JWT
+// HS256 dùng secret, các thuật toán khác đọc private key PEM từ file
+func LoadSigningKey(algorithm, secret, privateKeyPath, id string) (*SigningKey, error) {
+	if algorithm == "" || algorithm == AlgorithmHS256 {
+		if secret == "" {
+			return nil, errors.New("JWT access secret is required for HS256")
+		}
+		if id == "" {
+			id = HMACKeyID(secret)
+		}
+		return NewHMACKey(id, secret), nil
+	}
+
+	if privateKeyPath == "" {
+		return nil, fmt.Errorf("JWT private key path is required for %s", algorithm)
+	}
+	pemBytes, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+	}
+
+	return ParsePrivateKeyPEM(algorithm, pemBytes, id)
+}
+
+// HMACKeyID - kid mặc định cho key HMAC, suy ra từ secret để đổi secret thì đổi kid
+func HMACKeyID(secret string) string {
+	sum := sha256.Sum256([]byte("kid:" + secret))
+	return "hs-" + encodeSegment(sum[:8])
+}
+
+// IsSymmetric - key HMAC không được công khai qua JWKS
+func (k *SigningKey) IsSymmetric() bool {
+	return k.Algorithm == AlgorithmHS256
+}
+
+// PublicJWK - public key dạng JWK (nil với key HMAC)
+func (k *SigningKey) PublicJWK() *JWK {
+	jwk := &JWK{Use: "sig", Kid: k.ID, Alg: k.Algorithm}
+
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeSegment(pub.N.Bytes())
+		jwk.E = encodeSegment(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = encodeSegment(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeSegment(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeSegment(pub)
+	default:
+		return nil
+	}
+
+	return jwk
+}
+
+// Thumbprint - JWK thumbprint theo RFC 7638 (SHA-256, base64url)
+func (k *SigningKey) Thumbprint() (string, error) {
+	jwk := k.PublicJWK()
+	if jwk == nil {
+		return "", errors.New("thumbprint is only defined for asymmetric keys")
+	}
+
+	// Chỉ gồm các member bắt buộc, sắp xếp theo thứ tự từ điển
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	raw, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return encodeSegment(sum[:]), nil
+}
+
+// encodeSegment - base64url không padding
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
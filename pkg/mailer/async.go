@@ -0,0 +1,151 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrQueueFull - hàng đợi email đầy
+	ErrQueueFull = errors.New("mail queue is full")
+	// ErrSenderClosed - sender đã đóng (đang shutdown)
+	ErrSenderClosed = errors.New("mail sender is closed")
+)
+
+// AsyncConfig - cấu hình gửi email bất đồng bộ
+type AsyncConfig struct {
+	Workers      int           // Số goroutine gửi song song
+	QueueSize    int           // Số email tối đa chờ gửi
+	MaxRetries   int           // Số lần thử lại khi gửi lỗi
+	RetryBackoff time.Duration // Thời gian chờ lần retry đầu (nhân đôi mỗi lần)
+	SendTimeout  time.Duration // Timeout cho mỗi lần gửi
+}
+
+// AsyncSender - đưa email vào hàng đợi, worker gửi nền và retry khi lỗi
+// HTTP request không phải chờ SMTP
+type AsyncSender struct {
+	sender Sender
+	logger *zap.Logger
+	cfg    AsyncConfig
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *Message
+	wg     sync.WaitGroup
+}
+
+// NewAsyncSender - tạo async sender và khởi động worker
+func NewAsyncSender(sender Sender, logger *zap.Logger, cfg AsyncConfig) *AsyncSender {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Second
+	}
+	if cfg.SendTimeout <= 0 {
+		cfg.SendTimeout = 30 * time.Second
+	}
+
+	s := &AsyncSender{
+		sender: sender,
+		logger: logger,
+		cfg:    cfg,
+		queue:  make(chan *Message, cfg.QueueSize),
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	return s
+}
+
+// Send - đưa email vào hàng đợi (không block)
+func (s *AsyncSender) Send(ctx context.Context, msg *Message) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return ErrSenderClosed
+	}
+
+	select {
+	case s.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close - ngừng nhận email mới và chờ gửi hết hàng đợi (hoặc ctx hết hạn)
+func (s *AsyncSender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// worker - lấy email từ hàng đợi và gửi
+func (s *AsyncSender) worker() {
+	defer s.wg.Done()
+	for msg := range s.queue {
+		s.deliver(msg)
+	}
+}
+
+// deliver - gửi 1 email, retry với exponential backoff
+func (s *AsyncSender) deliver(msg *Message) {
+	backoff := s.cfg.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), s.cfg.SendTimeout)
+		err := s.sender.Send(ctx, msg)
+		cancel()
+
+		if err == nil {
+			return
+		}
+
+		if attempt >= s.cfg.MaxRetries {
+			s.logger.Error("Failed to send email",
+				zap.String("to", msg.To),
+				zap.String("subject", msg.Subject),
+				zap.Int("attempts", attempt+1),
+				zap.Error(err),
+			)
+			return
+		}
+
+		s.logger.Warn("Email delivery failed, retrying",
+			zap.String("to", msg.To),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("retry_in", backoff),
+			zap.Error(err),
+		)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
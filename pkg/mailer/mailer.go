@@ -0,0 +1,240 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// Tên các template email
+const (
+	TemplatePasswordReset          = "password_reset"
+	TemplateEmailVerification      = "email_verification"
+	TemplateSecurityAlert          = "security_alert"
+	TemplateOrganizationInvitation = "organization_invitation"
+)
+
+// Message - 1 email đã render (text + HTML)
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender - interface gửi email (SMTP, file, log...)
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Service - interface render template và gửi email
+type Service interface {
+	Send(ctx context.Context, to, templateName string, data interface{}) error
+}
+
+// PasswordResetData - dữ liệu cho email reset password
+type PasswordResetData struct {
+	Name      string
+	Token     string
+	ExpiresIn time.Duration
+}
+
+// EmailVerificationData - dữ liệu cho email xác thực
+type EmailVerificationData struct {
+	Name      string
+	Token     string
+	ExpiresIn time.Duration
+}
+
+// SecurityAlertData - dữ liệu cho email cảnh báo bảo mật
+type SecurityAlertData struct {
+	Name      string
+	Event     string // Mô tả ngắn sự kiện, vd "Your password was changed"
+	IPAddress string
+	UserAgent string
+	Time      time.Time
+}
+
+// OrganizationInvitationData - dữ liệu cho email mời tham gia organization
+type OrganizationInvitationData struct {
+	OrganizationName string
+	InviterName      string
+	Role             string
+	Token            string
+	ExpiresIn        time.Duration
+}
+
+// templateContext - dữ liệu truyền vào template
+type templateContext struct {
+	AppName string
+	BaseURL string // URL frontend để tạo link trong email
+	Data    interface{}
+}
+
+// emailTemplate - cặp template text/HTML của 1 loại email
+type emailTemplate struct {
+	text *texttemplate.Template // Chứa block "subject"
+	html *htmltemplate.Template // layout.html + <name>.html
+}
+
+// mailService - implementation
+type mailService struct {
+	sender    Sender
+	appName   string
+	baseURL   string
+	templates map[string]*emailTemplate
+}
+
+// NewService - tạo mail service mới (parse template nhúng trong binary)
+func NewService(sender Sender, appName, baseURL string) (Service, error) {
+	s := &mailService{
+		sender:    sender,
+		appName:   appName,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		templates: make(map[string]*emailTemplate),
+	}
+
+	funcs := map[string]interface{}{"duration": formatDuration}
+	for _, name := range []string{TemplatePasswordReset, TemplateEmailVerification, TemplateSecurityAlert, TemplateOrganizationInvitation} {
+		text, err := texttemplate.New(name+".txt").Funcs(funcs).ParseFS(templateFS, "templates/"+name+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s text template: %w", name, err)
+		}
+		html, err := htmltemplate.New("layout.html").Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s html template: %w", name, err)
+		}
+		s.templates[name] = &emailTemplate{text: text, html: html}
+	}
+
+	return s, nil
+}
+
+// Send - render template và giao cho sender
+func (s *mailService) Send(ctx context.Context, to, templateName string, data interface{}) error {
+	msg, err := s.render(to, templateName, data)
+	if err != nil {
+		return err
+	}
+	return s.sender.Send(ctx, msg)
+}
+
+// render - tạo Message từ template
+func (s *mailService) render(to, templateName string, data interface{}) (*Message, error) {
+	tmpl, ok := s.templates[templateName]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template: %s", templateName)
+	}
+
+	tc := templateContext{AppName: s.appName, BaseURL: s.baseURL, Data: data}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", tc); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", templateName, err)
+	}
+	if err := tmpl.text.Execute(&text, tc); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %w", templateName, err)
+	}
+	if err := tmpl.html.Execute(&html, tc); err != nil {
+		return nil, fmt.Errorf("failed to render %s html: %w", templateName, err)
+	}
+
+	return &Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+// Bytes - encode message theo RFC 5322 (multipart/alternative text + HTML)
+func (m *Message) Bytes(from string) ([]byte, error) {
+	boundary, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	messageID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", messageID, domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", m.Text},
+		{"text/html", m.HTML},
+	} {
+		if part.body == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+// formatDuration - hiển thị thời hạn dễ đọc trong email ("1 hour", "24 hours", "30 minutes")
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return plural(int(d/time.Hour), "hour")
+	case d >= time.Minute:
+		return plural(int(d/time.Minute), "minute")
+	default:
+		return plural(int(d/time.Second), "second")
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
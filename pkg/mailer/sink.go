@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// logSender - chỉ ghi email ra log (dùng cho development)
+type logSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender - tạo sender ghi email ra log
+func NewLogSender(logger *zap.Logger) Sender {
+	return &logSender{logger: logger}
+}
+
+// Send - log nội dung text của email
+func (s *logSender) Send(ctx context.Context, msg *Message) error {
+	s.logger.Info("Email (log driver)",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Text),
+	)
+	return nil
+}
+
+// fileSender - lưu mỗi email thành 1 file .eml (mở được bằng mail client)
+type fileSender struct {
+	dir  string
+	from string
+}
+
+// NewFileSender - tạo sender ghi email vào thư mục
+func NewFileSender(dir, from string) (Sender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail dir: %w", err)
+	}
+	return &fileSender{dir: dir, from: from}, nil
+}
+
+// Send - ghi email ra file <thời gian>-<người nhận>.eml
+func (s *fileSender) Send(ctx context.Context, msg *Message) error {
+	body, err := msg.Bytes(s.from)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	suffix, err := randomHex(4)
+	if err != nil {
+		return err
+	}
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_", " ", "_").Replace(msg.To)
+	name := fmt.Sprintf("%s-%s-%s.eml", time.Now().Format("20060102-150405"), recipient, suffix)
+
+	return os.WriteFile(filepath.Join(s.dir, name), body, 0o644)
+}
@@ -0,0 +1,130 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// Chế độ TLS khi kết nối SMTP
+const (
+	SMTPTLSStartTLS = "starttls" // Nâng cấp bằng STARTTLS nếu server hỗ trợ (port 587)
+	SMTPTLSImplicit = "tls"      // TLS ngay từ đầu (port 465)
+	SMTPTLSNone     = "none"     // Không mã hóa (chỉ dùng cho dev, vd MailHog)
+)
+
+// SMTPConfig - cấu hình SMTP sender
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string // "Tên <email>" hoặc "email"
+	TLSMode  string
+	Timeout  time.Duration
+}
+
+// smtpSender - gửi email qua SMTP
+type smtpSender struct {
+	cfg      SMTPConfig
+	envelope string // Địa chỉ MAIL FROM (không có tên hiển thị)
+}
+
+// NewSMTPSender - tạo SMTP sender mới
+func NewSMTPSender(cfg SMTPConfig) (Sender, error) {
+	addr, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mail from address: %w", err)
+	}
+	if cfg.TLSMode == "" {
+		cfg.TLSMode = SMTPTLSStartTLS
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &smtpSender{cfg: cfg, envelope: addr.Address}, nil
+}
+
+// Send - gửi 1 email qua SMTP
+func (s *smtpSender) Send(ctx context.Context, msg *Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	body, err := msg.Bytes(s.cfg.From)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	// 1. Kết nối
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline := time.Now().Add(s.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	// 2. STARTTLS
+	if s.cfg.TLSMode == SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+				return fmt.Errorf("smtp starttls failed: %w", err)
+			}
+		}
+	}
+
+	// 3. Xác thực
+	if s.cfg.Username != "" {
+		auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	// 4. Gửi
+	if err := client.Mail(s.envelope); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// dial - mở kết nối TCP (hoặc TLS với chế độ implicit)
+func (s *smtpSender) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+	dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+
+	if s.cfg.TLSMode == SMTPTLSImplicit {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.cfg.Host}}
+		return tlsDialer.DialContext(ctx, "tcp", addr)
+	}
+	return dialer.DialContext(ctx, "tcp", addr)
+}
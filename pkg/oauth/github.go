@@ -0,0 +1,101 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Endpoint mặc định của github.com
+const (
+	githubAuthURL  = "https://github.com/login/oauth/authorize"
+	githubTokenURL = "https://github.com/login/oauth/access_token"
+	githubAPIURL   = "https://api.github.com"
+)
+
+// githubProvider - GitHub OAuth2 (GitHub không hỗ trợ OIDC cho đăng nhập user)
+type githubProvider struct {
+	cfg    ProviderConfig
+	client *http.Client
+}
+
+func newGitHubProvider(cfg ProviderConfig, client *http.Client) *githubProvider {
+	if cfg.AuthURL == "" {
+		cfg.AuthURL = githubAuthURL
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = githubTokenURL
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = githubAPIURL
+	}
+	cfg.APIURL = strings.TrimSuffix(cfg.APIURL, "/")
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"read:user", "user:email"}
+	}
+	return &githubProvider{cfg: cfg, client: client}
+}
+
+// Name - tên provider
+func (p *githubProvider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL - URL authorize của GitHub (nonce không dùng)
+func (p *githubProvider) AuthCodeURL(_ context.Context, req AuthRequest) (string, error) {
+	req.Nonce = ""
+	return buildAuthURL(p.cfg.AuthURL, p.cfg, p.cfg.Scopes, req)
+}
+
+// Exchange - đổi code lấy access token rồi đọc user và email đã xác thực qua REST API
+func (p *githubProvider) Exchange(ctx context.Context, code, codeVerifier, _ string) (*Identity, error) {
+	// 1. Đổi code lấy token
+	token, err := exchangeCode(ctx, p.client, p.cfg.TokenURL, p.cfg, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy user
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := getJSON(ctx, p.client, p.cfg.APIURL+"/user", token.AccessToken, &user); err != nil {
+		return nil, fmt.Errorf("failed to get github user: %w", err)
+	}
+	if user.ID == 0 {
+		return nil, errors.New("github user has no id")
+	}
+
+	identity := &Identity{
+		Provider: p.cfg.Name,
+		Subject:  strconv.FormatInt(user.ID, 10), // ID số không đổi, login đổi được
+		Name:     user.Name,
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+
+	// 3. Email chính đã xác thực (email công khai trên profile không chắc đã xác thực)
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, p.client, p.cfg.APIURL+"/user/emails", token.AccessToken, &emails); err != nil {
+		return nil, fmt.Errorf("failed to get github emails: %w", err)
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+			break
+		}
+	}
+
+	return identity, nil
+}
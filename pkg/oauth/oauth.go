@@ -0,0 +1,256 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Loại provider
+const (
+	ProviderTypeOIDC   = "oidc"   // OpenID Connect chuẩn (Google, Microsoft, Keycloak...), cấu hình qua discovery
+	ProviderTypeGitHub = "github" // GitHub OAuth2 (không có ID token, lấy thông tin qua REST API)
+)
+
+const maxResponseSize = 1 << 20 // Giới hạn body đọc từ provider (1 MB)
+
+var (
+	// ErrProviderNotFound - provider chưa được cấu hình
+	ErrProviderNotFound = errors.New("oauth provider not found")
+	// ErrInvalidIDToken - ID token sai chữ ký, issuer, audience, nonce hoặc hết hạn
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+// Identity - thông tin user do provider xác nhận
+type Identity struct {
+	Provider      string // Tên provider đã cấu hình (google, github...)
+	Subject       string // ID không đổi của user tại provider (claim "sub")
+	Email         string
+	EmailVerified bool // Provider xác nhận user sở hữu email
+	Name          string
+}
+
+// AuthRequest - tham số của 1 lần chuyển hướng sang provider
+type AuthRequest struct {
+	State         string // Chống CSRF, trả lại nguyên vẹn ở callback
+	Nonce         string // Gắn vào ID token, chống replay (chỉ OIDC)
+	CodeChallenge string // PKCE S256 của code verifier
+}
+
+// Provider - identity provider đăng nhập bằng authorization code + PKCE
+type Provider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, req AuthRequest) (string, error)                  // URL chuyển hướng user sang provider
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) // Đổi code lấy thông tin user
+}
+
+// ProviderConfig - cấu hình 1 provider
+type ProviderConfig struct {
+	Name         string
+	Type         string // oidc | github
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // URL callback đã đăng ký với provider
+	Scopes       []string // Rỗng -> scope mặc định của loại provider
+
+	Issuer string // OIDC: issuer, endpoint lấy qua {issuer}/.well-known/openid-configuration
+
+	// Ghi đè endpoint (GitHub Enterprise hoặc server giả lập khi test)
+	AuthURL  string
+	TokenURL string
+	APIURL   string // GitHub: REST API base URL
+}
+
+// NewProvider - tạo provider theo Type
+func NewProvider(cfg ProviderConfig, client *http.Client) (Provider, error) {
+	if cfg.Name == "" || cfg.ClientID == "" {
+		return nil, fmt.Errorf("oauth provider %q: name and client id are required", cfg.Name)
+	}
+	if cfg.RedirectURL == "" {
+		return nil, fmt.Errorf("oauth provider %q: redirect url is required", cfg.Name)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	switch cfg.Type {
+	case "", ProviderTypeOIDC:
+		return newOIDCProvider(cfg, client)
+	case ProviderTypeGitHub:
+		return newGitHubProvider(cfg, client), nil
+	default:
+		return nil, fmt.Errorf("oauth provider %q: unsupported type %q", cfg.Name, cfg.Type)
+	}
+}
+
+// Registry - danh sách provider đã cấu hình
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry - tạo registry từ danh sách provider
+func NewRegistry(providers ...Provider) *Registry {
+	registry := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, provider := range providers {
+		registry.providers[provider.Name()] = provider
+	}
+	return registry
+}
+
+// Get - lấy provider theo tên
+func (r *Registry) Get(name string) (Provider, error) {
+	if r != nil {
+		if provider, ok := r.providers[name]; ok {
+			return provider, nil
+		}
+	}
+	return nil, ErrProviderNotFound
+}
+
+// Names - tên các provider (sắp xếp)
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RandomString - chuỗi ngẫu nhiên base64url từ n bytes (dùng cho state, nonce, code verifier)
+func RandomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// GenerateCodeVerifier - PKCE code verifier (43 ký tự, RFC 7636)
+func GenerateCodeVerifier() (string, error) {
+	return RandomString(32)
+}
+
+// CodeChallengeS256 - PKCE code challenge = BASE64URL(SHA256(verifier))
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// buildAuthURL - thêm tham số authorization code + PKCE vào endpoint
+func buildAuthURL(endpoint string, cfg ProviderConfig, scopes []string, req AuthRequest) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+
+	query := parsed.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", cfg.ClientID)
+	query.Set("redirect_uri", cfg.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", req.State)
+	query.Set("code_challenge", req.CodeChallenge)
+	query.Set("code_challenge_method", "S256")
+	if req.Nonce != "" {
+		query.Set("nonce", req.Nonce)
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String(), nil
+}
+
+// tokenResponse - response của token endpoint
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchangeCode - gọi token endpoint (client_secret_post + code_verifier)
+func exchangeCode(ctx context.Context, client *http.Client, tokenURL string, cfg ProviderConfig, code, codeVerifier string) (*tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {cfg.RedirectURL},
+		"client_id":     {cfg.ClientID},
+		"client_secret": {cfg.ClientSecret},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	status, err := doJSON(client, req, &token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	// GitHub trả lỗi với status 200
+	if token.Error != "" {
+		return nil, fmt.Errorf("failed to exchange authorization code: %s: %s", token.Error, token.ErrorDescription)
+	}
+	if status != http.StatusOK || token.AccessToken == "" {
+		return nil, fmt.Errorf("failed to exchange authorization code: token endpoint returned status %d", status)
+	}
+
+	return &token, nil
+}
+
+// doJSON - gửi request và decode JSON response, trả về status code
+func doJSON(client *http.Client, req *http.Request, out interface{}) (int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("unexpected response (status %d): %w", resp.StatusCode, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// getJSON - GET kèm bearer token (rỗng -> không gửi Authorization)
+func getJSON(ctx context.Context, client *http.Client, endpoint, accessToken string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	status, err := doJSON(client, req, out)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", endpoint, err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", endpoint, status)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+// Package oauthtest - OpenID Connect provider giả lập cho test (discovery, JWKS, token endpoint)
+package oauthtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// KeyID - kid của key ký ID token
+	KeyID = "test-key"
+	// ClientSecret - secret client phải gửi ở token endpoint
+	ClientSecret = "test-secret"
+)
+
+// User - user đăng nhập ở provider
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// pendingCode - code đã cấp, chờ client đổi lấy token
+type pendingCode struct {
+	user          User
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// Server - provider giả lập chạy trên httptest.Server
+// Issuer = URL của server, ID token ký RS256 bằng key sinh lúc tạo server
+type Server struct {
+	*httptest.Server
+
+	// TamperClaims - sửa claims trước khi ký ID token (test issuer, audience, nonce, hạn sai...)
+	TamperClaims func(claims jwt.MapClaims)
+	// DiscoveryIssuer - issuer trả về trong discovery (rỗng = URL của server)
+	DiscoveryIssuer string
+	// SignWithClientSecret - ký ID token HS256 bằng client secret thay vì key RSA (test chặn HS256)
+	SignWithClientSecret bool
+
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]pendingCode
+	seq   int
+}
+
+// NewServer - chạy provider giả lập, tự đóng khi test kết thúc
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+
+	s := &Server{key: key, codes: make(map[string]pendingCode)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/jwks", s.handleJWKS)
+	mux.HandleFunc("/token", s.handleToken)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Issuer - issuer của provider
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// Authorize - giả lập user đăng nhập và đồng ý ở authorization endpoint
+// Trả về code và state mà provider sẽ gửi về redirect URI
+func (s *Server) Authorize(t testing.TB, authURL string, user User) (code, state string) {
+	t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid auth url: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("auth url is not an authorization code + PKCE S256 request: %s", authURL)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	code = "code-" + strconv.Itoa(s.seq)
+	s.codes[code] = pendingCode{
+		user:          user,
+		clientID:      query.Get("client_id"),
+		redirectURI:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	return code, query.Get("state")
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	issuer := s.DiscoveryIssuer
+	if issuer == "" {
+		issuer = s.URL
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                 issuer,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+// handleToken - đổi code lấy token: code dùng 1 lần, kiểm tra client, redirect URI và PKCE verifier
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	s.mu.Lock()
+	pending, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	switch {
+	case !ok:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "unknown or used code"})
+		return
+	case r.PostForm.Get("client_id") != pending.clientID || r.PostForm.Get("client_secret") != ClientSecret:
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	case r.PostForm.Get("redirect_uri") != pending.redirectURI:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "redirect_uri mismatch"})
+		return
+	case challengeS256(r.PostForm.Get("code_verifier")) != pending.codeChallenge:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "pkce verification failed"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            pending.user.Subject,
+		"aud":            pending.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          pending.nonce,
+		"email":          pending.user.Email,
+		"email_verified": pending.user.EmailVerified,
+		"name":           pending.user.Name,
+	}
+	if s.TamperClaims != nil {
+		s.TamperClaims(claims)
+	}
+
+	var (
+		idToken string
+		err     error
+	)
+	if s.SignWithClientSecret {
+		idToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(ClientSecret))
+	} else {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = KeyID
+		idToken, err = token.SignedString(s.key)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "access-" + pending.user.Subject,
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func challengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,274 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	jwksRefreshInterval = time.Minute // Tải lại JWKS (khi gặp kid lạ) tối đa 1 lần/phút
+	idTokenLeeway       = time.Minute // Cho phép lệch đồng hồ với provider
+)
+
+// Thuật toán chấp nhận cho ID token (không nhận HS256/none)
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// discoveryDocument - các field cần dùng của {issuer}/.well-known/openid-configuration
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// idTokenClaims - claims của ID token
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string   `json:"nonce"`
+	AuthorizedParty string   `json:"azp"`
+	Email           string   `json:"email"`
+	EmailVerified   flexBool `json:"email_verified"`
+	Name            string   `json:"name"`
+}
+
+// userInfoResponse - response của userinfo endpoint
+type userInfoResponse struct {
+	Subject       string   `json:"sub"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+// flexBool - một số provider trả email_verified dạng chuỗi "true"
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
+
+// oidcProvider - OpenID Connect provider, metadata và JWKS tải lười và cache lại
+type oidcProvider struct {
+	cfg    ProviderConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	metadata      *discoveryDocument
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+func newOIDCProvider(cfg ProviderConfig, client *http.Client) (*oidcProvider, error) {
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("oauth provider %q: issuer is required", cfg.Name)
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &oidcProvider{cfg: cfg, client: client}, nil
+}
+
+// Name - tên provider
+func (p *oidcProvider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL - URL authorization endpoint kèm state, nonce và PKCE challenge
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, req AuthRequest) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return buildAuthURL(metadata.AuthorizationEndpoint, p.cfg, p.cfg.Scopes, req)
+}
+
+// Exchange - đổi code lấy token, verify ID token và trả về identity
+func (p *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	// 1. Lấy metadata
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Đổi code lấy token
+	token, err := exchangeCode(ctx, p.client, metadata.TokenEndpoint, p.cfg, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	// 3. Verify ID token (chữ ký, issuer, audience, hạn, nonce)
+	claims, err := p.verifyIDToken(ctx, metadata, token.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Provider:      p.cfg.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}
+
+	// 4. ID token không có email -> hỏi userinfo endpoint
+	if identity.Email == "" && metadata.UserInfoEndpoint != "" {
+		var info userInfoResponse
+		if err := getJSON(ctx, p.client, metadata.UserInfoEndpoint, token.AccessToken, &info); err != nil {
+			return nil, fmt.Errorf("failed to get userinfo: %w", err)
+		}
+		if info.Subject != identity.Subject {
+			return nil, errors.New("userinfo subject does not match id token")
+		}
+		identity.Email = info.Email
+		identity.EmailVerified = bool(info.EmailVerified)
+		if identity.Name == "" {
+			identity.Name = info.Name
+		}
+	}
+
+	return identity, nil
+}
+
+// verifyIDToken - kiểm tra ID token theo OpenID Connect Core 3.1.3.7
+func (p *oidcProvider) verifyIDToken(ctx context.Context, metadata *discoveryDocument, raw, nonce string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.publicKey(ctx, metadata, kid)
+		},
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	// Token cấp cho nhiều audience thì azp phải là client này
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: unexpected authorized party", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// discover - tải metadata của provider (1 lần, lỗi thì lần sau thử lại)
+func (p *oidcProvider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	issuer := strings.TrimSuffix(p.cfg.Issuer, "/")
+	var metadata discoveryDocument
+	if err := getJSON(ctx, p.client, issuer+"/.well-known/openid-configuration", "", &metadata); err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider %q: %w", p.cfg.Name, err)
+	}
+
+	// Issuer trong metadata phải khớp issuer đã cấu hình (chống metadata giả)
+	if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc provider %q: issuer mismatch %q", p.cfg.Name, metadata.Issuer)
+	}
+	if p.cfg.AuthURL != "" {
+		metadata.AuthorizationEndpoint = p.cfg.AuthURL
+	}
+	if p.cfg.TokenURL != "" {
+		metadata.TokenEndpoint = p.cfg.TokenURL
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc provider %q: incomplete metadata", p.cfg.Name)
+	}
+
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// publicKey - lấy key verify theo kid, tải lại JWKS khi provider đã xoay key
+func (p *oidcProvider) publicKey(ctx context.Context, metadata *discoveryDocument, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysFetchedAt) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	keys, err := p.fetchKeys(ctx, metadata.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookupKey - token không có kid chỉ dùng được khi JWKS có đúng 1 key
+func (p *oidcProvider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *oidcProvider) fetchKeys(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks: status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	return parseJWKS(body)
+}
@@ -0,0 +1,193 @@
+package oauth_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/me/go-gin-auth/pkg/oauth"
+	"github.com/me/go-gin-auth/pkg/oauth/oauthtest"
+)
+
+const (
+	testClientID    = "test-client"
+	testRedirectURL = "https://app.example.com/oauth/callback"
+)
+
+var testUser = oauthtest.User{Subject: "user-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+
+// newTestProvider - provider OIDC trỏ tới server giả lập
+func newTestProvider(t *testing.T, server *oauthtest.Server) oauth.Provider {
+	t.Helper()
+
+	provider, err := oauth.NewProvider(oauth.ProviderConfig{
+		Name:         "test",
+		Type:         oauth.ProviderTypeOIDC,
+		ClientID:     testClientID,
+		ClientSecret: oauthtest.ClientSecret,
+		RedirectURL:  testRedirectURL,
+		Issuer:       server.Issuer(),
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	return provider
+}
+
+// authorize - tạo auth URL và giả lập user đồng ý, trả về code và PKCE verifier
+func authorize(t *testing.T, server *oauthtest.Server, provider oauth.Provider, nonce string) (string, string) {
+	t.Helper()
+
+	verifier, err := oauth.GenerateCodeVerifier()
+	if err != nil {
+		t.Fatalf("GenerateCodeVerifier: %v", err)
+	}
+	authURL, err := provider.AuthCodeURL(context.Background(), oauth.AuthRequest{
+		State:         "state-1",
+		Nonce:         nonce,
+		CodeChallenge: oauth.CodeChallengeS256(verifier),
+	})
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code, _ := server.Authorize(t, authURL, testUser)
+	return code, verifier
+}
+
+func TestOIDCAuthCodeURL(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	provider := newTestProvider(t, server)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), oauth.AuthRequest{
+		State:         "state-1",
+		Nonce:         "nonce-1",
+		CodeChallenge: oauth.CodeChallengeS256("verifier"),
+	})
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse auth url: %v", err)
+	}
+	if got := parsed.Scheme + "://" + parsed.Host + parsed.Path; got != server.URL+"/authorize" {
+		t.Errorf("endpoint = %q, want discovery authorization_endpoint", got)
+	}
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"scope":                 "openid email profile",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        oauth.CodeChallengeS256("verifier"),
+		"code_challenge_method": "S256",
+	}
+	query := parsed.Query()
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestOIDCExchange(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	provider := newTestProvider(t, server)
+	code, verifier := authorize(t, server, provider, "nonce-1")
+
+	identity, err := provider.Exchange(context.Background(), code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := oauth.Identity{Provider: "test", Subject: testUser.Subject, Email: testUser.Email, EmailVerified: true, Name: testUser.Name}
+	if *identity != want {
+		t.Errorf("identity = %+v, want %+v", *identity, want)
+	}
+
+	// Code chỉ dùng được 1 lần
+	if _, err := provider.Exchange(context.Background(), code, verifier, "nonce-1"); err == nil {
+		t.Error("Exchange with a used code succeeded")
+	}
+}
+
+func TestOIDCExchangeRejectsWrongCodeVerifier(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	provider := newTestProvider(t, server)
+	code, _ := authorize(t, server, provider, "nonce-1")
+
+	otherVerifier, _ := oauth.GenerateCodeVerifier()
+	_, err := provider.Exchange(context.Background(), code, otherVerifier, "nonce-1")
+	if err == nil || !strings.Contains(err.Error(), "pkce") {
+		t.Fatalf("Exchange error = %v, want pkce failure", err)
+	}
+}
+
+func TestOIDCExchangeRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		nonce  string // Nonce lưu phía client lúc bắt đầu
+		tamper func(claims jwt.MapClaims)
+		hs256  bool
+	}{
+		{name: "nonce mismatch", nonce: "other-nonce"},
+		{name: "missing nonce", nonce: "nonce-1", tamper: func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{name: "wrong audience", nonce: "nonce-1", tamper: func(c jwt.MapClaims) { c["aud"] = "another-client" }},
+		{name: "multiple audiences without azp", nonce: "nonce-1", tamper: func(c jwt.MapClaims) {
+			c["aud"] = []string{testClientID, "another-client"}
+		}},
+		{name: "multiple audiences with foreign azp", nonce: "nonce-1", tamper: func(c jwt.MapClaims) {
+			c["aud"] = []string{testClientID, "another-client"}
+			c["azp"] = "another-client"
+		}},
+		{name: "wrong issuer", nonce: "nonce-1", tamper: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", nonce: "nonce-1", tamper: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() }},
+		{name: "missing expiry", nonce: "nonce-1", tamper: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "issued in the future", nonce: "nonce-1", tamper: func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() }},
+		{name: "missing subject", nonce: "nonce-1", tamper: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "hs256 signed with client secret", nonce: "nonce-1", hs256: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := oauthtest.NewServer(t)
+			server.TamperClaims = tt.tamper
+			server.SignWithClientSecret = tt.hs256
+			provider := newTestProvider(t, server)
+			code, verifier := authorize(t, server, provider, "nonce-1")
+
+			_, err := provider.Exchange(context.Background(), code, verifier, tt.nonce)
+			if !errors.Is(err, oauth.ErrInvalidIDToken) {
+				t.Fatalf("Exchange error = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestOIDCExchangeAcceptsClockSkew(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	// Hết hạn 30 giây trước, vẫn trong leeway 1 phút
+	server.TamperClaims = func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-30 * time.Second).Unix() }
+	provider := newTestProvider(t, server)
+	code, verifier := authorize(t, server, provider, "nonce-1")
+
+	if _, err := provider.Exchange(context.Background(), code, verifier, "nonce-1"); err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+}
+
+func TestOIDCDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	server := oauthtest.NewServer(t)
+	server.DiscoveryIssuer = "https://evil.example.com"
+	provider := newTestProvider(t, server)
+
+	_, err := provider.AuthCodeURL(context.Background(), oauth.AuthRequest{State: "s", CodeChallenge: "c"})
+	if err == nil || !strings.Contains(err.Error(), "issuer mismatch") {
+		t.Fatalf("AuthCodeURL error = %v, want issuer mismatch", err)
+	}
+}
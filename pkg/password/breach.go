@@ -0,0 +1,261 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// BreachChecker - interface kiểm tra password đã bị lộ trong các vụ rò rỉ dữ liệu
+type BreachChecker interface {
+	BreachCount(password string) (int, error) // Số lần xuất hiện trong dữ liệu rò rỉ (0 = chưa bị lộ)
+}
+
+// File Pwned Passwords (bản tải về, sắp xếp theo hash) có dạng mỗi dòng:
+//
+//	<SHA-1 hex in hoa>:<số lần xuất hiện>
+//
+// File rất lớn (hàng chục GB) nên không load vào memory mà binary search trực tiếp trên đĩa.
+// File index (<file>.idx, tạo bằng BuildBreachIndex) lưu offset của từng prefix 5 ký tự hex
+// để thu hẹp vùng tìm kiếm còn vài KB.
+const (
+	sha1HexLen       = 40
+	indexPrefixBits  = 20 // 5 ký tự hex
+	indexEntries     = 1<<indexPrefixBits + 1
+	indexMagic       = "PWNIDX01"
+	indexHeaderSize  = 16 // magic + kích thước file dữ liệu
+	breachReadBuffer = 128
+)
+
+// fileBreachChecker - tra cứu trong file Pwned Passwords trên đĩa
+type fileBreachChecker struct {
+	data  *os.File
+	size  int64
+	index *os.File // nil nếu không có index hợp lệ
+}
+
+// NewFileBreachChecker - mở file Pwned Passwords (dùng <path>.idx nếu có và còn khớp)
+func NewFileBreachChecker(path string) (BreachChecker, error) {
+	data, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password file: %w", err)
+	}
+	info, err := data.Stat()
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+
+	c := &fileBreachChecker{data: data, size: info.Size()}
+
+	// Index chỉ dùng được khi tạo từ đúng file dữ liệu hiện tại
+	if index, err := os.Open(path + ".idx"); err == nil {
+		if size, err := readIndexHeader(index); err == nil && size == c.size {
+			c.index = index
+		} else {
+			index.Close()
+		}
+	}
+
+	return c, nil
+}
+
+// BreachCount - tìm SHA-1 của password trong file
+func (c *fileBreachChecker) BreachCount(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	target := []byte(strings.ToUpper(hex.EncodeToString(sum[:])))
+
+	lo, hi := int64(0), c.size
+	if c.index != nil {
+		prefix := binary.BigEndian.Uint32(append([]byte{0}, sum[:3]...)) >> 4
+		var err error
+		if lo, hi, err = c.indexRange(prefix); err != nil {
+			return 0, err
+		}
+	}
+
+	return c.search(lo, hi, target)
+}
+
+// indexRange - vùng byte [lo, hi) chứa các hash có prefix này
+func (c *fileBreachChecker) indexRange(prefix uint32) (int64, int64, error) {
+	buf := make([]byte, 16)
+	if _, err := c.index.ReadAt(buf, indexHeaderSize+int64(prefix)*8); err != nil {
+		return 0, 0, fmt.Errorf("failed to read breached password index: %w", err)
+	}
+	return int64(binary.LittleEndian.Uint64(buf[:8])), int64(binary.LittleEndian.Uint64(buf[8:])), nil
+}
+
+// search - binary search các dòng bắt đầu trong [lo, hi) (lo luôn là đầu 1 dòng)
+func (c *fileBreachChecker) search(lo, hi int64, target []byte) (int, error) {
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		// Tìm đầu dòng đầu tiên tại hoặc sau mid
+		lineStart := lo
+		if mid > lo {
+			next, err := c.nextLineStart(mid - 1)
+			if err != nil {
+				return 0, err
+			}
+			if next >= hi {
+				// Không có dòng nào bắt đầu trong [mid, hi)
+				hi = mid
+				continue
+			}
+			lineStart = next
+		}
+
+		line, next, err := c.readLine(lineStart)
+		if err != nil {
+			return 0, err
+		}
+		if len(line) < sha1HexLen {
+			return 0, errors.New("malformed breached password file")
+		}
+
+		switch cmp := bytes.Compare(line[:sha1HexLen], target); {
+		case cmp == 0:
+			count, _ := strconv.Atoi(string(bytes.TrimSpace(line[sha1HexLen+1:])))
+			if count < 1 {
+				count = 1
+			}
+			return count, nil
+		case cmp < 0:
+			lo = next
+		default:
+			hi = lineStart
+		}
+	}
+
+	return 0, nil
+}
+
+// nextLineStart - vị trí ngay sau ký tự '\n' đầu tiên tính từ pos
+func (c *fileBreachChecker) nextLineStart(pos int64) (int64, error) {
+	buf := make([]byte, breachReadBuffer)
+	for pos < c.size {
+		n, err := c.data.ReadAt(buf, pos)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return pos + int64(i) + 1, nil
+		}
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return 0, fmt.Errorf("failed to read breached password file: %w", err)
+		}
+		pos += int64(n)
+	}
+	return c.size, nil
+}
+
+// readLine - đọc 1 dòng bắt đầu tại pos, trả về nội dung (không có \r\n) và vị trí dòng kế tiếp
+func (c *fileBreachChecker) readLine(pos int64) ([]byte, int64, error) {
+	buf := make([]byte, breachReadBuffer)
+	n, err := c.data.ReadAt(buf, pos)
+	if err != nil && err != io.EOF {
+		return nil, 0, fmt.Errorf("failed to read breached password file: %w", err)
+	}
+
+	line := buf[:n]
+	next := pos + int64(n)
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+		next = pos + int64(i) + 1
+	}
+	return bytes.TrimRight(line, "\r"), next, nil
+}
+
+// BuildBreachIndex - quét file Pwned Passwords 1 lần và ghi <path>.idx
+// Index gồm header (magic + kích thước file) và offset bắt đầu của từng prefix 20 bit
+func BuildBreachIndex(path string) error {
+	data, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open breached password file: %w", err)
+	}
+	defer data.Close()
+
+	info, err := data.Stat()
+	if err != nil {
+		return err
+	}
+
+	// 1. Ghi nhận offset dòng đầu tiên của mỗi prefix
+	offsets := make([]int64, indexEntries)
+	for i := range offsets {
+		offsets[i] = -1
+	}
+
+	reader := bufio.NewReaderSize(data, 1<<20)
+	var pos int64
+	for {
+		line, err := reader.ReadSlice('\n')
+		if len(line) >= 5 {
+			prefix, perr := strconv.ParseUint(string(line[:5]), 16, 32)
+			if perr != nil {
+				return fmt.Errorf("malformed line at offset %d", pos)
+			}
+			if offsets[prefix] < 0 {
+				offsets[prefix] = pos
+			}
+		}
+		pos += int64(len(line))
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read breached password file: %w", err)
+		}
+	}
+
+	// 2. Prefix không có dòng nào -> vùng rỗng (trỏ tới prefix kế tiếp)
+	offsets[indexEntries-1] = info.Size()
+	for i := indexEntries - 2; i >= 0; i-- {
+		if offsets[i] < 0 {
+			offsets[i] = offsets[i+1]
+		}
+	}
+
+	// 3. Ghi file tạm rồi rename để không đọc phải index ghi dở
+	tmp := path + ".idx.tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create index file: %w", err)
+	}
+	w := bufio.NewWriter(out)
+	w.WriteString(indexMagic)
+	binary.Write(w, binary.LittleEndian, uint64(info.Size()))
+	for _, off := range offsets {
+		binary.Write(w, binary.LittleEndian, uint64(off))
+	}
+	if err := w.Flush(); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write index file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path+".idx")
+}
+
+// readIndexHeader - kiểm tra magic và trả về kích thước file dữ liệu đã index
+func readIndexHeader(f *os.File) (int64, error) {
+	buf := make([]byte, indexHeaderSize)
+	if _, err := f.ReadAt(buf, 0); err != nil {
+		return 0, err
+	}
+	if string(buf[:len(indexMagic)]) != indexMagic {
+		return 0, errors.New("invalid breached password index")
+	}
+	return int64(binary.LittleEndian.Uint64(buf[len(indexMagic):])), nil
+}
@@ -1,36 +1,177 @@
 package password
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Thuật toán hash password
+const (
+	AlgorithmArgon2id = "argon2id" // Mặc định
+	AlgorithmBcrypt   = "bcrypt"   // Legacy (cắt password dài hơn 72 bytes)
+)
+
+const (
+	argon2SaltSize = 16
+	argon2KeySize  = 32
+)
+
 // Service - interface cho password operations
 type Service interface {
 	HashPassword(password string) (string, error)
 	CheckPassword(password, hashedPassword string) bool
+	NeedsRehash(hashedPassword string) bool // Hash dùng thuật toán/tham số cũ -> nên hash lại
+}
+
+// Params - tham số hash password
+type Params struct {
+	Algorithm         string // argon2id | bcrypt, dùng cho hash mới
+	BcryptCost        int    // Độ mạnh bcrypt (12 = mạnh, 4 = yếu)
+	Argon2Memory      uint32 // Bộ nhớ argon2id (KiB)
+	Argon2Iterations  uint32 // Số vòng lặp argon2id
+	Argon2Parallelism uint8  // Số luồng argon2id
 }
 
 // passwordService - implementation
+// Hash lưu theo PHC string format ($argon2id$v=19$m=...,t=...,p=...$salt$hash),
+// bcrypt ($2a$/$2b$/$2y$) vẫn verify được cho các hash cũ
 type passwordService struct {
-	cost int // Độ mạnh mã hóa (12 = mạnh, 4 = yếu)
+	params Params
 }
 
 // NewPasswordService - tạo password service mới
-func NewPasswordService(cost int) Service {
-	return &passwordService{cost: cost}
+func NewPasswordService(params Params) Service {
+	if params.Algorithm == "" {
+		params.Algorithm = AlgorithmArgon2id
+	}
+	if params.BcryptCost == 0 {
+		params.BcryptCost = bcrypt.DefaultCost
+	}
+	if params.Argon2Memory == 0 {
+		params.Argon2Memory = 64 * 1024
+	}
+	if params.Argon2Iterations == 0 {
+		params.Argon2Iterations = 3
+	}
+	if params.Argon2Parallelism == 0 {
+		params.Argon2Parallelism = 2
+	}
+	return &passwordService{params: params}
 }
 
-// HashPassword - mã hóa password
+// HashPassword - mã hóa password bằng thuật toán đang cấu hình
 func (s *passwordService) HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
-	if err != nil {
+	if s.params.Algorithm == AlgorithmBcrypt {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), s.params.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashedPassword), nil
+	}
+
+	salt := make([]byte, argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
-	return string(hashedPassword), nil
+
+	p := argon2Params{
+		memory:      s.params.Argon2Memory,
+		iterations:  s.params.Argon2Iterations,
+		parallelism: s.params.Argon2Parallelism,
+	}
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, argon2KeySize)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
-// CheckPassword - kiểm tra password có đúng không
+// CheckPassword - kiểm tra password có đúng không (tự nhận diện thuật toán từ hash)
 func (s *passwordService) CheckPassword(password, hashedPassword string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil // true nếu đúng, false nếu sai
+	if isBcrypt(hashedPassword) {
+		err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+		return err == nil // true nếu đúng, false nếu sai
+	}
+
+	p, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return false
+	}
+	other := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+// NeedsRehash - true nếu hash không khớp thuật toán hoặc tham số hiện tại
+func (s *passwordService) NeedsRehash(hashedPassword string) bool {
+	if isBcrypt(hashedPassword) {
+		if s.params.Algorithm != AlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost != s.params.BcryptCost
+	}
+
+	if s.params.Algorithm != AlgorithmArgon2id {
+		return true
+	}
+	p, _, _, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return true
+	}
+	return p.memory != s.params.Argon2Memory ||
+		p.iterations != s.params.Argon2Iterations ||
+		p.parallelism != s.params.Argon2Parallelism
+}
+
+// argon2Params - tham số đọc từ PHC string
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// decodeArgon2id - parse PHC string của argon2id
+func decodeArgon2id(encoded string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return p, nil, nil, errors.New("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errors.New("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id params: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errors.New("invalid argon2id key")
+	}
+
+	return p, salt, key, nil
+}
+
+// isBcrypt - hash bcrypt bắt đầu bằng $2a$, $2b$ hoặc $2y$
+func isBcrypt(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") ||
+		strings.HasPrefix(hashedPassword, "$2b$") ||
+		strings.HasPrefix(hashedPassword, "$2y$")
 }
@@ -0,0 +1,343 @@
+package password
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tên các rule trong PolicyViolation
+const (
+	RuleMinLength  = "min_length"
+	RuleMaxLength  = "max_length"
+	RuleUppercase  = "uppercase"
+	RuleLowercase  = "lowercase"
+	RuleDigit      = "digit"
+	RuleSymbol     = "symbol"
+	RuleBannedWord = "banned_word"
+	RuleUserInfo   = "user_info"
+	RuleStrength   = "strength"
+	RuleBreached   = "breached"
+	RuleReused     = "reused" // Trùng password dùng gần đây (kiểm tra ở usecase)
+)
+
+// PolicyConfig - cấu hình password policy
+type PolicyConfig struct {
+	MinLength     int      // Số ký tự tối thiểu
+	MaxLength     int      // Số ký tự tối đa (0 = không giới hạn)
+	RequireUpper  bool     // Bắt buộc có chữ hoa
+	RequireLower  bool     // Bắt buộc có chữ thường
+	RequireDigit  bool     // Bắt buộc có chữ số
+	RequireSymbol bool     // Bắt buộc có ký tự đặc biệt
+	BannedWords   []string // Từ cấm thêm (ngoài danh sách password phổ biến có sẵn)
+	CheckUserInfo bool     // Không cho chứa email / họ tên của user
+	MinScore      int      // Điểm độ mạnh tối thiểu (0-4, giống thang zxcvbn)
+
+	BreachChecker  BreachChecker // Tra cứu password đã bị lộ (nil = tắt)
+	BreachMinCount int           // Số lần xuất hiện tối thiểu để coi là đã bị lộ
+}
+
+// PolicyViolation - 1 rule không đạt
+type PolicyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PolicyError - password không đạt policy (chứa danh sách rule vi phạm)
+type PolicyError struct {
+	Violations []PolicyViolation
+}
+
+// Error - implement error interface
+func (e *PolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return "password does not meet policy: " + strings.Join(messages, "; ")
+}
+
+// Policy - kiểm tra password theo cấu hình
+type Policy struct {
+	cfg         PolicyConfig
+	bannedWords []string // Lowercase, gồm danh sách phổ biến + BannedWords
+}
+
+// NewPolicy - tạo password policy mới
+func NewPolicy(cfg PolicyConfig) *Policy {
+	words := make([]string, 0, len(commonPasswords)+len(cfg.BannedWords))
+	words = append(words, commonPasswords...)
+	for _, w := range cfg.BannedWords {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			words = append(words, w)
+		}
+	}
+	return &Policy{cfg: cfg, bannedWords: words}
+}
+
+// Validate - kiểm tra password, trả về *PolicyError nếu có rule không đạt
+// userInputs: email, họ tên... của user để kiểm tra độ tương đồng
+// Lỗi khác *PolicyError nghĩa là không tra cứu được dữ liệu password bị lộ
+func (p *Policy) Validate(password string, userInputs ...string) error {
+	var violations []PolicyViolation
+	add := func(rule, format string, args ...interface{}) {
+		violations = append(violations, PolicyViolation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// 1. Độ dài
+	// Quá dài -> dừng ngay, các bước sau tốn CPU theo độ dài password (tránh DoS bằng password rất dài)
+	length := utf8.RuneCountInString(password)
+	if p.cfg.MaxLength > 0 && length > p.cfg.MaxLength {
+		add(RuleMaxLength, "password must be at most %d characters", p.cfg.MaxLength)
+		return &PolicyError{Violations: violations}
+	}
+	if length < p.cfg.MinLength {
+		add(RuleMinLength, "password must be at least %d characters", p.cfg.MinLength)
+	}
+
+	// 2. Loại ký tự
+	classes := characterClasses(password)
+	if p.cfg.RequireUpper && !classes.upper {
+		add(RuleUppercase, "password must contain an uppercase letter")
+	}
+	if p.cfg.RequireLower && !classes.lower {
+		add(RuleLowercase, "password must contain a lowercase letter")
+	}
+	if p.cfg.RequireDigit && !classes.digit {
+		add(RuleDigit, "password must contain a digit")
+	}
+	if p.cfg.RequireSymbol && !classes.symbol {
+		add(RuleSymbol, "password must contain a symbol")
+	}
+
+	// 3. Từ cấm (so sánh sau khi bỏ leetspeak: p@ssw0rd -> password)
+	normalized := unleet(strings.ToLower(password))
+	for _, word := range p.bannedWords {
+		// Từ ngắn chỉ cấm khi trùng khớp, tránh chặn nhầm password dài có chứa nó
+		if len(word) >= 5 && strings.Contains(normalized, word) || normalized == word {
+			add(RuleBannedWord, "password contains a common or banned word")
+			break
+		}
+	}
+
+	// 4. Thông tin cá nhân
+	userTokens := userInfoTokens(userInputs)
+	if p.cfg.CheckUserInfo {
+		for _, token := range userTokens {
+			if strings.Contains(normalized, token) {
+				add(RuleUserInfo, "password must not contain your name or email")
+				break
+			}
+		}
+	}
+
+	// 5. Độ mạnh
+	if score := p.Score(password, userInputs...); score < p.cfg.MinScore {
+		add(RuleStrength, "password is too weak (strength %d/4, need at least %d)", score, p.cfg.MinScore)
+	}
+
+	// 6. Password đã bị lộ trong các vụ rò rỉ dữ liệu
+	if p.cfg.BreachChecker != nil {
+		count, err := p.cfg.BreachChecker.BreachCount(password)
+		if err != nil {
+			return fmt.Errorf("failed to check breached passwords: %w", err)
+		}
+		if count > 0 && count >= p.cfg.BreachMinCount {
+			add(RuleBreached, "password has appeared in a data breach, please choose a different one")
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// Score - ước lượng độ mạnh 0-4 theo số lần đoán (cùng ngưỡng với zxcvbn)
+// Từ điển, lặp ký tự, chuỗi liên tiếp và phím cạnh nhau được tính ít entropy hơn ký tự ngẫu nhiên
+func (p *Policy) Score(password string, userInputs ...string) int {
+	bits := p.entropy(password, userInfoTokens(userInputs))
+
+	// log2 của 10^3, 10^6, 10^8, 10^10 lần đoán
+	switch {
+	case bits < 10:
+		return 0
+	case bits < 20:
+		return 1
+	case bits < 26.6:
+		return 2
+	case bits < 33.2:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// entropy - ước lượng số bit entropy của password
+func (p *Policy) entropy(password string, userTokens []string) float64 {
+	lower := []rune(strings.ToLower(password))
+	unleeted := []rune(unleet(strings.ToLower(password)))
+	charsetBits := math.Log2(float64(characterClasses(password).size()))
+
+	// Từ điển: password phổ biến + từ cấm + thông tin user
+	dictionary := append(append([]string{}, p.bannedWords...), userTokens...)
+	dictionaryBits := math.Log2(float64(len(dictionary) + 1))
+
+	var bits float64
+	for i := 0; i < len(lower); {
+		// 1. Từ trong từ điển dài nhất bắt đầu tại i
+		if n := longestMatch(unleeted[i:], dictionary); n > 0 {
+			bits += dictionaryBits + 1 // +1 bit cho biến thể hoa/thường, leetspeak
+			i += n
+			continue
+		}
+
+		// 2. Ký tự dễ đoán dựa vào ký tự trước
+		if i > 0 {
+			prev, cur := lower[i-1], lower[i]
+			switch {
+			case cur == prev:
+				bits++ // Lặp lại: aaaa
+				i++
+				continue
+			case cur == prev+1 || cur == prev-1:
+				bits += 1.5 // Chuỗi liên tiếp: abcd, 4321
+				i++
+				continue
+			case keyboardAdjacent(prev, cur):
+				bits += 2 // Phím cạnh nhau: qwerty, asdf
+				i++
+				continue
+			}
+		}
+
+		// 3. Ký tự ngẫu nhiên
+		bits += charsetBits
+		i++
+	}
+
+	return bits
+}
+
+// charClasses - các loại ký tự có trong password
+type charClasses struct {
+	upper, lower, digit, symbol bool
+}
+
+func characterClasses(password string) charClasses {
+	var c charClasses
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			c.upper = true
+		case unicode.IsLower(r):
+			c.lower = true
+		case unicode.IsDigit(r):
+			c.digit = true
+		default:
+			c.symbol = true
+		}
+	}
+	return c
+}
+
+// size - số ký tự có thể có theo các loại đang dùng
+func (c charClasses) size() int {
+	size := 0
+	if c.lower {
+		size += 26
+	}
+	if c.upper {
+		size += 26
+	}
+	if c.digit {
+		size += 10
+	}
+	if c.symbol {
+		size += 33
+	}
+	if size == 0 {
+		return 1
+	}
+	return size
+}
+
+// userInfoTokens - tách email/họ tên thành các từ (>= 3 ký tự, lowercase)
+func userInfoTokens(inputs []string) []string {
+	var tokens []string
+	for _, input := range inputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if input == "" {
+			continue
+		}
+		if addr, err := mail.ParseAddress(input); err == nil {
+			input = addr.Address[:strings.LastIndex(addr.Address, "@")]
+		}
+		for _, token := range strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		}) {
+			if utf8.RuneCountInString(token) >= 3 {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+// longestMatch - độ dài (rune) của từ dài nhất trong dictionary là prefix của s
+func longestMatch(s []rune, dictionary []string) int {
+	best := 0
+	for _, word := range dictionary {
+		n := utf8.RuneCountInString(word)
+		if n < 3 || n > len(s) || n <= best {
+			continue
+		}
+		if string(s[:n]) == word {
+			best = n
+		}
+	}
+	return best
+}
+
+// unleet - đổi leetspeak thông dụng về chữ cái
+func unleet(s string) string {
+	return leetReplacer.Replace(s)
+}
+
+var leetReplacer = strings.NewReplacer(
+	"@", "a", "4", "a", "8", "b", "3", "e", "6", "g", "1", "i", "!", "i",
+	"0", "o", "$", "s", "5", "s", "7", "t", "+", "t", "2", "z",
+)
+
+// keyboardAdjacent - 2 ký tự nằm cạnh nhau trên bàn phím QWERTY
+func keyboardAdjacent(a, b rune) bool {
+	for _, row := range keyboardRows {
+		i := strings.IndexRune(row, a)
+		j := strings.IndexRune(row, b)
+		if i >= 0 && j >= 0 && (i-j == 1 || j-i == 1) {
+			return true
+		}
+	}
+	return false
+}
+
+var keyboardRows = []string{"1234567890-=", "qwertyuiop[]", "asdfghjkl;'", "zxcvbnm,./"}
+
+// commonPasswords - các password/từ phổ biến nhất (đã unleet, lowercase)
+var commonPasswords = []string{
+	"password", "passwd", "qwerty", "azerty", "letmein", "welcome", "admin", "administrator",
+	"login", "master", "monkey", "dragon", "football", "baseball", "soccer", "hockey",
+	"iloveyou", "princess", "sunshine", "shadow", "superman", "batman", "trustno", "whatever",
+	"starwars", "pokemon", "freedom", "secret", "changeme", "default", "access", "hello",
+	"charlie", "michael", "jennifer", "jordan", "hunter", "ranger", "buster", "thomas",
+	"tigger", "robert", "killer", "george", "andrew", "pepper", "ginger",
+	"summer", "winter", "spring", "autumn", "flower", "cookie", "chocolate", "banana",
+	"computer", "internet", "service", "server", "database", "root", "toor", "guest",
+	"test", "testing", "user", "demo", "sample", "example", "abc", "abcd", "qazwsx",
+	"zaq", "asdf", "zxcv", "matrix", "mustang", "harley", "corvette", "ferrari",
+	"lovely", "angel", "baby", "blink", "family", "friend", "forever", "heaven",
+	"liverpool", "chelsea", "arsenal", "barcelona", "samsung", "apple", "google", "facebook",
+}
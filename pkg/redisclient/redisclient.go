@@ -0,0 +1,306 @@
+// Package redisclient - client Redis tối thiểu (giao thức RESP) cho các store dùng chung giữa nhiều instance
+// Chỉ cần chạy Lua script (EVAL), không phụ thuộc thư viện ngoài
+package redisclient
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPoolSize = 10              // Số connection rảnh giữ lại
+	dialTimeout     = 5 * time.Second // Timeout kết nối
+	ioTimeout       = 3 * time.Second // Timeout mỗi lệnh khi context không có deadline
+)
+
+// Evaler - client Redis tối thiểu mà các store cần (chạy Lua script)
+// Với go-redis: wrap client.Eval(ctx, script, keys, args...).Result()
+type Evaler interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// Error - lỗi Redis trả về (vd. lỗi trong Lua script), connection vẫn dùng tiếp được
+type Error string
+
+// Error - implement error interface
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Client - client Redis có pool connection, an toàn khi dùng đồng thời
+type Client struct {
+	addr      string
+	username  string
+	password  string
+	db        int
+	tlsConfig *tls.Config // nil -> không dùng TLS
+	idle      chan *conn
+}
+
+// conn - 1 connection tới Redis
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// New - tạo client từ URL: redis://[[user]:password@]host[:port][/db] hoặc rediss:// (TLS)
+// Chưa kết nối ngay, dùng Ping để kiểm tra cấu hình lúc khởi động
+func New(rawURL string) (*Client, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	if parsed.Scheme != "redis" && parsed.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid redis url scheme %q, want redis or rediss", parsed.Scheme)
+	}
+	if parsed.Hostname() == "" {
+		return nil, errors.New("invalid redis url: missing host")
+	}
+
+	c := &Client{addr: parsed.Host, idle: make(chan *conn, defaultPoolSize)}
+	if parsed.Port() == "" {
+		c.addr = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if parsed.User != nil {
+		c.username = parsed.User.Username()
+		c.password, _ = parsed.User.Password()
+	}
+	if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		c.db, err = strconv.Atoi(db)
+		if err != nil || c.db < 0 {
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+	}
+	if parsed.Scheme == "rediss" {
+		c.tlsConfig = &tls.Config{ServerName: parsed.Hostname(), MinVersion: tls.VersionTLS12}
+	}
+	return c, nil
+}
+
+// Eval - chạy Lua script, trả về kết quả đã decode (int64, string, []interface{} hoặc nil)
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	cmd := make([]interface{}, 0, 3+len(keys)+len(args))
+	cmd = append(cmd, "EVAL", script, len(keys))
+	for _, key := range keys {
+		cmd = append(cmd, key)
+	}
+	cmd = append(cmd, args...)
+	return c.do(ctx, cmd...)
+}
+
+// Ping - kiểm tra kết nối và xác thực
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Close - đóng các connection đang rảnh
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do - gửi 1 lệnh và đọc kết quả
+// Lỗi mạng / giao thức -> bỏ connection; lỗi Redis (Error) -> trả connection về pool
+func (c *Client) do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.roundTrip(ctx, args)
+	var redisErr Error
+	if err != nil && !errors.As(err, &redisErr) {
+		cn.Close()
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// get - lấy connection rảnh hoặc kết nối mới
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+		return c.dial(ctx)
+	}
+}
+
+// put - trả connection về pool (pool đầy thì đóng)
+func (c *Client) put(cn *conn) {
+	select {
+	case c.idle <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+// dial - kết nối, AUTH và SELECT database
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var (
+		netConn net.Conn
+		err     error
+	)
+	if c.tlsConfig != nil {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: c.tlsConfig}).DialContext(ctx, "tcp", c.addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	cn := &conn{Conn: netConn, r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+	if c.password != "" {
+		auth := []interface{}{"AUTH", c.password}
+		if c.username != "" {
+			auth = []interface{}{"AUTH", c.username, c.password}
+		}
+		if _, err := cn.roundTrip(ctx, auth); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("failed to authenticate to redis: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.roundTrip(ctx, []interface{}{"SELECT", c.db}); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("failed to select redis database: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+// roundTrip - ghi lệnh dạng RESP array và đọc 1 reply
+func (cn *conn) roundTrip(ctx context.Context, args []interface{}) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(ioTimeout)
+	}
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(cn.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		value, err := formatArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(cn.w, "$%d\r\n%s\r\n", len(value), value)
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write redis command: %w", err)
+	}
+
+	return readReply(cn.r)
+}
+
+// formatArg - đổi tham số sang bulk string
+func formatArg(arg interface{}) (string, error) {
+	switch v := arg.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported redis argument type %T", arg)
+	}
+}
+
+// readReply - đọc 1 reply RESP
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redis reply: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("invalid redis reply: empty line")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis integer reply: %w", err)
+		}
+		return n, nil
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk reply: %w", err)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("failed to read redis reply: %w", err)
+		}
+		return string(buf[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis array reply: %w", err)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		// Đọc hết phần tử kể cả khi có phần tử lỗi, để connection còn dùng tiếp được
+		items := make([]interface{}, count)
+		var itemErr error
+		for i := range items {
+			item, err := readReply(r)
+			var redisErr Error
+			if errors.As(err, &redisErr) {
+				if itemErr == nil {
+					itemErr = err
+				}
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		if itemErr != nil {
+			return nil, itemErr
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("invalid redis reply type %q", line[0])
+	}
+}
@@ -65,3 +65,21 @@ func Forbidden(c *gin.Context, message string) {
 		Code:    "FORBIDDEN",
 	})
 }
+
+// PayloadTooLarge - trả về lỗi request body vượt quá giới hạn
+func PayloadTooLarge(c *gin.Context, message string) {
+	c.JSON(http.StatusRequestEntityTooLarge, Response{
+		Success: false,
+		Message: message,
+		Code:    "PAYLOAD_TOO_LARGE",
+	})
+}
+
+// TooManyRequests - trả về lỗi vượt quá rate limit
+func TooManyRequests(c *gin.Context, message string) {
+	c.JSON(http.StatusTooManyRequests, Response{
+		Success: false,
+		Message: message,
+		Code:    "RATE_LIMITED",
+	})
+}
@@ -0,0 +1,474 @@
+package revocation
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/me/go-gin-auth/pkg/jwt"
+	"github.com/me/go-gin-auth/pkg/redisclient"
+)
+
+// Store - lưu access token đã bị thu hồi trước khi hết hạn (memory, Redis...)
+// Gồm 4 loại entry, tự hết hạn khi token tương ứng không còn dùng được:
+//   - jti của từng token (logout, /oauth/revoke)
+//   - mốc thời gian theo user: token cấp trước mốc không còn hiệu lực (reset password, admin đăng xuất mọi thiết bị)
+//   - mốc thời gian theo session: như trên nhưng chỉ cho token của 1 session (đăng xuất 1 thiết bị, refresh token bị dùng lại)
+//   - mốc thời gian theo OAuth client: token cấp cho client trước mốc (client bị xóa, đổi secret, thu hẹp scope)
+type Store interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error                           // Thu hồi 1 token đến khi nó hết hạn
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)                                     // Token đã bị thu hồi chưa
+	RevokeUserTokens(ctx context.Context, userID uint, before time.Time, ttl time.Duration) error         // Thu hồi token của user cấp trước before, giữ mốc trong ttl
+	UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error)                          // Mốc thu hồi của user (zero = chưa có)
+	RevokeSessionTokens(ctx context.Context, sessionID string, before time.Time, ttl time.Duration) error // Thu hồi token của session cấp trước before, giữ mốc trong ttl
+	SessionTokensRevokedBefore(ctx context.Context, sessionID string) (time.Time, error)                  // Mốc thu hồi của session (zero = chưa có)
+	RevokeClientTokens(ctx context.Context, clientID string, before time.Time, ttl time.Duration) error   // Thu hồi token cấp cho client trước before, giữ mốc trong ttl
+	ClientTokensRevokedBefore(ctx context.Context, clientID string) (time.Time, error)                    // Mốc thu hồi của client (zero = chưa có)
+}
+
+// IsAccessTokenRevoked - access token bị thu hồi theo jti hoặc được cấp trước (hoặc cùng giây với) mốc thu hồi của client / user / session
+// Store lỗi -> trả kết quả đã kiểm tra được kèm lỗi để caller tự quyết định
+func IsAccessTokenRevoked(ctx context.Context, store Store, claims *jwt.AccessClaims) (bool, error) {
+	var firstErr error
+
+	// 1. Theo jti (token cũ chưa có jti thì bỏ qua)
+	if claims.ID != "" {
+		revoked, err := store.IsTokenRevoked(ctx, claims.ID)
+		if revoked {
+			return true, nil
+		}
+		firstErr = err
+	}
+
+	if claims.IssuedAt == nil {
+		return false, firstErr
+	}
+
+	// 2. Theo mốc thu hồi của OAuth client (token service và token user cấp cho client)
+	if claims.ClientID != "" {
+		before, err := store.ClientTokensRevokedBefore(ctx, claims.ClientID)
+		if firstErr == nil {
+			firstErr = err
+		}
+		if !before.IsZero() && !claims.IssuedAt.Time.After(before) {
+			return true, nil
+		}
+	}
+
+	// 3. Theo mốc thu hồi của user và session (token service không gắn user)
+	if claims.IsService() || claims.UserID == 0 {
+		return false, firstErr
+	}
+	before, err := store.UserTokensRevokedBefore(ctx, claims.UserID)
+	if firstErr == nil {
+		firstErr = err
+	}
+	if !before.IsZero() && !claims.IssuedAt.Time.After(before) {
+		return true, nil
+	}
+
+	if claims.SessionID == "" {
+		return false, firstErr
+	}
+	before, err = store.SessionTokensRevokedBefore(ctx, claims.SessionID)
+	if firstErr == nil {
+		firstErr = err
+	}
+	if !before.IsZero() && !claims.IssuedAt.Time.After(before) {
+		return true, nil
+	}
+	return false, firstErr
+}
+
+// truncateWatermark - mốc thu hồi làm tròn xuống giây vì iat của JWT chỉ có độ chính xác giây
+// Token có iat bằng mốc cũng bị thu hồi: không phân biệt được token cấp trước hay sau khi thu hồi trong cùng giây đó,
+// nên chọn thu hồi (token cấp ngay sau khi thu hồi phải refresh lại, thay vì token cũ còn dùng được)
+func truncateWatermark(before time.Time) time.Time {
+	return before.Truncate(time.Second)
+}
+
+// tokenKey, userKey, sessionKey, clientKey - key của 4 loại entry
+func tokenKey(tokenID string) string     { return "jti:" + tokenID }
+func userKey(userID uint) string         { return "user:" + strconv.FormatUint(uint64(userID), 10) }
+func sessionKey(sessionID string) string { return "session:" + sessionID }
+func clientKey(clientID string) string   { return "client:" + clientID }
+
+const (
+	// DefaultCapacity - số entry tối đa mặc định của store trong memory
+	DefaultCapacity = 100000
+	// sweepInterval - khoảng thời gian tối thiểu giữa 2 lần dọn entry hết hạn
+	sweepInterval = time.Minute
+)
+
+// entry - 1 jti hoặc mốc thu hồi của user / session / client
+type entry struct {
+	key       string
+	before    time.Time // Mốc thu hồi (chỉ dùng cho entry của user / session / client)
+	expiresAt time.Time
+}
+
+// memoryStore - danh sách thu hồi trong memory, LRU giới hạn số entry
+// Entry hết hạn cùng token tương ứng; khi đầy thì xóa entry đã hết hạn trước, sau đó entry ít dùng nhất.
+// Chạy 1 instance: capacity phải lớn hơn số token bị thu hồi trong 1 access TTL (entry bị đẩy ra thì token dùng lại được).
+// Chạy nhiều instance: store này chỉ là cache phía trước Redis, entry bị đẩy ra vẫn còn trên Redis
+type memoryStore struct {
+	mu        sync.Mutex
+	capacity  int
+	items     map[string]*list.Element
+	order     *list.List // Mới dùng ở đầu, ít dùng ở cuối
+	lastSweep time.Time
+}
+
+// NewMemoryStore - tạo revocation store trong memory tối đa capacity entry (<= 0 dùng DefaultCapacity)
+func NewMemoryStore(capacity int) Store {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &memoryStore{
+		capacity:  capacity,
+		items:     make(map[string]*list.Element),
+		order:     list.New(),
+		lastSweep: time.Now(),
+	}
+}
+
+// RevokeToken - thêm jti vào danh sách thu hồi
+func (s *memoryStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	s.set(tokenKey(tokenID), time.Time{}, expiresAt)
+	return nil
+}
+
+// IsTokenRevoked - jti có trong danh sách thu hồi và chưa hết hạn
+func (s *memoryStore) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	_, ok := s.get(tokenKey(tokenID))
+	return ok, nil
+}
+
+// RevokeUserTokens - nâng mốc thu hồi của user (không bao giờ lùi mốc)
+func (s *memoryStore) RevokeUserTokens(ctx context.Context, userID uint, before time.Time, ttl time.Duration) error {
+	s.set(userKey(userID), truncateWatermark(before), time.Now().Add(ttl))
+	return nil
+}
+
+// UserTokensRevokedBefore - mốc thu hồi của user
+func (s *memoryStore) UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error) {
+	e, ok := s.get(userKey(userID))
+	if !ok {
+		return time.Time{}, nil
+	}
+	return e.before, nil
+}
+
+// RevokeSessionTokens - nâng mốc thu hồi của session (không bao giờ lùi mốc)
+func (s *memoryStore) RevokeSessionTokens(ctx context.Context, sessionID string, before time.Time, ttl time.Duration) error {
+	s.set(sessionKey(sessionID), truncateWatermark(before), time.Now().Add(ttl))
+	return nil
+}
+
+// SessionTokensRevokedBefore - mốc thu hồi của session
+func (s *memoryStore) SessionTokensRevokedBefore(ctx context.Context, sessionID string) (time.Time, error) {
+	e, ok := s.get(sessionKey(sessionID))
+	if !ok {
+		return time.Time{}, nil
+	}
+	return e.before, nil
+}
+
+// RevokeClientTokens - nâng mốc thu hồi của client (không bao giờ lùi mốc)
+func (s *memoryStore) RevokeClientTokens(ctx context.Context, clientID string, before time.Time, ttl time.Duration) error {
+	s.set(clientKey(clientID), truncateWatermark(before), time.Now().Add(ttl))
+	return nil
+}
+
+// ClientTokensRevokedBefore - mốc thu hồi của client
+func (s *memoryStore) ClientTokensRevokedBefore(ctx context.Context, clientID string) (time.Time, error) {
+	e, ok := s.get(clientKey(clientID))
+	if !ok {
+		return time.Time{}, nil
+	}
+	return e.before, nil
+}
+
+// set - thêm hoặc cập nhật entry (giữ mốc và hạn lớn hơn)
+func (s *memoryStore) set(key string, before, expiresAt time.Time) {
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		e := el.Value.(*entry)
+		if before.After(e.before) {
+			e.before = before
+		}
+		if expiresAt.After(e.expiresAt) {
+			e.expiresAt = expiresAt
+		}
+		s.order.MoveToFront(el)
+		return
+	}
+
+	// Đầy -> dọn entry hết hạn, vẫn đầy thì bỏ entry ít dùng nhất
+	if s.order.Len() >= s.capacity {
+		s.sweep(now, true)
+		for s.order.Len() >= s.capacity {
+			s.remove(s.order.Back())
+		}
+	} else {
+		s.sweep(now, false)
+	}
+	s.items[key] = s.order.PushFront(&entry{key: key, before: before, expiresAt: expiresAt})
+}
+
+// get - lấy entry còn hạn
+func (s *memoryStore) get(key string) (entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return entry{}, false
+	}
+	e := el.Value.(*entry)
+	if !e.expiresAt.After(time.Now()) {
+		s.remove(el)
+		return entry{}, false
+	}
+	s.order.MoveToFront(el)
+	return *e, true
+}
+
+// sweep - xóa các entry đã hết hạn (tối đa 1 lần mỗi sweepInterval trừ khi force, gọi khi đang giữ lock)
+func (s *memoryStore) sweep(now time.Time, force bool) {
+	if !force && now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for el := s.order.Back(); el != nil; {
+		prev := el.Prev()
+		if !el.Value.(*entry).expiresAt.After(now) {
+			s.remove(el)
+		}
+		el = prev
+	}
+}
+
+// remove - xóa 1 entry (gọi khi đang giữ lock)
+func (s *memoryStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.items, el.Value.(*entry).key)
+}
+
+// Lua script cho Redis store
+const (
+	redisSetScript    = `redis.call("SET", KEYS[1], "1", "PX", ARGV[1]) return 1`
+	redisExistsScript = `return redis.call("EXISTS", KEYS[1])`
+
+	// Chỉ nâng mốc, không ghi đè mốc mới hơn của instance khác
+	redisRaiseScript = `
+local current = tonumber(redis.call("GET", KEYS[1]) or "0")
+if tonumber(ARGV[1]) > current then
+  redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+else
+  redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 1
+`
+	redisGetScript = `return tonumber(redis.call("GET", KEYS[1]) or "0")`
+)
+
+// redisStore - danh sách thu hồi trên Redis (dùng chung giữa nhiều instance)
+type redisStore struct {
+	client redisclient.Evaler
+	prefix string
+}
+
+// NewRedisStore - tạo revocation store dùng Redis
+func NewRedisStore(client redisclient.Evaler, prefix string) Store {
+	return &redisStore{client: client, prefix: prefix}
+}
+
+// RevokeToken - SET jti với TTL đến lúc token hết hạn
+func (s *redisStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	if _, err := s.client.Eval(ctx, redisSetScript, []string{s.prefix + tokenKey(tokenID)}, ttl.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked - jti còn tồn tại trên Redis
+func (s *redisStore) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	raw, err := s.client.Eval(ctx, redisExistsScript, []string{s.prefix + tokenKey(tokenID)})
+	if err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	n, ok := raw.(int64)
+	if !ok {
+		return false, fmt.Errorf("unexpected revocation script result: %v", raw)
+	}
+	return n > 0, nil
+}
+
+// RevokeUserTokens - nâng mốc thu hồi của user (unix giây)
+func (s *redisStore) RevokeUserTokens(ctx context.Context, userID uint, before time.Time, ttl time.Duration) error {
+	if err := s.raise(ctx, userKey(userID), before, ttl); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// UserTokensRevokedBefore - mốc thu hồi của user trên Redis
+func (s *redisStore) UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error) {
+	before, err := s.watermark(ctx, userKey(userID))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get user revocation: %w", err)
+	}
+	return before, nil
+}
+
+// RevokeSessionTokens - nâng mốc thu hồi của session (unix giây)
+func (s *redisStore) RevokeSessionTokens(ctx context.Context, sessionID string, before time.Time, ttl time.Duration) error {
+	if err := s.raise(ctx, sessionKey(sessionID), before, ttl); err != nil {
+		return fmt.Errorf("failed to revoke session tokens: %w", err)
+	}
+	return nil
+}
+
+// SessionTokensRevokedBefore - mốc thu hồi của session trên Redis
+func (s *redisStore) SessionTokensRevokedBefore(ctx context.Context, sessionID string) (time.Time, error) {
+	before, err := s.watermark(ctx, sessionKey(sessionID))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get session revocation: %w", err)
+	}
+	return before, nil
+}
+
+// RevokeClientTokens - nâng mốc thu hồi của client (unix giây)
+func (s *redisStore) RevokeClientTokens(ctx context.Context, clientID string, before time.Time, ttl time.Duration) error {
+	if err := s.raise(ctx, clientKey(clientID), before, ttl); err != nil {
+		return fmt.Errorf("failed to revoke client tokens: %w", err)
+	}
+	return nil
+}
+
+// ClientTokensRevokedBefore - mốc thu hồi của client trên Redis
+func (s *redisStore) ClientTokensRevokedBefore(ctx context.Context, clientID string) (time.Time, error) {
+	before, err := s.watermark(ctx, clientKey(clientID))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get client revocation: %w", err)
+	}
+	return before, nil
+}
+
+// raise - nâng mốc thu hồi của key, giữ trong ttl
+func (s *redisStore) raise(ctx context.Context, key string, before time.Time, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	_, err := s.client.Eval(ctx, redisRaiseScript, []string{s.prefix + key}, truncateWatermark(before).Unix(), ttl.Milliseconds())
+	return err
+}
+
+// watermark - đọc mốc thu hồi của key (zero = chưa có)
+func (s *redisStore) watermark(ctx context.Context, key string) (time.Time, error) {
+	raw, err := s.client.Eval(ctx, redisGetScript, []string{s.prefix + key})
+	if err != nil {
+		return time.Time{}, err
+	}
+	n, ok := raw.(int64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected revocation script result: %v", raw)
+	}
+	if n == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(n, 0), nil
+}
+
+// tieredStore - store memory local phía trước store dùng chung
+// Ghi vào cả 2; đọc local trước (thu hồi do chính instance này thực hiện), sau đó tới store dùng chung
+type tieredStore struct {
+	local  Store
+	shared Store
+}
+
+// NewStore - revocation store của service: memory tối đa capacity entry,
+// shared != nil -> dùng chung thêm store đó (Redis khi chạy nhiều instance)
+func NewStore(shared Store, capacity int) Store {
+	local := NewMemoryStore(capacity)
+	if shared == nil {
+		return local
+	}
+	return &tieredStore{local: local, shared: shared}
+}
+
+// RevokeToken - ghi vào cả local và shared
+func (s *tieredStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	_ = s.local.RevokeToken(ctx, tokenID, expiresAt)
+	return s.shared.RevokeToken(ctx, tokenID, expiresAt)
+}
+
+// IsTokenRevoked - local có thì không cần hỏi shared
+func (s *tieredStore) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if revoked, _ := s.local.IsTokenRevoked(ctx, tokenID); revoked {
+		return true, nil
+	}
+	return s.shared.IsTokenRevoked(ctx, tokenID)
+}
+
+// RevokeUserTokens - ghi vào cả local và shared
+func (s *tieredStore) RevokeUserTokens(ctx context.Context, userID uint, before time.Time, ttl time.Duration) error {
+	_ = s.local.RevokeUserTokens(ctx, userID, before, ttl)
+	return s.shared.RevokeUserTokens(ctx, userID, before, ttl)
+}
+
+// UserTokensRevokedBefore - mốc mới hơn giữa local và shared (instance khác có thể đã nâng mốc)
+func (s *tieredStore) UserTokensRevokedBefore(ctx context.Context, userID uint) (time.Time, error) {
+	local, _ := s.local.UserTokensRevokedBefore(ctx, userID)
+	shared, err := s.shared.UserTokensRevokedBefore(ctx, userID)
+	return latest(local, shared), err
+}
+
+// RevokeSessionTokens - ghi vào cả local và shared
+func (s *tieredStore) RevokeSessionTokens(ctx context.Context, sessionID string, before time.Time, ttl time.Duration) error {
+	_ = s.local.RevokeSessionTokens(ctx, sessionID, before, ttl)
+	return s.shared.RevokeSessionTokens(ctx, sessionID, before, ttl)
+}
+
+// SessionTokensRevokedBefore - mốc mới hơn giữa local và shared
+func (s *tieredStore) SessionTokensRevokedBefore(ctx context.Context, sessionID string) (time.Time, error) {
+	local, _ := s.local.SessionTokensRevokedBefore(ctx, sessionID)
+	shared, err := s.shared.SessionTokensRevokedBefore(ctx, sessionID)
+	return latest(local, shared), err
+}
+
+// RevokeClientTokens - ghi vào cả local và shared
+func (s *tieredStore) RevokeClientTokens(ctx context.Context, clientID string, before time.Time, ttl time.Duration) error {
+	_ = s.local.RevokeClientTokens(ctx, clientID, before, ttl)
+	return s.shared.RevokeClientTokens(ctx, clientID, before, ttl)
+}
+
+// ClientTokensRevokedBefore - mốc mới hơn giữa local và shared
+func (s *tieredStore) ClientTokensRevokedBefore(ctx context.Context, clientID string) (time.Time, error) {
+	local, _ := s.local.ClientTokensRevokedBefore(ctx, clientID)
+	shared, err := s.shared.ClientTokensRevokedBefore(ctx, clientID)
+	return latest(local, shared), err
+}
+
+// latest - mốc mới hơn trong 2 mốc
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,50 @@
+package tokenhash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Service - interface băm token trước khi lưu database
+// DB chỉ chứa digest nên bị lộ dump cũng không dùng lại được token
+type Service interface {
+	Hash(token string) string         // Digest để lưu và tra cứu
+	Candidates(token string) []string // Các digest có thể khớp (digest hiện tại trước, sau đó digest SHA-256 cũ)
+}
+
+// tokenHashService - implementation
+type tokenHashService struct {
+	pepper []byte // Secret phía server, rỗng -> chỉ dùng SHA-256
+}
+
+// NewTokenHashService - tạo token hash service mới
+// pepper khác rỗng -> HMAC-SHA256(pepper, token), ngược lại SHA-256(token)
+func NewTokenHashService(pepper string) Service {
+	return &tokenHashService{pepper: []byte(pepper)}
+}
+
+// Hash - tính digest (hex) của token
+func (s *tokenHashService) Hash(token string) string {
+	if len(s.pepper) == 0 {
+		return SHA256(token)
+	}
+	mac := hmac.New(sha256.New, s.pepper)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Candidates - digest hiện tại và digest SHA-256 không pepper
+// Row cũ được migration chuyển sang SHA2(token, 256) nên vẫn tra cứu được sau khi bật pepper
+func (s *tokenHashService) Candidates(token string) []string {
+	if len(s.pepper) == 0 {
+		return []string{SHA256(token)}
+	}
+	return []string{s.Hash(token), SHA256(token)}
+}
+
+// SHA256 - digest SHA-256 dạng hex (giống SHA2(token, 256) của MySQL)
+func SHA256(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"strings"
+	"unicode/utf8"
 )
 
 // Contains - kiểm tra string có trong slice không
@@ -15,6 +17,19 @@ func Contains(slice []string, item string) bool {
 	return false
 }
 
+// Truncate - cắt chuỗi tối đa maxBytes byte, không cắt giữa 1 ký tự UTF-8
+// Byte không phải UTF-8 hợp lệ bị bỏ trước khi cắt (tránh lỗi khi lưu vào cột utf8mb4)
+func Truncate(s string, maxBytes int) string {
+	s = strings.ToValidUTF8(s, "")
+	if len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+	return s[:maxBytes]
+}
+
 // GenerateRandomString - tạo random string
 func GenerateRandomString(length int) (string, error) {
 	bytes := make([]byte, length/2)
@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Header gửi kèm mỗi webhook
+const (
+	HeaderSignature  = "X-Webhook-Signature"   // t=<unix>,v1=<hex HMAC-SHA256>
+	HeaderEvent      = "X-Webhook-Event"       // Loại sự kiện
+	HeaderEventID    = "X-Webhook-Event-Id"    // ID sự kiện (bên nhận dùng để chống xử lý trùng)
+	HeaderDeliveryID = "X-Webhook-Delivery-Id" // ID lần gửi
+)
+
+const (
+	signatureVersion    = "v1"
+	maxResponseBodySize = 1024 // Chỉ giữ 1 KB đầu của response để ghi log
+)
+
+var (
+	// ErrInvalidSignature - chữ ký không khớp hoặc sai định dạng
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrSignatureExpired - timestamp trong chữ ký quá cũ (chống replay)
+	ErrSignatureExpired = errors.New("webhook signature timestamp is outside the tolerance")
+	// ErrForbiddenAddress - endpoint trỏ vào loopback, mạng nội bộ hoặc link-local (chống SSRF)
+	ErrForbiddenAddress = errors.New("webhook endpoint address is not a public address")
+)
+
+// Dải địa chỉ không phải unicast public nhưng net/netip không có hàm kiểm tra riêng
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This network"
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // Reserved, broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64 (trỏ ngược vào IPv4 bất kỳ)
+}
+
+// IsPublicAddress - địa chỉ được phép gửi webhook tới
+// Chặn loopback, private (RFC 1918 / ULA), link-local (gồm metadata 169.254.169.254), multicast và dải dành riêng
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialControl - kiểm tra IP thật ngay lúc kết nối (sau khi resolve DNS)
+// Kiểm tra lúc đăng ký URL là chưa đủ: DNS của endpoint có thể đổi sang IP nội bộ sau đó (DNS rebinding)
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return ErrForbiddenAddress
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublicAddress(addr) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// Sign - HMAC-SHA256 của "<timestamp>.<body>" (hex)
+// Đưa timestamp vào nội dung ký để bên nhận từ chối request bị gửi lại sau này
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader - giá trị header X-Webhook-Signature
+func SignatureHeader(secret string, timestamp int64, body []byte) string {
+	return fmt.Sprintf("t=%d,%s=%s", timestamp, signatureVersion, Sign(secret, timestamp, body))
+}
+
+// Verify - kiểm tra header chữ ký (dùng ở bên nhận webhook)
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			timestamp = t
+		case signatureVersion:
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+
+	expected := Sign(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Request - 1 lần gửi webhook
+type Request struct {
+	URL        string
+	Secret     string
+	EventID    string
+	EventType  string
+	DeliveryID string
+	Body       []byte
+}
+
+// Result - kết quả gửi (StatusCode = 0 nếu không nhận được response)
+type Result struct {
+	StatusCode   int
+	ResponseBody string
+	Duration     time.Duration
+	Err          error
+}
+
+// OK - endpoint trả về 2xx
+func (r *Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Client - gửi webhook qua HTTP
+type Client interface {
+	Send(ctx context.Context, req *Request) *Result
+}
+
+// httpClient - implementation
+type httpClient struct {
+	client    *http.Client
+	userAgent string
+}
+
+// NewClient - tạo webhook client
+// Chỉ kết nối tới IP public, không follow redirect và không đi qua proxy (proxy sẽ bỏ qua kiểm tra IP)
+func NewClient(timeout time.Duration, userAgent string) Client {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := &net.Dialer{Timeout: timeout, Control: dialControl}
+	return &httpClient{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent: userAgent,
+	}
+}
+
+// Send - POST body đã ký tới endpoint
+func (c *httpClient) Send(ctx context.Context, req *Request) *Result {
+	start := time.Now()
+	result := &Result{}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	httpReq.Header.Set(HeaderEvent, req.EventType)
+	httpReq.Header.Set(HeaderEventID, req.EventID)
+	httpReq.Header.Set(HeaderDeliveryID, req.DeliveryID)
+	httpReq.Header.Set(HeaderSignature, SignatureHeader(req.Secret, start.Unix(), req.Body))
+
+	resp, err := c.client.Do(httpReq)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20)) // Đọc hết để tái sử dụng kết nối
+
+	result.StatusCode = resp.StatusCode
+	result.ResponseBody = strings.ToValidUTF8(string(body), "\uFFFD") // Cột TEXT utf8mb4 không nhận byte lỗi
+	if !result.OK() {
+		result.Err = fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return result
+}
@@ -29,7 +29,13 @@ func main() {
 	}
 
 	// Create services
-	passwordService := password.NewPasswordService(cfg.Security.BcryptCost)
+	passwordService := password.NewPasswordService(password.Params{
+		Algorithm:         cfg.Security.PasswordHashAlgorithm,
+		BcryptCost:        cfg.Security.BcryptCost,
+		Argon2Memory:      cfg.Security.Argon2Memory,
+		Argon2Iterations:  cfg.Security.Argon2Iterations,
+		Argon2Parallelism: cfg.Security.Argon2Parallelism,
+	})
 	userRepo := repository.NewUserRepository(db)
 
 	// Get admin credentials from env or use defaults